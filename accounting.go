@@ -0,0 +1,114 @@
+package gum
+
+import (
+	"fmt"
+	"time"
+)
+
+// AccountingRecord is the usage record of a unit instance. Go doesn't
+// account CPU time per goroutine, so records carry wall-clock time only.
+type AccountingRecord struct {
+	Unit     UnitInfo
+	Start    time.Time
+	Stop     time.Time     // Zero when clocking in
+	Duration time.Duration // Zero when clocking in
+
+	// Restarts is the number of instances of the unit started before this
+	// one, e.g. by SwapUnit or WithRecycle.
+	Restarts int
+
+	// State is the final state of the instance when clocking out.
+	State     UnitState
+	Abandoned bool // Still running when the shutdown was forced
+}
+
+// Accounting receives the usage records of the units, e.g. to meter tenant
+// workers. Records are delivered synchronously and must not block.
+type Accounting interface {
+	ClockIn(rec AccountingRecord)
+	ClockOut(rec AccountingRecord)
+}
+
+// AccountingFuncs adapts a pair of functions to the Accounting interface.
+type AccountingFuncs struct {
+	ClockInFunc  func(rec AccountingRecord)
+	ClockOutFunc func(rec AccountingRecord)
+}
+
+// ClockIn calls ClockInFunc if set.
+func (a AccountingFuncs) ClockIn(rec AccountingRecord) {
+	if a.ClockInFunc != nil {
+		a.ClockInFunc(rec)
+	}
+}
+
+// ClockOut calls ClockOutFunc if set.
+func (a AccountingFuncs) ClockOut(rec AccountingRecord) {
+	if a.ClockOutFunc != nil {
+		a.ClockOutFunc(rec)
+	}
+}
+
+// WithAccounting adds an accounting hook. Every unit instance clocks in when
+// it is started and clocks out once, when it is done or abandoned.
+func WithAccounting(a Accounting) Option {
+	return func(m *Manager) {
+		if a == nil {
+			m.invalid(fmt.Errorf("nil accounting hook"))
+			return
+		}
+		m.accounting = append(m.accounting, a)
+	}
+}
+
+// clockIn counts the start of the unit and delivers its clock-in record.
+func (m *Manager) clockIn(w *WorkUnitManager) {
+	m.regMu.Lock()
+	w.restarts = m.starts[w.lineage]
+	m.starts[w.lineage]++
+	if m.accounting == nil {
+		m.regMu.Unlock()
+		return
+	}
+	rec := AccountingRecord{
+		Start:    w.startedAt,
+		Restarts: w.restarts,
+		State:    w.state,
+	}
+	m.regMu.Unlock()
+	rec.Unit = w.Info()
+
+	for _, a := range m.accounting {
+		a := a // Left running on timeout
+		m.callHook("accounting", w.name, func() { a.ClockIn(rec) })
+	}
+}
+
+// clockOut delivers the clock-out record of a done or abandoned unit. It is
+// a no-op past the first call for a unit.
+func (m *Manager) clockOut(w *WorkUnitManager, abandoned bool) {
+	if m.accounting == nil || !w.clockedOut.CompareAndSwap(false, true) {
+		return
+	}
+
+	stop := m.clock.Now()
+	m.regMu.RLock()
+	rec := AccountingRecord{
+		Start:     w.startedAt,
+		Stop:      stop,
+		Duration:  stop.Sub(w.startedAt),
+		Restarts:  w.restarts,
+		State:     w.state,
+		Abandoned: abandoned,
+	}
+	if !w.stoppedAt.IsZero() {
+		rec.Stop, rec.Duration = w.stoppedAt, w.stoppedAt.Sub(w.startedAt)
+	}
+	m.regMu.RUnlock()
+	rec.Unit = w.Info()
+
+	for _, a := range m.accounting {
+		a := a
+		m.callHook("accounting", w.name, func() { a.ClockOut(rec) })
+	}
+}
@@ -0,0 +1,85 @@
+package gum
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+// ledger records the accounting records
+type ledger struct {
+	mu  sync.Mutex
+	in  []AccountingRecord
+	out []AccountingRecord
+}
+
+func (l *ledger) ClockIn(rec AccountingRecord) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.in = append(l.in, rec)
+}
+
+func (l *ledger) ClockOut(rec AccountingRecord) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.out = append(l.out, rec)
+}
+
+func TestAccounting(t *testing.T) {
+	l := &ledger{}
+	manager := NewManager(WithAccounting(l))
+	labels := WithLabels(map[string]string{"tenant": "a"})
+	manager.AddUnit(&readyWorker{}, "tenant", labels)
+
+	go manager.Run()
+	waitState(t, manager, 0, Running)
+	if err := manager.SwapUnit(context.Background(), manager.order[0].name, &readyWorker{}, labels); err != nil {
+		t.Fatal(err)
+	}
+	manager.Stop()
+	<-manager.Quit
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.in) != 2 || len(l.out) != 2 {
+		t.Fatalf("expected 2 clock-ins and 2 clock-outs, got %d and %d", len(l.in), len(l.out))
+	}
+	for i, rec := range l.in {
+		if rec.Restarts != i {
+			t.Errorf("expected %d restarts, got %d", i, rec.Restarts)
+		}
+		if rec.Unit.Labels["tenant"] != "a" {
+			t.Errorf("unexpected unit %+v", rec.Unit)
+		}
+	}
+	for _, rec := range l.out {
+		if rec.State != Stopped || rec.Abandoned {
+			t.Errorf("unexpected clock-out %+v", rec)
+		}
+		if rec.Duration <= 0 || !rec.Stop.Equal(rec.Start.Add(rec.Duration)) {
+			t.Errorf("inconsistent clock-out duration %+v", rec)
+		}
+	}
+}
+
+func TestAccountingAbandoned(t *testing.T) {
+	l := &ledger{}
+	manager := NewManager(WithAccounting(l), WithShutdownTimeout(20*time.Millisecond))
+	manager.ShutdownOn(os.Interrupt)
+	manager.AddUnit(&stuckWorker{}, "")
+
+	go manager.Run()
+	waitState(t, manager, 0, Running)
+	manager.signalIn <- os.Interrupt
+	<-manager.Quit
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.out) != 1 || !l.out[0].Abandoned || l.out[0].State != Stopping {
+		t.Fatalf("expected an abandoned clock-out, got %+v", l.out)
+	}
+}
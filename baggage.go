@@ -0,0 +1,100 @@
+package gum
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+// WithBaggage attaches metadata to the manager, e.g. a run ID or deployment
+// information, to correlate everything from one process run. The baggage is
+// prefixed to the manager logs, set on every event and carried by the unit
+// contexts, see BaggageFromContext.
+func WithBaggage(baggage map[string]string) Option {
+	return func(m *Manager) {
+		if m.baggage == nil {
+			m.baggage = make(map[string]string, len(baggage))
+		}
+		for k, v := range baggage {
+			if k == "" {
+				m.invalid(fmt.Errorf("empty baggage key"))
+				continue
+			}
+			m.baggage[k] = v
+		}
+	}
+}
+
+// Baggage returns a copy of the manager baggage.
+func (m *Manager) Baggage() map[string]string {
+	return copyBaggage(m.baggage)
+}
+
+type baggageKey struct{}
+
+// BaggageFromContext returns a copy of the manager baggage carried by a unit
+// context, or nil.
+func BaggageFromContext(ctx context.Context) map[string]string {
+	baggage, _ := ctx.Value(baggageKey{}).(map[string]string)
+	return copyBaggage(baggage)
+}
+
+// initBaggage prepares the context and log prefix carrying the baggage.
+func (m *Manager) initBaggage() {
+	m.baseCtx = context.Background()
+	m.slogBase = m.slog
+	if len(m.baggage) == 0 {
+		return
+	}
+
+	m.baseCtx = context.WithValue(m.baseCtx, baggageKey{}, m.baggage)
+
+	keys := make([]string, 0, len(m.baggage))
+	for k := range m.baggage {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = k + "=" + m.baggage[k]
+	}
+	if m.slog != nil {
+		m.slog = m.withBaggage(m.slog)
+	}
+
+	// Escape the baggage as it is used in log formats
+	m.logPrefix = strings.ReplaceAll("["+strings.Join(pairs, " ")+"] ", "%", "%%")
+}
+
+// withBaggage returns the structured logger carrying the baggage as a group.
+func (m *Manager) withBaggage(l *slog.Logger) *slog.Logger {
+	if len(m.baggage) == 0 {
+		return l
+	}
+
+	keys := make([]string, 0, len(m.baggage))
+	for k := range m.baggage {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]any, len(keys))
+	for i, k := range keys {
+		attrs[i] = slog.String(k, m.baggage[k])
+	}
+	return l.With(slog.Group("baggage", attrs...))
+}
+
+func copyBaggage(baggage map[string]string) map[string]string {
+	if baggage == nil {
+		return nil
+	}
+	c := make(map[string]string, len(baggage))
+	for k, v := range baggage {
+		c[k] = v
+	}
+	return c
+}
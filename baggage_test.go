@@ -0,0 +1,52 @@
+package gum
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestBaggage(t *testing.T) {
+	var buf bytes.Buffer
+	manager := NewManager(
+		WithLogger(log.New(&buf, "", 0)),
+		WithBaggage(map[string]string{"run_id": "42", "env": "100%"}),
+	)
+	manager.AddUnit(&stopWorker{}, "")
+	sub := manager.Subscribe()
+
+	go manager.Run()
+	manager.Stop()
+	<-manager.Quit
+	sub.Close()
+
+	for ev := range sub.Events() {
+		if ev.Baggage["run_id"] != "42" {
+			t.Fatalf("expected baggage on %s event, got %v", ev, ev.Baggage)
+		}
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if !strings.HasPrefix(line, "[env=100% run_id=42] ") {
+			t.Fatalf("expected baggage in log line %q", line)
+		}
+	}
+
+	if BaggageFromContext(manager.ShutdownContext())["run_id"] != "42" {
+		t.Fatal("expected baggage in the unit context")
+	}
+}
+
+func TestBaggageImmutable(t *testing.T) {
+	baggage := map[string]string{"run_id": "42"}
+	manager := NewManager(WithBaggage(baggage))
+
+	baggage["run_id"] = "43"
+	manager.Baggage()["run_id"] = "43"
+	BaggageFromContext(manager.ShutdownContext())["run_id"] = "43"
+
+	if manager.Baggage()["run_id"] != "42" {
+		t.Fatal("manager baggage was modified")
+	}
+}
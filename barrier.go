@@ -0,0 +1,93 @@
+package gum
+
+import (
+	"context"
+	"sync"
+)
+
+// Barrier is a named synchronization point between units, lighter than
+// ordering the whole startup: units depending on a single condition wait on
+// the barrier, the unit fulfilling it signals it once.
+//
+//	// storage unit
+//	um.Ready()
+//	um.Barrier("storage-ready").Signal()
+//
+//	// api unit
+//	if err := um.Barrier("storage-ready").Wait(um.Context()); err != nil {
+//		um.Done() // Asked to stop before the storage was ready
+//		return
+//	}
+//
+// A unit waiting before calling Ready holds its startup slot meanwhile, see
+// WithStartupConcurrency, the signaling unit must not need one after it.
+type Barrier struct {
+	name string
+	c    chan struct{}
+	once sync.Once
+	m    *Manager
+}
+
+// Barrier returns the barrier of the given name, created on first use by
+// either side.
+func (m *Manager) Barrier(name string) *Barrier {
+	m.barriersMu.Lock()
+	defer m.barriersMu.Unlock()
+
+	b, ok := m.barriers[name]
+	if !ok {
+		if m.barriers == nil {
+			m.barriers = make(map[string]*Barrier)
+		}
+		b = &Barrier{name: name, c: make(chan struct{}), m: m}
+		m.barriers[name] = b
+	}
+	return b
+}
+
+// Barrier returns the manager barrier of the given name, see Manager.Barrier.
+func (w *WorkUnitManager) Barrier(name string) *Barrier {
+	return w.manager.Barrier(name)
+}
+
+// Name returns the name of the barrier.
+func (b *Barrier) Name() string {
+	return b.name
+}
+
+// Signal releases the units waiting on the barrier, and the ones waiting
+// later. Signaling twice is a no-op.
+func (b *Barrier) Signal() {
+	b.once.Do(func() {
+		close(b.c)
+		b.m.unitLogf("", "Barrier <%s> signaled\n", b.name)
+	})
+}
+
+// Signaled reports whether the barrier was signaled.
+func (b *Barrier) Signaled() bool {
+	select {
+	case <-b.c:
+		return true
+	default:
+		return false
+	}
+}
+
+// Done returns a channel closed once the barrier is signaled, to wait on it
+// along with other channels.
+func (b *Barrier) Done() <-chan struct{} {
+	return b.c
+}
+
+// Wait blocks until the barrier is signaled or ctx is done, in which case it
+// returns the context error. Units should pass um.Context() so the wait ends
+// when they are asked to stop.
+func (b *Barrier) Wait(ctx context.Context) error {
+	select {
+	case <-b.c:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
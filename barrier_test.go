@@ -0,0 +1,97 @@
+package gum
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// barrierWorker waits on a barrier before being ready, or signals it once
+// ready
+type barrierWorker struct {
+	name   string
+	signal bool
+	log    *teardownLog
+}
+
+func (w *barrierWorker) Run(um UnitManager) {
+	b := um.Barrier("storage-ready")
+	if w.signal {
+		time.Sleep(20 * time.Millisecond)
+		w.log.add(w.name)
+		um.Ready()
+		b.Signal()
+	} else {
+		if err := b.Wait(um.Context()); err != nil {
+			um.Done()
+			return
+		}
+		w.log.add(w.name)
+		um.Ready()
+	}
+	<-um.ShouldStop()
+	um.Done()
+}
+
+func TestBarrier(t *testing.T) {
+	log := &teardownLog{}
+	manager := NewManager()
+	manager.AddUnit(&barrierWorker{name: "api", log: log}, "", WithName("api"))
+	manager.AddUnit(&barrierWorker{name: "storage", signal: true, log: log}, "", WithName("storage"))
+
+	sub := manager.Subscribe()
+	quit := runAsync(manager)
+	waitEvent(t, sub, EventStartupComplete)
+	manager.Stop()
+	<-quit
+
+	if got := log.order; len(got) != 2 || got[0] != "storage" || got[1] != "api" {
+		t.Fatalf("expected the api to wait for the storage, got %v", got)
+	}
+	if !manager.Barrier("storage-ready").Signaled() {
+		t.Fatal("expected the barrier to be signaled")
+	}
+}
+
+func TestBarrierWaitStop(t *testing.T) {
+	log := &teardownLog{}
+	manager := NewManager()
+	manager.AddUnit(&barrierWorker{name: "api", log: log}, "")
+
+	quit := runAsync(manager)
+	waitState(t, manager, 0, Running)
+	manager.Stop()
+
+	select {
+	case <-quit:
+	case <-time.After(time.Second):
+		t.Fatal("the stop did not end the barrier wait")
+	}
+	if got := log.order; len(got) != 0 {
+		t.Fatalf("expected the api to never be ready, got %v", got)
+	}
+}
+
+func TestBarrierSignalTwice(t *testing.T) {
+	manager := NewManager()
+	b := manager.Barrier("once")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.Signal()
+		}()
+	}
+	wg.Wait()
+
+	if manager.Barrier("once") != b || !b.Signaled() {
+		t.Fatal("expected a single signaled barrier")
+	}
+	select {
+	case <-b.Done():
+	default:
+		t.Fatal("expected Done to be closed")
+	}
+}
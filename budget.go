@@ -0,0 +1,43 @@
+package gum
+
+import (
+	"fmt"
+	"time"
+)
+
+// panicHistory is the panic record of a unit lineage, shared by the
+// instances replacing the unit so restarts don't reset the panic budget.
+type panicHistory struct {
+	times []time.Time // Within the panic budget window
+	total int
+}
+
+// recordPanic counts a panic of the unit against its panic budget and
+// publishes an EventBudgetExceeded event when the budget is crossed.
+func (m *Manager) recordPanic(w *WorkUnitManager) {
+	now := m.clock.Now()
+
+	m.regMu.Lock()
+	p := w.panics
+	p.total++
+	exceeded := false
+
+	if w.panicBudgetWindow > 0 {
+		// Drop panics out of the window
+		i := 0
+		for i < len(p.times) && now.Sub(p.times[i]) > w.panicBudgetWindow {
+			i++
+		}
+		p.times = append(p.times[i:], now)
+		exceeded = len(p.times) == w.panicBudget+1
+	}
+
+	count := len(p.times)
+	m.touch()
+	m.regMu.Unlock()
+
+	if exceeded {
+		err := fmt.Errorf("%d panics within %s, budget is %d", count, w.panicBudgetWindow, w.panicBudget)
+		m.emitUnit(EventBudgetExceeded, w, err)
+	}
+}
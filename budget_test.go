@@ -0,0 +1,81 @@
+package gum
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPanicBudget(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&stopWorker{}, "", WithPanicBudget(2, time.Hour))
+	w := manager.order[0]
+	sub := manager.Subscribe()
+
+	for i := 0; i < 4; i++ {
+		manager.recordPanic(w)
+	}
+	sub.Close()
+
+	exceeded := 0
+	for ev := range sub.Events() {
+		if ev.Kind == EventBudgetExceeded {
+			exceeded++
+		}
+	}
+	if exceeded != 1 {
+		t.Fatalf("expected the budget to be crossed once, got %d events", exceeded)
+	}
+	if panics := manager.Snapshot().Units[0].Panics; panics != 4 {
+		t.Fatalf("expected 4 panics in status, got %d", panics)
+	}
+}
+
+func TestPanicBudgetWindow(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&stopWorker{}, "", WithPanicBudget(1, 10*time.Millisecond))
+	w := manager.order[0]
+	sub := manager.Subscribe()
+
+	manager.recordPanic(w)
+	time.Sleep(20 * time.Millisecond)
+	manager.recordPanic(w)
+	sub.Close()
+
+	for ev := range sub.Events() {
+		if ev.Kind == EventBudgetExceeded {
+			t.Fatal("panics out of the window counted against the budget")
+		}
+	}
+}
+
+func TestPanicBudgetRestarts(t *testing.T) {
+	unit := &flakyWorker{failures: 4}
+	manager := NewManager()
+	manager.AddUnit(unit, "", WithName("flaky"), WithPanicBudget(2, time.Hour),
+		WithRestart(RestartOnFailure), WithRestartBackoff(time.Millisecond, time.Millisecond))
+
+	sub := manager.Subscribe()
+	quit := runAsync(manager)
+	waitEvent(t, sub, EventBudgetExceeded)
+	waitEvent(t, sub, EventUnitReady)
+
+	// The restarted instances carry the panics of the earlier ones
+	if u, _ := manager.Status("flaky"); u.Panics != 4 || u.Restarts != 4 {
+		t.Fatalf("expected 4 panics and restarts, got %+v", u)
+	}
+	var buf bytes.Buffer
+	if err := manager.WriteMetrics(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `gum_unit_panics_total{unit="flaky"} 4`) {
+		t.Fatalf("expected the panics of all instances in the metrics, got:\n%s", buf.String())
+	}
+
+	manager.Stop()
+	<-quit
+	if unit.runs.Load() != 5 {
+		t.Fatalf("expected 5 runs, got %d", unit.runs.Load())
+	}
+}
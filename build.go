@@ -0,0 +1,56 @@
+package gum
+
+import (
+	"runtime/debug"
+	"time"
+)
+
+// BuildInfo describes the build of the supervised program.
+type BuildInfo struct {
+	Version string    `json:"version,omitempty"`
+	Commit  string    `json:"commit,omitempty"`
+	Built   time.Time `json:"built"`
+}
+
+func (b BuildInfo) String() string {
+	s := b.Version
+	if s == "" {
+		s = "unknown version"
+	}
+	if b.Commit != "" {
+		s += " (" + b.Commit + ")"
+	}
+	return s
+}
+
+// WithBuildInfo sets the build metadata reported by the manager in the
+// startup banner, the EventManagerStarted event and Snapshot, so ops tooling
+// can confirm which version is running.
+func WithBuildInfo(b BuildInfo) Option {
+	return func(m *Manager) {
+		m.build = &b
+	}
+}
+
+// ReadBuildInfo returns the build metadata embedded by the Go toolchain: the
+// main module version and the VCS revision and time.
+func ReadBuildInfo() BuildInfo {
+	var b BuildInfo
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return b
+	}
+
+	b.Version = info.Main.Version
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			b.Commit = s.Value
+		case "vcs.time":
+			b.Built, _ = time.Parse(time.RFC3339, s.Value)
+		}
+	}
+
+	return b
+}
@@ -0,0 +1,47 @@
+package gum
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBuildInfo(t *testing.T) {
+	var buf bytes.Buffer
+	build := BuildInfo{Version: "v1.2.3", Commit: "abc123"}
+	manager := NewManager(WithBuildInfo(build), WithLogger(log.New(&buf, "", 0)))
+	manager.AddUnit(&stopWorker{}, "")
+	sub := manager.Subscribe()
+
+	go manager.Run()
+	ev := <-sub.Events()
+	if ev.Kind != EventManagerStarted || ev.Build == nil || *ev.Build != build {
+		t.Fatalf("expected build info in %s event, got %+v", ev, ev.Build)
+	}
+	if snap := manager.Snapshot(); snap.Build == nil || *snap.Build != build {
+		t.Fatalf("expected build info in snapshot, got %+v", snap.Build)
+	}
+
+	srv := httptest.NewServer(manager.ControlHandler())
+	defer srv.Close()
+	resp, err := srv.Client().Get(srv.URL + "/status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ws wireSnapshot
+	json.NewDecoder(resp.Body).Decode(&ws)
+	resp.Body.Close()
+	if ws.Build == nil || ws.Build.Version != "v1.2.3" {
+		t.Fatalf("expected build info in control status, got %+v", ws.Build)
+	}
+
+	manager.Stop()
+	<-manager.Quit
+
+	if !strings.Contains(buf.String(), "Version v1.2.3 (abc123)") {
+		t.Fatalf("expected version banner in logs:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,118 @@
+package gum
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+// Builder configures a Manager with a fluent API. The whole configuration is
+// validated by Build.
+//
+//	manager, err := gum.New().
+//		Signals(os.Interrupt, syscall.SIGTERM).
+//		Timeout(10 * time.Second).
+//		Unit(db, "db").
+//		Group("workers", worker1, worker2).
+//		Build()
+type Builder struct {
+	opts      []Option
+	signals   []os.Signal
+	immediate []os.Signal
+	units     []unitSpec
+	errs      []error
+}
+
+// unitSpec is a unit waiting to be added to the manager.
+type unitSpec struct {
+	unit WorkUnit
+	name string
+	opts []UnitOption
+}
+
+// New returns a Builder for a new Manager.
+func New() *Builder {
+	return &Builder{}
+}
+
+// fail records a configuration error.
+func (b *Builder) fail(err error) *Builder {
+	b.errs = append(b.errs, err)
+	return b
+}
+
+// Options adds manager options.
+func (b *Builder) Options(opts ...Option) *Builder {
+	b.opts = append(b.opts, opts...)
+	return b
+}
+
+// Signals registers graceful shutdown signals, see Manager.ShutdownOn.
+func (b *Builder) Signals(sig ...os.Signal) *Builder {
+	b.signals = append(b.signals, sig...)
+	return b
+}
+
+// ImmediateSignals registers immediate shutdown signals, see
+// Manager.ImmediateShutdownOn.
+func (b *Builder) ImmediateSignals(sig ...os.Signal) *Builder {
+	b.immediate = append(b.immediate, sig...)
+	return b
+}
+
+// Timeout sets the shutdown timeout, see WithShutdownTimeout.
+func (b *Builder) Timeout(d time.Duration) *Builder {
+	return b.Options(WithShutdownTimeout(d))
+}
+
+// StartupConcurrency bounds the number of units starting at the same time,
+// see WithStartupConcurrency.
+func (b *Builder) StartupConcurrency(n int) *Builder {
+	return b.Options(WithStartupConcurrency(n))
+}
+
+// PanicPolicy sets the policy applied when a unit panics, see
+// WithPanicPolicy.
+func (b *Builder) PanicPolicy(policy PanicPolicy) *Builder {
+	return b.Options(WithPanicPolicy(policy))
+}
+
+// Unit adds a unit, see Manager.AddUnit.
+func (b *Builder) Unit(unit WorkUnit, name string, opts ...UnitOption) *Builder {
+	b.units = append(b.units, unitSpec{unit, name, opts})
+	return b
+}
+
+// Group adds units sharing the same name, they are identified as
+// name[Type#ID].
+func (b *Builder) Group(name string, units ...WorkUnit) *Builder {
+	if name == "" {
+		return b.fail(errors.New("group without name"))
+	}
+	for _, unit := range units {
+		b.Unit(unit, name)
+	}
+	return b
+}
+
+// Build validates the configuration and returns the configured Manager. All
+// the problems found are returned at once, see Manager.Validate.
+func (b *Builder) Build() (*Manager, error) {
+	m := NewManager(b.opts...)
+	for _, spec := range b.units {
+		m.AddUnit(spec.unit, spec.name, spec.opts...)
+	}
+
+	if err := errors.Join(append(b.errs, m.Validate())...); err != nil {
+		return nil, err
+	}
+
+	if len(b.signals) > 0 {
+		m.ShutdownOn(b.signals...)
+	}
+	if len(b.immediate) > 0 {
+		m.ImmediateShutdownOn(b.immediate...)
+	}
+
+	return m, nil
+}
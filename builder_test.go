@@ -0,0 +1,52 @@
+package gum
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuilder(t *testing.T) {
+	manager, err := New().
+		Timeout(time.Second).
+		StartupConcurrency(2).
+		Unit(&stopWorker{}, "db").
+		Group("workers", &stopWorker{}, &stopWorker{}).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if manager.shutdownTimeout != time.Second {
+		t.Errorf("unexpected shutdown timeout %s", manager.shutdownTimeout)
+	}
+	if cap(manager.startSem) != 2 {
+		t.Errorf("unexpected startup concurrency %d", cap(manager.startSem))
+	}
+
+	units := manager.Snapshot().Units
+	if len(units) != 3 {
+		t.Fatalf("expected 3 units, got %d", len(units))
+	}
+	for _, u := range units[1:] {
+		if !strings.HasPrefix(u.Name, "workers[") {
+			t.Errorf("expected unit of the workers group, got %s", u.Name)
+		}
+	}
+}
+
+func TestBuilderInvalid(t *testing.T) {
+	manager, err := New().
+		Timeout(-time.Second).
+		Unit(nil, "db").
+		Build()
+
+	if err == nil || manager != nil {
+		t.Fatal("expected invalid configuration to fail")
+	}
+	for _, msg := range []string{"negative shutdown timeout", "nil unit"} {
+		if !strings.Contains(err.Error(), msg) {
+			t.Errorf("expected error %q to be reported, got: %v", msg, err)
+		}
+	}
+}
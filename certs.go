@@ -0,0 +1,136 @@
+package gum
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// CertReloader is a unit serving a TLS certificate reloaded without restart,
+// on changes of its files, on SIGHUP and optionally periodically. Servers get
+// the current certificate through GetCertificate or TLSConfig:
+//
+//	certs := gum.ReloadCertFiles("/etc/app/tls.crt", "/etc/app/tls.key")
+//	manager.AddUnit(certs, "certs")
+//	server := &http.Server{TLSConfig: certs.TLSConfig()}
+//
+// The certificate is loaded before the unit is ready, a server unit declared
+// After("certs") only starts once the certificate is available. A failed
+// first load fails the unit, later failures keep the current certificate.
+type CertReloader struct {
+	load     func() (*tls.Certificate, error)
+	watcher  *FileWatcher
+	every    time.Duration
+	onReload func(err error)
+	cert     atomic.Pointer[tls.Certificate]
+}
+
+// ReloadCertFiles returns a CertReloader loading a PEM encoded certificate
+// and key pair, reloaded when the files change.
+func ReloadCertFiles(certFile, keyFile string) *CertReloader {
+	r := ReloadCert(func() (*tls.Certificate, error) {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		return &cert, nil
+	})
+	r.watcher = WatchFiles(certFile, keyFile)
+	return r
+}
+
+// ReloadCert returns a CertReloader loading its certificate from a provider,
+// e.g. a secret store. It is reloaded on SIGHUP and every interval set with
+// Every.
+func ReloadCert(load func() (*tls.Certificate, error)) *CertReloader {
+	return &CertReloader{load: load}
+}
+
+// Every reloads the certificate periodically, on top of the other triggers.
+func (r *CertReloader) Every(d time.Duration) *CertReloader {
+	r.every = d
+	return r
+}
+
+// OnReload sets the function called after each reload, with the error of the
+// reload if it failed, e.g. to log it or count failures.
+func (r *CertReloader) OnReload(onReload func(err error)) *CertReloader {
+	r.onReload = onReload
+	return r
+}
+
+// GetCertificate returns the current certificate, for tls.Config.
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	cert := r.cert.Load()
+	if cert == nil {
+		return nil, errors.New("certificate not loaded yet")
+	}
+	return cert, nil
+}
+
+// TLSConfig returns a TLS configuration serving the current certificate.
+func (r *CertReloader) TLSConfig() *tls.Config {
+	return &tls.Config{GetCertificate: r.GetCertificate}
+}
+
+// Run loads the certificate and reloads it until the unit is asked to stop.
+func (r *CertReloader) Run(um UnitManager) {
+	var files map[string]fileState
+	if r.watcher != nil {
+		files = scanFiles(r.watcher.paths)
+	}
+
+	if err := r.reload(); err != nil {
+		um.Panic(err)
+		return
+	}
+
+	var changes <-chan []string
+	if r.watcher != nil {
+		ctx, cancel := context.WithCancel(um.Context())
+		defer cancel()
+		go r.watcher.watch(ctx, files)
+		changes = r.watcher.Changes()
+	}
+
+	var tick <-chan time.Time
+	if r.every > 0 {
+		ticker := time.NewTicker(r.every)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	sighup := um.Signals(syscall.SIGHUP)
+	um.Ready()
+
+	for {
+		select {
+		case <-um.ShouldStop():
+			um.Done()
+			return
+		case <-changes:
+		case <-sighup:
+		case <-tick:
+		}
+		r.reload()
+	}
+}
+
+// reload loads the certificate, keeping the current one on failure.
+func (r *CertReloader) reload() error {
+	cert, err := r.load()
+	if err == nil && cert == nil {
+		err = errors.New("no certificate loaded")
+	}
+	if err == nil {
+		r.cert.Store(cert)
+	}
+
+	if r.onReload != nil {
+		r.onReload(err)
+	}
+	return err
+}
@@ -0,0 +1,142 @@
+package gum
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// writeCert writes a self-signed certificate and its key, and returns the
+// DER encoded certificate.
+func writeCert(t *testing.T, certFile, keyFile, name string) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if err := os.WriteFile(keyFile, keyPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(certFile, certPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return der
+}
+
+func TestReloadCertFiles(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	first := writeCert(t, certFile, keyFile, "first")
+
+	certs := ReloadCertFiles(certFile, keyFile)
+	certs.watcher.Interval(2 * time.Millisecond).Debounce(10 * time.Millisecond)
+
+	manager := NewManager()
+	manager.AddUnit(certs, "")
+	sub := manager.Subscribe()
+	go manager.Run()
+	defer func() {
+		manager.Stop()
+		<-manager.Quit
+	}()
+	waitEvent(t, sub, EventUnitReady)
+
+	cert, err := certs.TLSConfig().GetCertificate(nil)
+	if err != nil || !bytes.Equal(cert.Certificate[0], first) {
+		t.Fatalf("expected the first certificate, got %v", err)
+	}
+
+	second := writeCert(t, certFile, keyFile, "second")
+	deadline := time.Now().Add(time.Second)
+	for {
+		cert, _ := certs.GetCertificate(nil)
+		if bytes.Equal(cert.Certificate[0], second) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("certificate not reloaded")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestReloadCertSignal(t *testing.T) {
+	var loads atomic.Int32
+	dir := t.TempDir()
+	der := writeCert(t, filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), "signal")
+	certs := ReloadCert(func() (*tls.Certificate, error) {
+		loads.Add(1)
+		return &tls.Certificate{Certificate: [][]byte{der}}, nil
+	})
+
+	reloaded := make(chan error, 1)
+	certs.OnReload(func(err error) {
+		if loads.Load() > 1 {
+			reloaded <- err
+		}
+	})
+
+	manager := NewManager()
+	manager.AddUnit(certs, "")
+	sub := manager.Subscribe()
+	go manager.Run()
+	defer func() {
+		manager.Stop()
+		<-manager.Quit
+	}()
+	waitEvent(t, sub, EventUnitReady)
+
+	manager.signalIn <- syscall.SIGHUP
+	select {
+	case err := <-reloaded:
+		if err != nil {
+			t.Fatalf("unexpected reload error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("certificate not reloaded on SIGHUP")
+	}
+}
+
+func TestReloadCertFailure(t *testing.T) {
+	errNoSecret := errors.New("no secret")
+	certs := ReloadCert(func() (*tls.Certificate, error) { return nil, errNoSecret })
+
+	manager := NewManager()
+	manager.AddUnit(certs, "")
+	manager.Run()
+
+	if !errors.Is(manager.Err(), errNoSecret) {
+		t.Fatalf("expected the first load to fail the unit, got %v", manager.Err())
+	}
+}
@@ -0,0 +1,47 @@
+package gum
+
+import (
+	"fmt"
+	"time"
+)
+
+// Clock tells the time to the manager, see WithClock.
+type Clock interface {
+	Now() time.Time
+
+	// AfterFunc calls f in its own goroutine once d has elapsed, unless
+	// stopped first.
+	AfterFunc(d time.Duration, f func()) interface{ Stop() bool }
+}
+
+// systemClock is the wall clock.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) AfterFunc(d time.Duration, f func()) interface{ Stop() bool } {
+	return time.AfterFunc(d, f)
+}
+
+// WithClock sets the clock timing the restart backoffs, the restart and
+// panic budget windows, the scheduled and parked units, the events and the
+// uptimes and durations reported by the manager, so tests can drive them with
+// a fake clock instead of sleeping. Scheduled units are then timed by the
+// clock rather than by the timer wheel of the manager.
+func WithClock(c Clock) Option {
+	return func(m *Manager) {
+		if c == nil {
+			m.invalid(fmt.Errorf("nil clock"))
+			return
+		}
+		m.clock = c
+	}
+}
+
+// customClock reports whether the clock was set with WithClock.
+func (m *Manager) customClock() bool {
+	_, ok := m.clock.(systemClock)
+	return !ok
+}
@@ -0,0 +1,140 @@
+package gum
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// manualClock is a fake clock whose time only moves with Advance.
+type manualClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*manualTimer
+}
+
+type manualTimer struct {
+	clock *manualClock
+	at    time.Time
+	f     func()
+}
+
+func (c *manualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *manualClock) AfterFunc(d time.Duration, f func()) interface{ Stop() bool } {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &manualTimer{clock: c, at: c.now.Add(d), f: f}
+	c.timers = append(c.timers, t)
+	return t
+}
+
+func (t *manualTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	for i, timer := range t.clock.timers {
+		if timer == t {
+			t.clock.timers = append(t.clock.timers[:i], t.clock.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// pending returns the number of timers not fired yet.
+func (c *manualClock) pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.timers)
+}
+
+// Advance moves the time forward, firing the timers due meanwhile.
+func (c *manualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	var due []*manualTimer
+	timers := c.timers[:0]
+	for _, t := range c.timers {
+		if t.at.After(c.now) {
+			timers = append(timers, t)
+		} else {
+			due = append(due, t)
+		}
+	}
+	c.timers = timers
+	c.mu.Unlock()
+
+	for _, t := range due {
+		go t.f()
+	}
+}
+
+func TestWithClock(t *testing.T) {
+	clock := &manualClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	unit := &flakyWorker{failures: 1}
+	manager := NewManager(WithClock(clock))
+	manager.AddUnit(unit, "", WithRestart(RestartOnFailure), WithRestartBackoff(time.Hour, time.Hour))
+	sub := manager.Subscribe()
+
+	quit := runAsync(manager)
+
+	ev := waitEvent(t, sub, EventUnitRestart)
+	if !ev.Time.Equal(clock.Now()) {
+		t.Fatalf("expected the event to be timed by the clock, got %s", ev.Time)
+	}
+
+	// The restart waits for the clock
+	deadline := time.Now().Add(time.Second)
+	for clock.pending() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the restart to be scheduled on the clock")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if runs := unit.runs.Load(); runs != 1 {
+		t.Fatalf("expected the restart to wait for the clock, got %d runs", runs)
+	}
+	clock.Advance(time.Hour)
+	waitRestarted(t, manager, 0, 1)
+
+	manager.Stop()
+	<-quit
+
+	if err := NewManager(WithClock(nil)).Validate(); err == nil {
+		t.Fatal("expected a nil clock to be reported")
+	}
+}
+
+func TestClockPark(t *testing.T) {
+	clock := &manualClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	w := &parkWorker{until: clock.Now().Add(time.Hour), woken: make(chan bool, 1)}
+	manager := NewManager(WithClock(clock))
+	manager.AddUnit(w, "")
+	sub := manager.Subscribe()
+
+	quit := runAsync(manager)
+
+	// The uptime and the event time are told by the same clock
+	if ev := waitEvent(t, sub, EventUnitParked); ev.Uptime != 0 {
+		t.Fatalf("expected the uptime to be timed by the clock, got %s", ev.Uptime)
+	}
+	if clock.pending() == 0 {
+		t.Fatal("expected the wake up to be scheduled on the clock")
+	}
+	clock.Advance(time.Hour)
+	select {
+	case <-w.woken:
+	case <-time.After(time.Second):
+		t.Fatal("expected the unit to be woken by the clock")
+	}
+	if uptime := manager.Snapshot().Uptime; uptime != time.Hour {
+		t.Fatalf("expected an uptime of 1h, got %s", uptime)
+	}
+
+	manager.Stop()
+	<-quit
+}
@@ -0,0 +1,134 @@
+package gum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// CompositeUnit assembles one unit from several goroutines and closers, for
+// logical components which are really a set of parts, e.g. a Kafka consumer
+// made of a reader goroutine, a committer goroutine and a client to close.
+//
+//	unit := gum.Composite().
+//		Closer("client", client).
+//		Go("reader", reader.Run).
+//		Go("committer", committer.Run)
+//
+// Parts are started in the order they were added and torn down in reverse
+// order: goroutines are cancelled and awaited one at a time, closers are
+// closed. The unit is ready once all its goroutines are started. When a
+// goroutine returns, the other parts are torn down and the unit is done, or
+// panics if the goroutine failed.
+type CompositeUnit struct {
+	parts []compositePart
+}
+
+type compositePart struct {
+	name   string
+	run    func(ctx context.Context) error
+	closer io.Closer
+}
+
+// Composite returns an empty CompositeUnit.
+func Composite() *CompositeUnit {
+	return &CompositeUnit{}
+}
+
+// Go adds a goroutine. It must return once its context is cancelled.
+func (c *CompositeUnit) Go(name string, run func(ctx context.Context) error) *CompositeUnit {
+	c.parts = append(c.parts, compositePart{name: name, run: run})
+	return c
+}
+
+// Closer adds a closer, closed once the parts added after it are torn down.
+func (c *CompositeUnit) Closer(name string, closer io.Closer) *CompositeUnit {
+	c.parts = append(c.parts, compositePart{name: name, closer: closer})
+	return c
+}
+
+// partExit is the result of a composite goroutine.
+type partExit struct {
+	i   int
+	err error
+}
+
+// Run starts the parts and tears them down once the unit is asked to stop.
+func (c *CompositeUnit) Run(um UnitManager) {
+	cancels := make([]context.CancelFunc, len(c.parts))
+	exited := make([]chan error, len(c.parts))
+	exits := make(chan partExit, len(c.parts))
+
+	for i, p := range c.parts {
+		if p.run == nil {
+			continue
+		}
+
+		// Parts are cancelled one at a time, not by the stop of the unit
+		ctx, cancel := context.WithCancel(context.WithoutCancel(um.Context()))
+		cancels[i] = cancel
+		exited[i] = make(chan error, 1)
+
+		go func(i int, p compositePart) {
+			err := runPart(ctx, p)
+			exited[i] <- err
+			exits <- partExit{i, err}
+		}(i, p)
+	}
+	um.Ready()
+
+	// Wait for the stop or the end of a goroutine
+	var cause error
+	select {
+	case <-um.ShouldStop():
+	case exit := <-exits:
+		<-exited[exit.i]
+		exited[exit.i] = nil
+		if exit.err != nil {
+			cause = fmt.Errorf("%s: %w", c.parts[exit.i].name, exit.err)
+		}
+	}
+
+	// Tear down in reverse order
+	var errs []error
+	for i := len(c.parts) - 1; i >= 0; i-- {
+		p := c.parts[i]
+
+		if p.closer != nil {
+			if err := p.closer.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", p.name, err))
+			}
+			continue
+		}
+
+		cancels[i]()
+		if exited[i] == nil {
+			continue
+		}
+		select {
+		case err := <-exited[i]:
+			if err != nil && !errors.Is(err, context.Canceled) {
+				errs = append(errs, fmt.Errorf("%s: %w", p.name, err))
+			}
+		case <-um.ShutdownContext().Done():
+			errs = append(errs, fmt.Errorf("%s: %w", p.name, um.ShutdownContext().Err()))
+		}
+	}
+
+	if cause != nil {
+		um.Panic(errors.Join(append([]error{cause}, errs...)...))
+		return
+	}
+	um.Done()
+}
+
+// runPart runs a composite goroutine, converting its panic to an error.
+func runPart(ctx context.Context, p compositePart) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return p.run(ctx)
+}
@@ -0,0 +1,112 @@
+package gum
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// teardownLog records the order in which composite parts are torn down
+type teardownLog struct {
+	mu    sync.Mutex
+	order []string
+}
+
+func (l *teardownLog) add(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.order = append(l.order, name)
+}
+
+func (l *teardownLog) goroutine(name string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		<-ctx.Done()
+		l.add(name)
+		return ctx.Err()
+	}
+}
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error { return f() }
+
+func TestCompositeUnit(t *testing.T) {
+	l := &teardownLog{}
+	unit := Composite().
+		Closer("client", closerFunc(func() error { l.add("client"); return nil })).
+		Go("reader", l.goroutine("reader")).
+		Go("committer", l.goroutine("committer"))
+
+	manager := NewManager()
+	manager.AddUnit(unit, "consumer")
+
+	go manager.Run()
+	waitState(t, manager, 0, Running)
+	manager.Stop()
+	<-manager.Quit
+
+	if manager.Err() != nil {
+		t.Fatalf("unexpected shutdown cause: %v", manager.Err())
+	}
+	want := []string{"committer", "reader", "client"}
+	if len(l.order) != len(want) {
+		t.Fatalf("expected teardown %v, got %v", want, l.order)
+	}
+	for i := range want {
+		if l.order[i] != want[i] {
+			t.Fatalf("expected teardown %v, got %v", want, l.order)
+		}
+	}
+}
+
+func TestCompositeUnitFailure(t *testing.T) {
+	errLost := errors.New("connection lost")
+
+	l := &teardownLog{}
+	unit := Composite().
+		Closer("client", closerFunc(func() error { l.add("client"); return nil })).
+		Go("reader", func(ctx context.Context) error {
+			time.Sleep(10 * time.Millisecond)
+			return errLost
+		}).
+		Go("committer", l.goroutine("committer"))
+
+	manager := NewManager()
+	manager.AddUnit(unit, "consumer")
+
+	select {
+	case <-runAsync(manager):
+	case <-time.After(time.Second):
+		t.Fatal("manager did not quit after the composite failure")
+	}
+
+	if !errors.Is(manager.Err(), ErrUnitPanic) || !errors.Is(manager.Err(), errLost) {
+		t.Fatalf("expected the reader failure, got %v", manager.Err())
+	}
+	if len(l.order) != 2 || l.order[0] != "committer" || l.order[1] != "client" {
+		t.Fatalf("expected the other parts to be torn down, got %v", l.order)
+	}
+}
+
+func TestCompositeUnitPanic(t *testing.T) {
+	unit := Composite().Go("reader", func(ctx context.Context) error {
+		var m map[string]int
+		m["boom"]++
+		return nil
+	})
+
+	manager := NewManager()
+	manager.AddUnit(unit, "")
+	manager.Run()
+
+	if !errors.Is(manager.Err(), ErrUnitPanic) {
+		t.Fatalf("expected the goroutine panic to be reported, got %v", manager.Err())
+	}
+}
+
+func runAsync(manager *Manager) <-chan bool {
+	go manager.Run()
+	return manager.Quit
+}
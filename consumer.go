@@ -0,0 +1,173 @@
+package gum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Source is a message source consumed by a ConsumerUnit, e.g. a Kafka
+// consumer group, a NATS pull subscription or an SQS queue. A source which
+// is also an io.Closer is closed once the consumer is drained.
+type Source[M any] interface {
+	// Fetch blocks until a batch of messages is available. It must return
+	// once ctx is cancelled, possibly with the messages already fetched.
+	Fetch(ctx context.Context) ([]M, error)
+
+	// Commit acknowledges the handled messages, in fetch order.
+	Commit(ctx context.Context, msgs []M) error
+}
+
+// Handler handles the messages of a ConsumerUnit.
+type Handler[M any] interface {
+	Handle(ctx context.Context, msg M) error
+}
+
+// HandlerFunc adapts a function to a Handler.
+type HandlerFunc[M any] func(ctx context.Context, msg M) error
+
+// Handle calls f(ctx, msg).
+func (f HandlerFunc[M]) Handle(ctx context.Context, msg M) error {
+	return f(ctx, msg)
+}
+
+// ConsumerUnit is a unit running the loop of a message consumer: fetch a
+// batch, handle each message with its own context, commit the handled
+// messages, so consumers get the shutdown right without reimplementing it.
+//
+//	unit := gum.Consume[*kafka.Message](source, gum.HandlerFunc[*kafka.Message](handle)).
+//		HandleTimeout(10 * time.Second)
+//
+// When the unit is asked to stop, the pending fetch is cancelled, the
+// messages already fetched are handled and committed, then the source is
+// closed and the unit is done. Handler contexts are not cancelled by the
+// stop, only by the end of the shutdown budget, see ShutdownContext.
+//
+// A failure to fetch or commit fails the unit. A handler failure is given to
+// the error hook, see OnError, and fails the unit by default: the messages
+// handled before it are committed, the failed one is not.
+type ConsumerUnit[M any] struct {
+	source        Source[M]
+	handler       Handler[M]
+	handleTimeout time.Duration
+	onError       func(msg M, err error) error
+}
+
+// Consume returns a ConsumerUnit handling the messages of source.
+func Consume[M any](source Source[M], handler Handler[M]) *ConsumerUnit[M] {
+	return &ConsumerUnit[M]{source: source, handler: handler}
+}
+
+// HandleTimeout bounds the handling of each message. Non-positive timeouts
+// are ignored.
+func (c *ConsumerUnit[M]) HandleTimeout(d time.Duration) *ConsumerUnit[M] {
+	if d > 0 {
+		c.handleTimeout = d
+	}
+	return c
+}
+
+// OnError sets the hook called with the messages which failed to be handled.
+// Returning nil skips the message, it is committed, e.g. once sent to a dead
+// letter queue. Returning an error fails the unit.
+func (c *ConsumerUnit[M]) OnError(onError func(msg M, err error) error) *ConsumerUnit[M] {
+	c.onError = onError
+	return c
+}
+
+// Run consumes the source until the unit is asked to stop.
+func (c *ConsumerUnit[M]) Run(um UnitManager) {
+	um.Ready()
+
+	for {
+		msgs, err := c.source.Fetch(um.Context())
+		if err != nil && !um.Stopping() {
+			c.fail(um, fmt.Errorf("fetch: %w", err))
+			return
+		}
+
+		handled, err := c.handle(um, msgs)
+		if len(handled) > 0 {
+			ctx, cancel := c.drainContext(um)
+			cerr := c.source.Commit(ctx, handled)
+			cancel()
+			if cerr != nil {
+				c.fail(um, fmt.Errorf("commit: %w", cerr))
+				return
+			}
+		}
+		if err != nil {
+			c.fail(um, err)
+			return
+		}
+
+		if um.Stopping() {
+			if err := c.close(); err != nil {
+				um.Panic(fmt.Errorf("close: %w", err))
+				return
+			}
+			um.Done()
+			return
+		}
+	}
+}
+
+// handle handles the batch in order and returns the messages to commit. It
+// stops at the first failure, or once the shutdown budget is exceeded.
+func (c *ConsumerUnit[M]) handle(um UnitManager, msgs []M) ([]M, error) {
+	for i, msg := range msgs {
+		err := c.handleOne(um, msg)
+		if err == nil {
+			continue
+		}
+		if um.Stopping() && um.ShutdownContext().Err() != nil {
+			return msgs[:i], nil // Out of budget, the rest is redelivered
+		}
+		if c.onError != nil {
+			err = c.onError(msg, err)
+		}
+		if err != nil {
+			return msgs[:i], fmt.Errorf("handle: %w", err)
+		}
+	}
+	return msgs, nil
+}
+
+// handleOne handles a message within the handle timeout.
+func (c *ConsumerUnit[M]) handleOne(um UnitManager, msg M) error {
+	ctx, cancel := c.drainContext(um)
+	defer cancel()
+	if c.handleTimeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, c.handleTimeout)
+		defer cancelTimeout()
+	}
+	return c.handler.Handle(ctx, msg)
+}
+
+// drainContext returns a context which is not cancelled by the stop of the
+// unit, only by the end of the shutdown budget.
+func (c *ConsumerUnit[M]) drainContext(um UnitManager) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.WithoutCancel(um.Context()))
+	stop := context.AfterFunc(um.Context(), func() {
+		context.AfterFunc(um.ShutdownContext(), cancel)
+	})
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// fail closes the source and fails the unit.
+func (c *ConsumerUnit[M]) fail(um UnitManager, err error) {
+	um.Panic(errors.Join(err, c.close()))
+}
+
+func (c *ConsumerUnit[M]) close() error {
+	if closer, ok := c.source.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package gum
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memSource delivers batches of ints and records the commits
+type memSource struct {
+	batches chan []int
+
+	mu        sync.Mutex
+	committed []int
+	closed    bool
+}
+
+func (s *memSource) Fetch(ctx context.Context) ([]int, error) {
+	select {
+	case b := <-s.batches:
+		return b, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (s *memSource) Commit(ctx context.Context, msgs []int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.committed = append(s.committed, msgs...)
+	return nil
+}
+
+func (s *memSource) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+func TestConsumerDrain(t *testing.T) {
+	source := &memSource{batches: make(chan []int, 1)}
+	handling := make(chan struct{})
+	var handled []int
+	unit := Consume[int](source, HandlerFunc[int](func(ctx context.Context, msg int) error {
+		if msg == 1 {
+			close(handling)
+			time.Sleep(10 * time.Millisecond) // Stop requested while handling
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		handled = append(handled, msg)
+		return nil
+	}))
+
+	manager := NewManager()
+	manager.AddUnit(unit, "")
+	quit := runAsync(manager)
+	source.batches <- []int{1, 2, 3}
+	<-handling
+	manager.Stop()
+	<-quit
+
+	if manager.Err() != nil {
+		t.Fatalf("unexpected shutdown cause: %v", manager.Err())
+	}
+	if len(handled) != 3 || len(source.committed) != 3 || !source.closed {
+		t.Fatalf("expected the batch to be drained, handled %v, committed %v", handled, source.committed)
+	}
+}
+
+func TestConsumerHandlerFailure(t *testing.T) {
+	errBad := errors.New("bad message")
+	source := &memSource{batches: make(chan []int, 1)}
+	source.batches <- []int{1, 2, 3, 4}
+	unit := Consume[int](source, HandlerFunc[int](func(ctx context.Context, msg int) error {
+		if msg == 3 {
+			return errBad
+		}
+		return nil
+	}))
+
+	manager := NewManager()
+	manager.AddUnit(unit, "")
+	manager.Run()
+
+	if !errors.Is(manager.Err(), errBad) {
+		t.Fatalf("expected the handler failure to fail the unit, got %v", manager.Err())
+	}
+	if len(source.committed) != 2 || !source.closed {
+		t.Fatalf("expected the messages before the failure to be committed, got %v", source.committed)
+	}
+}
+
+func TestConsumerOnError(t *testing.T) {
+	source := &memSource{batches: make(chan []int, 1)}
+	source.batches <- []int{1, 2, 3}
+	var skipped []int
+	unit := Consume[int](source, HandlerFunc[int](func(ctx context.Context, msg int) error {
+		if msg == 2 {
+			return errors.New("bad message")
+		}
+		return nil
+	})).OnError(func(msg int, err error) error {
+		skipped = append(skipped, msg)
+		return nil
+	})
+
+	manager := NewManager()
+	manager.AddUnit(unit, "")
+	quit := runAsync(manager)
+	waitCommitted(t, source, 3)
+	manager.Stop()
+	<-quit
+
+	if manager.Err() != nil || len(skipped) != 1 || skipped[0] != 2 {
+		t.Fatalf("expected the failed message to be skipped, got %v, %v", skipped, manager.Err())
+	}
+}
+
+func waitCommitted(t *testing.T, source *memSource, n int) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		source.mu.Lock()
+		committed := len(source.committed)
+		source.mu.Unlock()
+		if committed >= n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d committed messages, got %d", n, committed)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,229 @@
+package gum
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// WithControlAddr serves the ControlHandler on the TCP address addr, e.g.
+// "127.0.0.1:7070", from the start of Run until the manager quits. The
+// manager doesn't start if it can't listen on addr, see ControlAddr.
+func WithControlAddr(addr string) Option {
+	return func(m *Manager) {
+		if addr == "" {
+			m.invalid(fmt.Errorf("empty control address"))
+			return
+		}
+		m.controlAddr = addr
+	}
+}
+
+// ControlAddr returns the address the control handler is served on, e.g. to
+// find the port picked for "127.0.0.1:0", or nil if it is not served.
+func (m *Manager) ControlAddr() net.Addr {
+	m.controlMu.Lock()
+	defer m.controlMu.Unlock()
+
+	if m.controlLn == nil {
+		return nil
+	}
+	return m.controlLn.Addr()
+}
+
+// serveControl starts serving the control handler, see WithControlAddr.
+func (m *Manager) serveControl() error {
+	if m.controlAddr == "" {
+		return nil
+	}
+
+	ln, err := net.Listen("tcp", m.controlAddr)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{Handler: m.ControlHandler(), ReadHeaderTimeout: 10 * time.Second}
+
+	m.controlMu.Lock()
+	m.controlSrv, m.controlLn = srv, ln
+	m.controlMu.Unlock()
+
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			m.warnf("", "Control handler stopped: %s\n", err)
+		}
+	}()
+	return nil
+}
+
+// stopControl stops serving the control handler.
+func (m *Manager) stopControl() {
+	m.controlMu.Lock()
+	srv := m.controlSrv
+	m.controlSrv, m.controlLn = nil, nil
+	m.controlMu.Unlock()
+
+	if srv != nil {
+		srv.Close()
+	}
+}
+
+// ControlHandler returns an HTTP handler exposing the manager to other
+// processes, e.g. a parent manager supervising it with RemoteManager. It
+// serves:
+//
+//	GET  /status  the manager Snapshot as JSON
+//	GET  /metrics the manager metrics, see WriteMetrics
+//	POST /stop    stops the manager as Stop does
+//	GET  /settings the settings tunable at runtime as a JSON object
+//	POST /settings tunes the settings of the JSON object in the body, see Tune
+//
+// Use ObserverHandler to only expose the read-only endpoints. The handler
+// has no authentication, it should only be served on a private interface or
+// behind an authenticating proxy.
+func (m *Manager) ControlHandler() http.Handler {
+	mux := http.NewServeMux()
+
+	handleObserver(mux, m.Observer())
+
+	mux.HandleFunc("/stop", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		m.logf("stop requested through the control handler\n")
+		m.Stop()
+		rw.WriteHeader(http.StatusAccepted)
+	})
+
+	mux.HandleFunc("/settings", func(rw http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPost:
+			var values map[string]string
+			if err := json.NewDecoder(r.Body).Decode(&values); err != nil {
+				http.Error(rw, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if err := m.tuneAll(values); err != nil {
+				http.Error(rw, err.Error(), http.StatusBadRequest)
+				return
+			}
+		default:
+			http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		rw.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(rw).Encode(m.Settings())
+	})
+
+	return mux
+}
+
+// wireSnapshot is the JSON representation of a Snapshot.
+type wireSnapshot struct {
+	Version uint64        `json:"version"`
+	Time    time.Time     `json:"time"`
+	Uptime  time.Duration `json:"uptime"`
+	Units   []wireUnit    `json:"units"`
+	Build   *BuildInfo    `json:"build,omitempty"`
+}
+
+type wireUnit struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Family      string            `json:"family,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	State       string            `json:"state"`
+	Ready       bool              `json:"ready"`
+	StartedAt   time.Time         `json:"started_at"`
+	StoppedAt   time.Time         `json:"stopped_at"`
+	Err         string            `json:"err,omitempty"`
+	Panics      int               `json:"panics"`
+	Restarts    int               `json:"restarts"`
+	Uptime      time.Duration     `json:"uptime"`
+	StopLatency time.Duration     `json:"stop_latency"`
+
+	StopLatencies Percentiles `json:"stop_latencies"`
+}
+
+func newWireSnapshot(snap Snapshot) wireSnapshot {
+	ws := wireSnapshot{
+		Version: snap.Version,
+		Time:    snap.Time,
+		Uptime:  snap.Uptime,
+		Units:   make([]wireUnit, len(snap.Units)),
+		Build:   snap.Build,
+	}
+
+	for i, u := range snap.Units {
+		ws.Units[i] = newWireUnit(u)
+	}
+
+	return ws
+}
+
+func newWireUnit(u UnitStatus) wireUnit {
+	wu := wireUnit{
+		Name:        u.Name,
+		Description: u.Description,
+		Family:      u.Family,
+		Labels:      u.Labels,
+		State:       u.State.String(),
+		Ready:       u.Ready,
+		StartedAt:   u.StartedAt,
+		StoppedAt:   u.StoppedAt,
+		Panics:      u.Panics,
+		Restarts:    u.Restarts,
+		Uptime:      u.Uptime,
+		StopLatency: u.StopLatency,
+
+		StopLatencies: u.StopLatencies,
+	}
+	if u.Err != nil {
+		wu.Err = u.Err.Error()
+	}
+	return wu
+}
+
+func (ws wireSnapshot) snapshot() (Snapshot, error) {
+	snap := Snapshot{
+		Version: ws.Version,
+		Time:    ws.Time,
+		Uptime:  ws.Uptime,
+		Units:   make([]UnitStatus, len(ws.Units)),
+		Build:   ws.Build,
+	}
+
+	for i, u := range ws.Units {
+		state, err := parseUnitState(u.State)
+		if err != nil {
+			return Snapshot{}, fmt.Errorf("unit <%s>: %w", u.Name, err)
+		}
+
+		snap.Units[i] = UnitStatus{
+			Name:        u.Name,
+			Description: u.Description,
+			Family:      u.Family,
+			Labels:      u.Labels,
+			State:       state,
+			Ready:       u.Ready,
+			StartedAt:   u.StartedAt,
+			StoppedAt:   u.StoppedAt,
+			Panics:      u.Panics,
+			Restarts:    u.Restarts,
+			Uptime:      u.Uptime,
+			StopLatency: u.StopLatency,
+
+			StopLatencies: u.StopLatencies,
+		}
+		if u.Err != "" {
+			snap.Units[i].Err = errors.New(u.Err)
+		}
+	}
+
+	return snap, nil
+}
@@ -0,0 +1,100 @@
+package gum
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestControlStatus(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&readyWorker{}, "api", WithLabels(map[string]string{"tier": "web"}))
+	srv := httptest.NewServer(manager.ControlHandler())
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var ws wireSnapshot
+	if err := json.NewDecoder(resp.Body).Decode(&ws); err != nil {
+		t.Fatal(err)
+	}
+	snap, err := ws.snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snap.Units) != 1 || snap.Units[0].Name != manager.order[0].name || snap.Units[0].State != Starting {
+		t.Fatalf("unexpected status %+v", snap.Units)
+	}
+	if tier := snap.Units[0].Labels["tier"]; tier != "web" {
+		t.Fatalf("expected the labels to round-trip, got %v", snap.Units[0].Labels)
+	}
+}
+
+func TestControlStop(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&readyWorker{}, "")
+	srv := httptest.NewServer(manager.ControlHandler())
+	defer srv.Close()
+
+	go manager.Run()
+
+	resp, err := http.Post(srv.URL+"/stop", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("unexpected status %s", resp.Status)
+	}
+
+	select {
+	case <-manager.Quit:
+	case <-time.After(time.Second):
+		t.Fatal("manager was not stopped")
+	}
+}
+
+func TestControlAddr(t *testing.T) {
+	manager := NewManager(WithControlAddr("127.0.0.1:0"))
+	manager.AddUnit(&readyWorker{}, "")
+	sub := manager.Subscribe()
+
+	quit := runAsync(manager)
+	waitEvent(t, sub, EventStartupComplete)
+
+	resp, err := http.Post("http://"+manager.ControlAddr().String()+"/stop", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	select {
+	case <-quit:
+	case <-time.After(time.Second):
+		t.Fatal("expected the served control handler to stop the manager")
+	}
+	if manager.ControlAddr() != nil {
+		t.Fatal("expected the control handler to be stopped with the manager")
+	}
+
+	// The manager doesn't start without its control handler
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	busy := NewManager(WithControlAddr(ln.Addr().String()))
+	busy.AddUnit(&readyWorker{}, "")
+	busy.Run()
+	if !errors.Is(busy.Err(), ErrStartup) {
+		t.Fatalf("expected a startup error, got %v", busy.Err())
+	}
+}
@@ -0,0 +1,180 @@
+package gum
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultControlSubject is the default subject prefix of a ControlPlane.
+const DefaultControlSubject = "gum"
+
+// Bus is the message bus used by a ControlPlane. It is shaped after NATS so a
+// *nats.Conn is adapted in a few lines, without gum depending on a client:
+//
+//	type natsBus struct{ nc *nats.Conn }
+//
+//	func (b natsBus) Publish(subject string, data []byte) error {
+//		return b.nc.Publish(subject, data)
+//	}
+//
+//	func (b natsBus) Subscribe(subject string, handler func(gum.BusMsg)) (func() error, error) {
+//		sub, err := b.nc.Subscribe(subject, func(m *nats.Msg) {
+//			handler(gum.BusMsg{Subject: m.Subject, Data: m.Data, Reply: m.Reply})
+//		})
+//		if err != nil {
+//			return nil, err
+//		}
+//		return sub.Unsubscribe, nil
+//	}
+type Bus interface {
+	Publish(subject string, data []byte) error
+
+	// Subscribe calls handler with the messages published on subject
+	// until unsubscribed.
+	Subscribe(subject string, handler func(msg BusMsg)) (unsubscribe func() error, err error)
+}
+
+// BusMsg is a message received from a Bus. Requests carry the subject to
+// publish the reply on.
+type BusMsg struct {
+	Subject string
+	Data    []byte
+	Reply   string
+}
+
+// ControlPlane is a unit exposing the manager over a message bus, for fleets
+// managing many processes without opening a socket on each host. With the
+// subject prefix "gum.host1", it serves the requests:
+//
+//	gum.host1.status   replies the manager Snapshot as JSON
+//	gum.host1.stop     stops the manager as Stop does
+//	gum.host1.restart  restarts the unit named in the message, see RestartUnit
+//	gum.host1.tune     tunes the setting of a "name=value" message, see Tune
+//
+// and publishes the manager events as JSON on gum.host1.events. Commands are
+// replied "ok" or "error: " followed by the error. Like ControlHandler, the
+// control plane has no authentication of its own, it relies on the
+// permissions of the bus.
+type ControlPlane struct {
+	m       *Manager
+	bus     Bus
+	subject string
+}
+
+// ControlPlane returns a unit exposing the manager on the bus under the
+// subject prefix, DefaultControlSubject if empty. It must be added to the
+// manager to be served.
+func (m *Manager) ControlPlane(bus Bus, subject string) *ControlPlane {
+	if subject == "" {
+		subject = DefaultControlSubject
+	}
+	return &ControlPlane{m: m, bus: bus, subject: subject}
+}
+
+// Run serves the requests and publishes the events until the unit is asked
+// to stop. Failing to subscribe fails the unit.
+func (p *ControlPlane) Run(um UnitManager) {
+	sub := p.m.Subscribe()
+	defer sub.Close()
+
+	handlers := []struct {
+		suffix string
+		handle func(BusMsg) []byte
+	}{
+		{".status", p.status},
+		{".stop", p.stop},
+		{".restart", p.restart},
+		{".tune", p.tune},
+	}
+	for _, h := range handlers {
+		handle := h.handle
+		unsubscribe, err := p.bus.Subscribe(p.subject+h.suffix, func(msg BusMsg) {
+			reply := handle(msg)
+			if msg.Reply != "" {
+				p.bus.Publish(msg.Reply, reply)
+			}
+		})
+		if err != nil {
+			um.Panic(fmt.Errorf("control plane: %w", err))
+			return
+		}
+		defer unsubscribe()
+	}
+	um.Ready()
+
+	for {
+		select {
+		case <-um.ShouldStop():
+			um.Done()
+			return
+		case ev := <-sub.Events():
+			data, err := json.Marshal(newWireEvent(ev))
+			if err == nil {
+				p.bus.Publish(p.subject+".events", data)
+			}
+		}
+	}
+}
+
+func (p *ControlPlane) status(BusMsg) []byte {
+	data, err := json.Marshal(newWireSnapshot(p.m.Snapshot()))
+	if err != nil {
+		return commandReply(err)
+	}
+	return data
+}
+
+func (p *ControlPlane) stop(BusMsg) []byte {
+	p.m.logf("stop requested through the control plane\n")
+	p.m.Stop()
+	return commandReply(nil)
+}
+
+func (p *ControlPlane) restart(msg BusMsg) []byte {
+	return commandReply(p.m.RestartUnit(string(msg.Data)))
+}
+
+func (p *ControlPlane) tune(msg BusMsg) []byte {
+	name, value, ok := strings.Cut(string(msg.Data), "=")
+	if !ok {
+		return commandReply(fmt.Errorf("can't tune: expected name=value, got %q", msg.Data))
+	}
+	return commandReply(p.m.Tune(name, value))
+}
+
+func commandReply(err error) []byte {
+	if err != nil {
+		return []byte("error: " + err.Error())
+	}
+	return []byte("ok")
+}
+
+// wireEvent is the JSON representation of an Event.
+type wireEvent struct {
+	Kind     string            `json:"kind"`
+	Severity string            `json:"severity"`
+	Unit     string            `json:"unit,omitempty"`
+	Time     time.Time         `json:"time"`
+	Err      string            `json:"err,omitempty"`
+	Uptime   time.Duration     `json:"uptime"`
+	TraceID  string            `json:"trace_id,omitempty"`
+	Baggage  map[string]string `json:"baggage,omitempty"`
+}
+
+func newWireEvent(ev Event) wireEvent {
+	we := wireEvent{
+		Kind:     ev.Kind.String(),
+		Severity: ev.Severity.String(),
+		Unit:     ev.Unit,
+		Time:     ev.Time,
+		Uptime:   ev.Uptime,
+		TraceID:  ev.TraceID,
+		Baggage:  ev.Baggage,
+	}
+	if ev.Err != nil {
+		we.Err = ev.Err.Error()
+	}
+	return we
+}
@@ -0,0 +1,125 @@
+package gum
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memBus is an in-memory Bus delivering messages synchronously
+type memBus struct {
+	mu       sync.Mutex
+	handlers map[string]func(BusMsg)
+	received map[string]chan []byte // Messages without handler, by subject
+}
+
+func newMemBus() *memBus {
+	return &memBus{handlers: make(map[string]func(BusMsg)), received: make(map[string]chan []byte)}
+}
+
+func (b *memBus) Publish(subject string, data []byte) error {
+	b.publish(BusMsg{Subject: subject, Data: data})
+	return nil
+}
+
+func (b *memBus) publish(msg BusMsg) {
+	b.mu.Lock()
+	handler := b.handlers[msg.Subject]
+	b.mu.Unlock()
+	if handler != nil {
+		handler(msg)
+		return
+	}
+
+	select {
+	case b.inbox(msg.Subject) <- msg.Data:
+	default:
+	}
+}
+
+func (b *memBus) inbox(subject string) chan []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c, ok := b.received[subject]
+	if !ok {
+		c = make(chan []byte, 64)
+		b.received[subject] = c
+	}
+	return c
+}
+
+func (b *memBus) Subscribe(subject string, handler func(BusMsg)) (func() error, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[subject] = handler
+	return func() error {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.handlers, subject)
+		return nil
+	}, nil
+}
+
+func (b *memBus) request(t *testing.T, subject, data string) []byte {
+	t.Helper()
+	b.publish(BusMsg{Subject: subject, Data: []byte(data), Reply: "reply"})
+	select {
+	case reply := <-b.inbox("reply"):
+		return reply
+	case <-time.After(time.Second):
+		t.Fatalf("no reply to %s", subject)
+		return nil
+	}
+}
+
+func TestControlPlane(t *testing.T) {
+	bus := newMemBus()
+	manager := NewManager()
+	manager.AddUnit(manager.ControlPlane(bus, "gum.test"), "", WithName("control"))
+	manager.AddUnit(&readyWorker{}, "", WithName("worker"))
+
+	sub := manager.Subscribe()
+	quit := runAsync(manager)
+	waitEvent(t, sub, EventStartupComplete)
+
+	var snap wireSnapshot
+	if err := json.Unmarshal(bus.request(t, "gum.test.status", ""), &snap); err != nil || len(snap.Units) != 2 {
+		t.Fatalf("unexpected status reply %+v: %v", snap, err)
+	}
+
+	if reply := bus.request(t, "gum.test.restart", "worker"); string(reply) != "ok" {
+		t.Fatalf("unexpected restart reply %q", reply)
+	}
+	waitRestarted(t, manager, 1, 1)
+	if reply := bus.request(t, "gum.test.restart", "unknown"); !strings.HasPrefix(string(reply), "error: ") {
+		t.Fatalf("expected an error reply, got %q", reply)
+	}
+
+	if reply := bus.request(t, "gum.test.tune", "shutdown_timeout=10s"); string(reply) != "ok" {
+		t.Fatalf("unexpected tune reply %q", reply)
+	}
+	if reply := bus.request(t, "gum.test.tune", "shutdown_timeout"); !strings.HasPrefix(string(reply), "error: ") {
+		t.Fatalf("expected an error reply, got %q", reply)
+	}
+
+	var ev wireEvent
+	select {
+	case data := <-bus.inbox("gum.test.events"):
+		if err := json.Unmarshal(data, &ev); err != nil || ev.Kind == "" {
+			t.Fatalf("unexpected event %s: %v", data, err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no event published")
+	}
+
+	if reply := bus.request(t, "gum.test.stop", ""); string(reply) != "ok" {
+		t.Fatalf("unexpected stop reply %q", reply)
+	}
+	select {
+	case <-quit:
+	case <-time.After(time.Second):
+		t.Fatal("manager not stopped through the control plane")
+	}
+}
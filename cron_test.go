@@ -0,0 +1,41 @@
+package gum
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	from := time.Date(2024, time.January, 31, 10, 17, 30, 0, time.UTC) // Wednesday
+
+	for _, tt := range []struct {
+		spec string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2024, time.January, 31, 10, 18, 0, 0, time.UTC)},
+		{"*/10 * * * *", time.Date(2024, time.January, 31, 10, 20, 0, 0, time.UTC)},
+		{"30 3 * * *", time.Date(2024, time.February, 1, 3, 30, 0, 0, time.UTC)},
+		{"0 9-17 * * mon-fri", time.Date(2024, time.January, 31, 11, 0, 0, 0, time.UTC)},
+		{"0 0 * * sat,7", time.Date(2024, time.February, 3, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 feb *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 15 * fri", time.Date(2024, time.February, 2, 0, 0, 0, 0, time.UTC)}, // Either day
+		{"@monthly", time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"@every 90s", from.Add(90 * time.Second)},
+		{"0 0 30 2 *", time.Time{}}, // Never
+	} {
+		s, err := ParseCron(tt.spec)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.spec, err)
+			continue
+		}
+		if next := s.Next(from); !next.Equal(tt.next) {
+			t.Errorf("%q: expected %s, got %s", tt.spec, tt.next, next)
+		}
+	}
+
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "5-1 * * * *", "*/0 * * * *", "* * * foo *", "@every -1s"} {
+		if _, err := ParseCron(spec); err == nil {
+			t.Errorf("%q: expected an error", spec)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package gum
+
+import (
+	"context"
+	"os"
+)
+
+// DefaultManager is the manager used by the package-level functions. It is
+// meant for small programs, larger applications should create their own
+// Manager with NewManager.
+var DefaultManager = NewManager()
+
+// Add registers a unit with the DefaultManager.
+func Add(unit WorkUnit, name string, opts ...UnitOption) {
+	DefaultManager.AddUnit(unit, name, opts...)
+}
+
+// AddFunc registers a function as a unit with the DefaultManager.
+func AddFunc(name string, fn func(ctx context.Context) error, opts ...UnitOption) {
+	DefaultManager.AddFunc(name, fn, opts...)
+}
+
+// ShutdownOn registers graceful shutdown signals on the DefaultManager.
+func ShutdownOn(sig ...os.Signal) {
+	DefaultManager.ShutdownOn(sig...)
+}
+
+// Run runs the DefaultManager and blocks until it is shut down. It returns
+// the shutdown cause.
+func Run() error {
+	return DefaultManager.Run()
+}
+
+// Stop triggers a graceful shutdown of the DefaultManager without waiting
+// for it.
+func Stop() {
+	DefaultManager.Stop()
+}
+
+// Shutdown triggers a graceful shutdown of the DefaultManager and waits for
+// it, see Manager.Shutdown.
+func Shutdown(ctx context.Context) error {
+	return DefaultManager.Shutdown(ctx)
+}
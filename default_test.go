@@ -0,0 +1,38 @@
+package gum
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDefaultManager(t *testing.T) {
+	defer func(m *Manager) { DefaultManager = m }(DefaultManager)
+	DefaultManager = NewManager()
+
+	Add(&stopWorker{}, "")
+
+	done := make(chan bool)
+	go func() {
+		Run()
+		close(done)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := Shutdown(ctx); err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
+
+	// Shutdown returns once the manager quit
+	select {
+	case <-DefaultManager.quitC:
+	default:
+		t.Fatal("expected the default manager to have quit")
+	}
+	<-done
+
+	if state := DefaultManager.Snapshot().Units[0].State; state != Stopped {
+		t.Fatalf("expected unit to be stopped, got %s", state)
+	}
+}
@@ -0,0 +1,338 @@
+package gum
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// After declares the units the unit depends on, by the name given to AddUnit
+// or WithName. The unit is started once its dependencies are ready, done or
+// disabled, and is asked to stop before them on shutdown:
+//
+//	manager.AddUnit(db, "db")
+//	manager.AddUnit(api, "api", gum.After("db"))
+//
+// Units are started in dependency order, registration order otherwise.
+// Unknown dependencies and cycles are reported by Validate.
+func After(units ...string) UnitOption {
+	return func(w *WorkUnitManager) {
+		for _, name := range units {
+			if name == "" {
+				w.invalid(fmt.Errorf("empty dependency name"))
+				return
+			}
+		}
+		w.after = append(w.after, units...)
+	}
+}
+
+// AfterWithin declares dependencies as After does, each of them given d to
+// be ready once the unit waits on it. A dependency not ready in time fails
+// the unit with ErrDependencyTimeout, naming both sides and the time waited,
+// instead of hanging the startup.
+func AfterWithin(d time.Duration, units ...string) UnitOption {
+	return func(w *WorkUnitManager) {
+		if d <= 0 {
+			w.invalid(fmt.Errorf("invalid dependency timeout %s", d))
+			return
+		}
+		After(units...)(w)
+		if w.afterWithin == nil {
+			w.afterWithin = make(map[string]time.Duration, len(units))
+		}
+		for _, name := range units {
+			w.afterWithin[name] = d
+		}
+	}
+}
+
+// WithDependencyTimeout sets the timeout of the dependencies declared with
+// After, see AfterWithin. By default units wait on their dependencies for as
+// long as the startup lasts, see WithStartupTimeout.
+func WithDependencyTimeout(d time.Duration) Option {
+	return func(m *Manager) {
+		if d <= 0 {
+			m.invalid(fmt.Errorf("invalid dependency timeout: %s", d))
+			return
+		}
+		m.depTimeout = d
+	}
+}
+
+// lookupUnit returns the unit registered under name, or the latest unit
+// added under this base name. regMu must be held.
+func (m *Manager) lookupUnit(name string) *WorkUnitManager {
+	if w, ok := m.workers[name]; ok {
+		return w
+	}
+	for i := len(m.order) - 1; i >= 0; i-- {
+		if m.order[i].base == name {
+			return m.order[i]
+		}
+	}
+	return nil
+}
+
+// unitDeps returns the dependencies of the unit, and the error of the first
+// unknown one. regMu must be held.
+func (m *Manager) unitDeps(w *WorkUnitManager) ([]*WorkUnitManager, error) {
+	deps := make([]*WorkUnitManager, 0, len(w.after))
+	for _, name := range w.after {
+		d := m.lookupUnit(name)
+		if d == nil || d == w {
+			return deps, fmt.Errorf("unknown dependency <%s>", name)
+		}
+		deps = append(deps, d)
+	}
+	return deps, nil
+}
+
+// validateDeps returns the unknown dependencies and the dependency cycles
+// of the registered units. regMu must be held.
+func (m *Manager) validateDeps() []error {
+	var errs []error
+	const (
+		visiting = iota + 1
+		visited
+	)
+	marks := make(map[*WorkUnitManager]int, len(m.order))
+
+	var visit func(w *WorkUnitManager, path []string)
+	visit = func(w *WorkUnitManager, path []string) {
+		path = append(path, w.name)
+		switch marks[w] {
+		case visiting:
+			cycle := path[slices.Index(path, w.name):]
+			errs = append(errs, fmt.Errorf("dependency cycle: <%s>", strings.Join(cycle, "> -> <")))
+			return
+		case visited:
+			return
+		}
+
+		marks[w] = visiting
+		deps, _ := m.unitDeps(w)
+		for _, d := range deps {
+			visit(d, path)
+		}
+		marks[w] = visited
+	}
+
+	for _, w := range m.order {
+		if _, err := m.unitDeps(w); err != nil {
+			errs = append(errs, fmt.Errorf("unit <%s>: %w", w.name, err))
+		}
+		visit(w, nil)
+	}
+	return errs
+}
+
+// dependencyOrder returns the units sorted so each unit comes after its
+// dependencies, keeping the registration order otherwise.
+func (m *Manager) dependencyOrder(units []*WorkUnitManager) []*WorkUnitManager {
+	m.regMu.RLock()
+	defer m.regMu.RUnlock()
+
+	in := make(map[*WorkUnitManager]bool, len(units))
+	for _, w := range units {
+		in[w] = true
+	}
+	seen := make(map[*WorkUnitManager]bool, len(units))
+	sorted := make([]*WorkUnitManager, 0, len(units))
+
+	var visit func(w *WorkUnitManager)
+	visit = func(w *WorkUnitManager) {
+		if seen[w] {
+			return
+		}
+		seen[w] = true
+		deps, _ := m.unitDeps(w)
+		for _, d := range deps {
+			if in[d] {
+				visit(d)
+			}
+		}
+		sorted = append(sorted, w)
+	}
+
+	for _, w := range units {
+		visit(w)
+	}
+	return sorted
+}
+
+// waitDeps blocks until the dependencies of the unit are ready, done or
+// disabled. It reports false if the startup was interrupted first, or if a
+// dependency timed out and the unit failed.
+func (m *Manager) waitDeps(w *WorkUnitManager) bool {
+	if len(w.after) == 0 {
+		return true
+	}
+
+	start := time.Now()
+	var waiting *WorkUnitManager
+	for {
+		m.regMu.Lock()
+		deps, _ := m.unitDeps(w)
+		var pending []*WorkUnitManager
+		var timeouts []time.Duration
+		var failed *WorkUnitManager
+		for i, d := range deps {
+			if !d.ready && !d.done.Load() && d.state != Disabled {
+				pending = append(pending, d)
+				timeouts = append(timeouts, m.dependencyTimeout(w, w.after[i]))
+			}
+			if d.state == Failed && d.failurePolicy == FailureContain && !d.ready && !d.restarting.Load() {
+				failed = d
+			}
+		}
+		changed := m.changedC()
+		m.regMu.Unlock()
+
+		if len(pending) == 0 {
+			return true
+		}
+		if failed != nil {
+			m.dependencyFailed(w, failed)
+			return false
+		}
+		if pending[0] != waiting {
+			waiting = pending[0]
+			m.unitLogf(w.name, "<%s> waiting for <%s> to be ready\n", w, waiting)
+		}
+
+		// The dependency closest to its timeout
+		var late *WorkUnitManager
+		var left time.Duration
+		for i, d := range pending {
+			if timeouts[i] > 0 && (late == nil || timeouts[i]-time.Since(start) < left) {
+				late, left = d, timeouts[i]-time.Since(start)
+			}
+		}
+		if late != nil && left <= 0 {
+			m.dependencyTimedOut(w, late, time.Since(start))
+			return false
+		}
+
+		var timer *time.Timer
+		var timeout <-chan time.Time
+		if late != nil {
+			timer = time.NewTimer(left)
+			timeout = timer.C
+		}
+
+		select {
+		case <-changed:
+		case <-timeout:
+		case <-m.startStop:
+			return false
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// dependencyTimeout returns the timeout of the dependency of the unit
+// declared under name, zero without timeout.
+func (m *Manager) dependencyTimeout(w *WorkUnitManager, name string) time.Duration {
+	if d, ok := w.afterWithin[name]; ok {
+		return d
+	}
+	return m.depTimeout
+}
+
+// dependencyTimedOut fails the unit whose dependency was not ready in time.
+func (m *Manager) dependencyTimedOut(w, dep *WorkUnitManager, waited time.Duration) {
+	err := fmt.Errorf("%w: <%s> waited %s for <%s> to be ready", ErrDependencyTimeout, w, waited.Round(time.Millisecond), dep)
+	m.warnf(w.name, "%s\n", err)
+	m.setTrace(w, m.newTraceID())
+	m.setState(w, Failed, err)
+	m.unitPanic(w, err)
+}
+
+// dependencyFailed fails the unit whose dependency failed before being ready.
+func (m *Manager) dependencyFailed(w, dep *WorkUnitManager) {
+	err := fmt.Errorf("%w: <%s> depends on <%s>", ErrDependencyFailed, w, dep)
+	m.warnf(w.name, "%s\n", err)
+	m.setTrace(w, m.newTraceID())
+	m.setState(w, Failed, err)
+	m.unitPanic(w, err)
+}
+
+// dependents returns the units depending on each of the given units.
+func (m *Manager) dependents(units []*WorkUnitManager) map[*WorkUnitManager][]*WorkUnitManager {
+	m.regMu.RLock()
+	defer m.regMu.RUnlock()
+
+	dependents := make(map[*WorkUnitManager][]*WorkUnitManager)
+	for _, w := range units {
+		deps, _ := m.unitDeps(w)
+		for _, d := range deps {
+			dependents[d] = append(dependents[d], w)
+		}
+	}
+	return dependents
+}
+
+// unitsRunning reports whether any of the units was started and is not done.
+func unitsRunning(units []*WorkUnitManager) bool {
+	for _, w := range units {
+		if w.started.Load() && !w.done.Load() {
+			return true
+		}
+	}
+	return false
+}
+
+// startAfterDeps starts a unit added while the manager is running once its
+// dependencies are ready.
+func (m *Manager) startAfterDeps(w *WorkUnitManager) {
+	if !m.waitDeps(w) {
+		return
+	}
+
+	m.startMu.Lock()
+	defer m.startMu.Unlock()
+
+	select {
+	case <-m.startStop:
+		return
+	default:
+	}
+	if !w.removed.Load() {
+		m.startUnit(w)
+	}
+}
+
+// lastDrained returns the last of the units to be done during the shutdown,
+// given the order in which they were drained.
+func lastDrained(units []*WorkUnitManager, drained map[*WorkUnitManager]int) *WorkUnitManager {
+	var last *WorkUnitManager
+	for _, w := range units {
+		if drained[w] > drained[last] {
+			last = w
+		}
+	}
+	return last
+}
+
+// recordShutdownChain records the chain of dependencies which governed the
+// end of the shutdown: from the last unit done, the dependents which held
+// back its stop, one per unit. Chains of a single unit are not recorded.
+func (m *Manager) recordShutdownChain(last *WorkUnitManager, releasedBy map[*WorkUnitManager]*WorkUnitManager) {
+	var chain []string
+	for w := last; w != nil; w = releasedBy[w] {
+		chain = append(chain, w.name)
+	}
+	if len(chain) < 2 {
+		return
+	}
+	slices.Reverse(chain)
+
+	m.regMu.Lock()
+	m.shutdownChain = chain
+	m.regMu.Unlock()
+	m.unitLogf("", "Shutdown ordered by dependencies: <%s>\n", strings.Join(chain, "> -> <"))
+}
@@ -0,0 +1,171 @@
+package gum
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// depWorker logs its start and its stop, and is ready after a delay
+type depWorker struct {
+	name  string
+	delay time.Duration
+	log   *teardownLog
+}
+
+func (w *depWorker) Run(um UnitManager) {
+	w.log.add("start " + w.name)
+	time.Sleep(w.delay)
+	um.Ready()
+	<-um.ShouldStop()
+	time.Sleep(w.delay)
+	w.log.add("stop " + w.name)
+	um.Done()
+}
+
+func TestAfter(t *testing.T) {
+	log := &teardownLog{}
+	manager := NewManager()
+	manager.AddUnit(&depWorker{name: "api", log: log}, "api", After("db"))
+	manager.AddUnit(&depWorker{name: "db", delay: 20 * time.Millisecond, log: log}, "db")
+
+	sub := manager.Subscribe()
+	quit := runAsync(manager)
+	waitEvent(t, sub, EventStartupComplete)
+	manager.Stop()
+	<-quit
+
+	want := []string{"start db", "start api", "stop api", "stop db"}
+	if got := log.order; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if manager.Err() != nil {
+		t.Fatalf("unexpected shutdown cause: %v", manager.Err())
+	}
+}
+
+func TestAfterRunning(t *testing.T) {
+	log := &teardownLog{}
+	manager := NewManager()
+	manager.AddUnit(&depWorker{name: "db", log: log}, "", WithName("db"))
+
+	sub := manager.Subscribe()
+	quit := runAsync(manager)
+	waitEvent(t, sub, EventStartupComplete)
+
+	manager.AddUnit(&depWorker{name: "api", log: log}, "", WithName("api"), After("db"))
+	manager.AddUnit(&depWorker{name: "lost", log: log}, "", WithName("lost"), After("unknown"))
+	waitEvent(t, sub, EventUnitReady)
+	manager.Stop()
+	<-quit
+
+	want := []string{"start db", "start api", "stop api", "stop db"}
+	if got := log.order; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestAfterValidate(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&stopWorker{}, "", WithName("a"), After("b"))
+	manager.AddUnit(&stopWorker{}, "", WithName("b"), After("c"))
+	manager.AddUnit(&stopWorker{}, "", WithName("c"), After("b"))
+	manager.AddUnit(&stopWorker{}, "", WithName("d"), After("missing"))
+
+	err := manager.Validate()
+	if err == nil {
+		t.Fatal("expected the dependencies to be invalid")
+	}
+	for _, want := range []string{"dependency cycle: <b> -> <c> -> <b>", "unit <d>: unknown dependency <missing>"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in %v", want, err)
+		}
+	}
+}
+
+func TestAfterWithin(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(funcWorker(func(um UnitManager) {
+		<-um.ShouldStop() // Never ready
+		um.Done()
+	}), "", WithName("db"))
+	manager.AddUnit(&depWorker{name: "api", log: &teardownLog{}}, "", WithName("api"), AfterWithin(20*time.Millisecond, "db"))
+
+	quit := runAsync(manager)
+	select {
+	case <-quit:
+	case <-time.After(time.Second):
+		t.Fatal("expected the dependency timeout to shut the manager down")
+	}
+
+	err := manager.Err()
+	if !errors.Is(err, ErrDependencyTimeout) || !strings.Contains(err.Error(), "<api> waited ") || !strings.Contains(err.Error(), " for <db> to be ready") {
+		t.Fatalf("unexpected shutdown cause: %v", err)
+	}
+	if u, _ := manager.Status("api"); u.State != Failed {
+		t.Fatalf("expected the waiting unit to fail, got %s", u.State)
+	}
+
+	// Dependencies ready in time
+	log := &teardownLog{}
+	manager = NewManager(WithDependencyTimeout(time.Second))
+	manager.AddUnit(&depWorker{name: "db", delay: 10 * time.Millisecond, log: log}, "", WithName("db"))
+	manager.AddUnit(&depWorker{name: "api", log: log}, "", WithName("api"), After("db"))
+
+	sub := manager.Subscribe()
+	quit = runAsync(manager)
+	waitEvent(t, sub, EventStartupComplete)
+	manager.Stop()
+	<-quit
+	if manager.Err() != nil {
+		t.Fatalf("unexpected shutdown cause: %v", manager.Err())
+	}
+}
+
+func TestAfterWithinContained(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(funcWorker(func(um UnitManager) {
+		<-um.ShouldStop() // Never ready
+		um.Done()
+	}), "", WithName("db"))
+	manager.AddUnit(&readyWorker{}, "", WithName("exporter"), AfterWithin(20*time.Millisecond, "db"), WithFailurePolicy(FailureContain))
+	manager.AddUnit(&readyWorker{}, "", WithName("sink"), After("exporter"), WithFailurePolicy(FailureContain))
+	manager.AddUnit(&readyWorker{}, "", WithName("api"))
+
+	// The startup goes on past the contained failures
+	quit := runAsync(manager)
+	waitUnitState(t, manager, "api", Running)
+	for name, want := range map[string]error{"exporter": ErrDependencyTimeout, "sink": ErrDependencyFailed} {
+		waitUnitState(t, manager, name, Failed)
+		if u, _ := manager.Status(name); !errors.Is(u.Err, want) {
+			t.Errorf("expected <%s> to fail with %v, got %v", name, want, u.Err)
+		}
+	}
+
+	manager.Stop()
+	<-quit
+	if manager.Err() != nil {
+		t.Fatalf("expected contained failures not to be a shutdown cause, got %v", manager.Err())
+	}
+}
+
+func TestShutdownChain(t *testing.T) {
+	log := &teardownLog{}
+	manager := NewManager()
+	manager.AddUnit(&depWorker{name: "db", delay: 5 * time.Millisecond, log: log}, "", WithName("db"))
+	manager.AddUnit(&depWorker{name: "cache", delay: 5 * time.Millisecond, log: log}, "", WithName("cache"), After("db"))
+	manager.AddUnit(&depWorker{name: "api", delay: 5 * time.Millisecond, log: log}, "", WithName("api"), After("cache"))
+	manager.AddUnit(&depWorker{name: "metrics", log: log}, "", WithName("metrics"))
+
+	sub := manager.Subscribe()
+	quit := runAsync(manager)
+	waitEvent(t, sub, EventStartupComplete)
+	manager.Stop()
+	<-quit
+
+	want := "api,cache,db"
+	if got := strings.Join(manager.Summary().Chain, ","); got != want {
+		t.Fatalf("expected the shutdown chain %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,93 @@
+package gum
+
+import "context"
+
+// Registrar registers the process in a service discovery system such as
+// Consul, etcd or DNS-SD.
+type Registrar interface {
+	Register(ctx context.Context) error
+	Deregister(ctx context.Context) error
+}
+
+// RegistrarFuncs adapts a pair of functions to the Registrar interface.
+type RegistrarFuncs struct {
+	RegisterFunc   func(ctx context.Context) error
+	DeregisterFunc func(ctx context.Context) error
+}
+
+// Register calls RegisterFunc if set.
+func (r RegistrarFuncs) Register(ctx context.Context) error {
+	if r.RegisterFunc == nil {
+		return nil
+	}
+	return r.RegisterFunc(ctx)
+}
+
+// Deregister calls DeregisterFunc if set.
+func (r RegistrarFuncs) Deregister(ctx context.Context) error {
+	if r.DeregisterFunc == nil {
+		return nil
+	}
+	return r.DeregisterFunc(ctx)
+}
+
+// WithRegistrar adds a registrar. The process is registered once all units
+// are ready (or done or disabled), and deregistered as soon as the shutdown
+// starts, before the units are asked to stop, so no traffic is routed to a
+// draining process. Registrars are deregistered in reverse order.
+func WithRegistrar(r Registrar) Option {
+	return func(m *Manager) {
+		m.registrars = append(m.registrars, r)
+	}
+}
+
+// register waits for all units to be ready and registers the process. It
+// gives up when the shutdown starts first.
+func (m *Manager) register() {
+	defer close(m.regDone)
+
+	select {
+	case <-m.allReady:
+	case <-m.drainC:
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-m.drainC:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for _, r := range m.registrars {
+		if err := r.Register(ctx); err != nil {
+			m.warnf("", "Could not register: %s\n", err)
+			m.emit(EventRegistered, "", err)
+			continue
+		}
+		m.registered = append(m.registered, r)
+		m.emit(EventRegistered, "", nil)
+	}
+}
+
+// deregister interrupts a pending registration and deregisters the process.
+// It must be called once, when the shutdown starts.
+func (m *Manager) deregister(ctx context.Context) {
+	if m.registrars == nil {
+		return
+	}
+
+	close(m.drainC)
+	<-m.regDone
+
+	for i := len(m.registered) - 1; i >= 0; i-- {
+		err := m.registered[i].Deregister(ctx)
+		if err != nil {
+			m.warnf("", "Could not deregister: %s\n", err)
+		}
+		m.emit(EventDeregistered, "", err)
+	}
+}
@@ -0,0 +1,85 @@
+package gum
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// readyWorker is ready once started
+type readyWorker struct{}
+
+func (w *readyWorker) Run(um UnitManager) {
+	um.Ready()
+	<-um.ShouldStop()
+	um.Done()
+}
+
+func TestRegistrar(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	record := func(call string) func(context.Context) error {
+		return func(context.Context) error {
+			mu.Lock()
+			calls = append(calls, call)
+			mu.Unlock()
+			return nil
+		}
+	}
+
+	manager := NewManager(
+		WithRegistrar(RegistrarFuncs{record("register a"), record("deregister a")}),
+		WithRegistrar(RegistrarFuncs{record("register b"), record("deregister b")}),
+	)
+	manager.AddUnit(&readyWorker{}, "")
+	manager.AddUnit(&readyWorker{}, "")
+	sub := manager.Subscribe()
+
+	go manager.Run()
+
+	for registered := 0; registered < 2; {
+		select {
+		case ev := <-sub.Events():
+			if ev.Kind == EventRegistered {
+				registered++
+			}
+		case <-time.After(time.Second):
+			t.Fatal("process was not registered")
+		}
+	}
+
+	manager.Stop()
+	<-manager.Quit
+
+	want := []string{"register a", "register b", "deregister b", "deregister a"}
+	if len(calls) != len(want) {
+		t.Fatalf("expected calls %v, got %v", want, calls)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Fatalf("expected calls %v, got %v", want, calls)
+		}
+	}
+}
+
+func TestRegistrarNotReady(t *testing.T) {
+	registered := false
+	manager := NewManager(WithRegistrar(RegistrarFuncs{
+		RegisterFunc: func(context.Context) error {
+			registered = true
+			return nil
+		},
+	}))
+	manager.AddUnit(&readyWorker{}, "")
+	manager.AddUnit(&stopWorker{}, "") // Never ready
+
+	go manager.Run()
+	time.Sleep(10 * time.Millisecond)
+	manager.Stop()
+	<-manager.Quit
+
+	if registered {
+		t.Fatal("expected no registration before all units are ready")
+	}
+}
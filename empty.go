@@ -0,0 +1,104 @@
+package gum
+
+import "fmt"
+
+// EmptyPolicy defines what the manager does when no unit is registered.
+type EmptyPolicy int
+
+const (
+	// EmptyIdle runs the manager idle until it is stopped (default).
+	EmptyIdle EmptyPolicy = iota
+
+	// EmptyWarn runs the manager idle and logs a warning.
+	EmptyWarn
+
+	// EmptyError refuses to start the manager, the shutdown cause is
+	// ErrStartup and ErrNoUnits.
+	EmptyError
+)
+
+var emptyPolicyNames = [...]string{
+	EmptyIdle:  "idle",
+	EmptyWarn:  "warn",
+	EmptyError: "error",
+}
+
+func (p EmptyPolicy) String() string {
+	if p >= 0 && int(p) < len(emptyPolicyNames) {
+		return emptyPolicyNames[p]
+	}
+	return fmt.Sprintf("EmptyPolicy(%d)", int(p))
+}
+
+func parseEmptyPolicy(s string) (EmptyPolicy, error) {
+	for p, name := range emptyPolicyNames {
+		if name == s {
+			return EmptyPolicy(p), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown empty policy %q", s)
+}
+
+// WithEmptyPolicy sets the policy applied when Run is called without any
+// registered unit, to catch configuration mistakes such as an empty
+// topology. Whatever the policy, an EventNoUnits event is published when the
+// manager runs without units, at startup or once the last running unit is
+// done.
+func WithEmptyPolicy(p EmptyPolicy) Option {
+	return func(m *Manager) {
+		if p < EmptyIdle || p > EmptyError {
+			m.invalid(fmt.Errorf("invalid empty policy %d", int(p)))
+			return
+		}
+		m.emptyPolicy = p
+	}
+}
+
+// liveUnits returns the number of units which are running or still to be
+// started.
+func (m *Manager) liveUnits() int {
+	m.regMu.RLock()
+	defer m.regMu.RUnlock()
+
+	n := 0
+	for _, w := range m.order {
+		if w.state != Disabled && !w.done.Load() {
+			n++
+		}
+	}
+	return n
+}
+
+// checkEmpty publishes an EventNoUnits event if no unit is running once the
+// given unit is done, nil at startup. Failed units are skipped as they shut
+// the manager down, as are restarted units and units done while the manager
+// is shutting down.
+func (m *Manager) checkEmpty(done *WorkUnitManager) {
+	select {
+	case <-m.startStop:
+		return
+	default:
+	}
+
+	if done != nil {
+		if done.restarting.Load() {
+			return
+		}
+
+		m.regMu.RLock()
+		failed := done.state == Failed
+		m.regMu.RUnlock()
+		if failed {
+			return
+		}
+	}
+
+	if m.liveUnits() > 0 || !m.idle.CompareAndSwap(false, true) {
+		return
+	}
+
+	if m.emptyPolicy == EmptyWarn {
+		m.warnf("", "Warning: no unit is running, the manager is idle\n")
+	}
+	m.emit(EventNoUnits, "", nil)
+}
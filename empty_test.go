@@ -0,0 +1,74 @@
+package gum
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// oneShotWorker is done right away
+type oneShotWorker struct{}
+
+func (w *oneShotWorker) Run(um UnitManager) {
+	um.Done()
+}
+
+func TestEmptyError(t *testing.T) {
+	manager := NewManager(WithEmptyPolicy(EmptyError))
+	manager.Run()
+
+	if !errors.Is(manager.Err(), ErrStartup) || !errors.Is(manager.Err(), ErrNoUnits) {
+		t.Fatalf("expected a startup failure without units, got %v", manager.Err())
+	}
+}
+
+func TestEmptyIdle(t *testing.T) {
+	manager := NewManager(WithEmptyPolicy(EmptyWarn))
+	sub := manager.Subscribe()
+
+	go manager.Run()
+
+	waitEvent(t, sub, EventNoUnits)
+	manager.Stop()
+	<-manager.Quit
+
+	if manager.Err() != nil {
+		t.Fatalf("unexpected shutdown cause: %v", manager.Err())
+	}
+}
+
+func TestNoUnitsAtRuntime(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&oneShotWorker{}, "")
+	manager.AddUnit(&oneShotWorker{}, "")
+	sub := manager.Subscribe()
+
+	go manager.Run()
+
+	waitEvent(t, sub, EventNoUnits)
+	manager.Stop()
+	<-manager.Quit
+	sub.Close()
+
+	for ev := range sub.Events() {
+		if ev.Kind == EventNoUnits {
+			t.Fatal("expected a single no-units event")
+		}
+	}
+}
+
+func waitEvent(t *testing.T, sub *Subscription, kind EventKind) Event {
+	t.Helper()
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case ev := <-sub.Events():
+			if ev.Kind == kind {
+				return ev
+			}
+		case <-timeout:
+			t.Fatalf("no %s event", kind)
+		}
+	}
+}
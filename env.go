@@ -0,0 +1,190 @@
+package gum
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// DefaultEnvPrefix is the prefix of the environment variables read by
+// WithEnv("").
+const DefaultEnvPrefix = "GUM"
+
+// Environment variables read by WithEnv, after the prefix and an underscore.
+const (
+	EnvProfile            = "PROFILE"             // dev, prod or test
+	EnvShutdownTimeout    = "SHUTDOWN_TIMEOUT"    // Duration, e.g. 30s
+	EnvStartupConcurrency = "STARTUP_CONCURRENCY" // Integer
+	EnvPanicPolicy        = "PANIC_POLICY"        // shutdown, rethrow or exit
+	EnvPanicExitCode      = "PANIC_EXIT_CODE"     // Integer
+	EnvHistoryFile        = "HISTORY_FILE"        // Path
+	EnvEmptyPolicy        = "EMPTY_POLICY"        // idle, warn or error
+	EnvShutdownReport     = "SHUTDOWN_REPORT"     // Path
+	EnvLogVerbosity       = "LOG_VERBOSITY"       // quiet, normal or verbose
+	EnvLogSeverity        = "LOG_SEVERITY"        // debug, info, warn, error, critical or none
+	EnvRestartPolicy      = "RESTART_POLICY"      // never, on-failure or always
+	EnvRestartIntensity   = "RESTART_INTENSITY"   // Integer, restarts allowed within the period
+	EnvRestartPeriod      = "RESTART_PERIOD"      // Duration, e.g. 30s
+	EnvControlAddr        = "CONTROL_ADDR"        // Address, e.g. 127.0.0.1:7070
+)
+
+// WithEnv overlays the manager settings with the environment variables
+// named after the prefix, e.g. GUM_SHUTDOWN_TIMEOUT. The environment is read
+// once all the other options are applied so it takes precedence over them,
+// except for the profile which is a preset applied before all the options,
+// as the options override a profile, see WithProfile. The control address
+// serves the ControlHandler, see WithControlAddr.
+// The log verbosity and severity are set as with Tune, the restart variables
+// are unit defaults, see WithUnitDefaults, overridden by the unit options.
+// Invalid values are reported by Validate.
+func WithEnv(prefix string) Option {
+	return func(m *Manager) {
+		if prefix == "" {
+			prefix = DefaultEnvPrefix
+		}
+		m.envPrefix = prefix
+	}
+}
+
+// lookupEnv returns the value of the environment variable name read by
+// WithEnv.
+func (m *Manager) lookupEnv(name string) (string, bool) {
+	if m.envPrefix == "" {
+		return "", false
+	}
+	return os.LookupEnv(m.envPrefix + "_" + name)
+}
+
+// envProfile returns the valid profile set in the environment read by
+// WithEnv, applied by NewManager before the options.
+func (m *Manager) envProfile() (Profile, bool) {
+	v, ok := m.lookupEnv(EnvProfile)
+	if !ok {
+		return 0, false
+	}
+	profile, err := parseProfile(v)
+	return profile, err == nil
+}
+
+func (m *Manager) applyEnv() {
+	if m.envPrefix == "" {
+		return
+	}
+
+	invalid := func(name, value string, err error) {
+		m.invalid(fmt.Errorf("invalid %s_%s=%q: %w", m.envPrefix, name, value, err))
+	}
+
+	// The valid profile was applied by NewManager
+	if v, ok := m.lookupEnv(EnvProfile); ok {
+		if _, err := parseProfile(v); err != nil {
+			invalid(EnvProfile, v, err)
+		}
+	}
+
+	for _, s := range []struct{ env, setting string }{
+		{EnvLogVerbosity, SettingLogVerbosity},
+		{EnvLogSeverity, SettingLogSeverity},
+	} {
+		if v, ok := m.lookupEnv(s.env); ok {
+			if err := settings[s.setting].set(m, v); err != nil {
+				invalid(s.env, v, err)
+			}
+		}
+	}
+
+	if v, ok := m.lookupEnv(EnvRestartPolicy); ok {
+		policy, err := parseRestartPolicy(v)
+		if err != nil {
+			invalid(EnvRestartPolicy, v, err)
+		} else {
+			m.unitDefaults = append(m.unitDefaults, WithRestart(policy))
+		}
+	}
+
+	if v, ok := m.lookupEnv(EnvRestartIntensity); ok {
+		n, err := strconv.Atoi(v)
+		if err == nil && n < 0 {
+			err = fmt.Errorf("negative restart intensity: %d", n)
+		}
+		if err != nil {
+			invalid(EnvRestartIntensity, v, err)
+		} else {
+			m.unitDefaults = append(m.unitDefaults, func(w *WorkUnitManager) {
+				w.restartIntensity = n
+			})
+		}
+	}
+
+	if v, ok := m.lookupEnv(EnvRestartPeriod); ok {
+		d, err := time.ParseDuration(v)
+		if err == nil && d <= 0 {
+			err = fmt.Errorf("non-positive restart period: %s", d)
+		}
+		if err != nil {
+			invalid(EnvRestartPeriod, v, err)
+		} else {
+			m.unitDefaults = append(m.unitDefaults, func(w *WorkUnitManager) {
+				w.restartPeriod = d
+			})
+		}
+	}
+
+	if v, ok := m.lookupEnv(EnvShutdownTimeout); ok {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			invalid(EnvShutdownTimeout, v, err)
+		} else {
+			WithShutdownTimeout(d)(m)
+		}
+	}
+
+	if v, ok := m.lookupEnv(EnvStartupConcurrency); ok {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			invalid(EnvStartupConcurrency, v, err)
+		} else {
+			WithStartupConcurrency(n)(m)
+		}
+	}
+
+	if v, ok := m.lookupEnv(EnvPanicPolicy); ok {
+		policy, err := parsePanicPolicy(v)
+		if err != nil {
+			invalid(EnvPanicPolicy, v, err)
+		} else {
+			WithPanicPolicy(policy)(m)
+		}
+	}
+
+	if v, ok := m.lookupEnv(EnvPanicExitCode); ok {
+		code, err := strconv.Atoi(v)
+		if err != nil {
+			invalid(EnvPanicExitCode, v, err)
+		} else {
+			WithPanicExitCode(code)(m)
+		}
+	}
+
+	if v, ok := m.lookupEnv(EnvHistoryFile); ok {
+		WithHistoryFile(v)(m)
+	}
+
+	if v, ok := m.lookupEnv(EnvShutdownReport); ok {
+		WithShutdownReport(v)(m)
+	}
+
+	if v, ok := m.lookupEnv(EnvControlAddr); ok {
+		WithControlAddr(v)(m)
+	}
+
+	if v, ok := m.lookupEnv(EnvEmptyPolicy); ok {
+		policy, err := parseEmptyPolicy(v)
+		if err != nil {
+			invalid(EnvEmptyPolicy, v, err)
+		} else {
+			WithEmptyPolicy(policy)(m)
+		}
+	}
+}
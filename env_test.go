@@ -0,0 +1,91 @@
+package gum
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithEnv(t *testing.T) {
+	t.Setenv("APP_SHUTDOWN_TIMEOUT", "3s")
+	t.Setenv("APP_STARTUP_CONCURRENCY", "4")
+	t.Setenv("APP_PANIC_POLICY", "exit")
+	t.Setenv("APP_PANIC_EXIT_CODE", "9")
+	t.Setenv("APP_EMPTY_POLICY", "warn")
+	t.Setenv("APP_LOG_VERBOSITY", "verbose")
+	t.Setenv("APP_LOG_SEVERITY", "error")
+	t.Setenv("APP_RESTART_POLICY", "on-failure")
+	t.Setenv("APP_RESTART_INTENSITY", "3")
+	t.Setenv("APP_RESTART_PERIOD", "1m")
+
+	// The environment overlays the programmatic options
+	manager := NewManager(WithEnv("APP"), WithShutdownTimeout(time.Second))
+
+	if err := manager.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if manager.shutdownTimeout != 3*time.Second {
+		t.Errorf("unexpected shutdown timeout %s", manager.shutdownTimeout)
+	}
+	if cap(manager.startSem) != 4 {
+		t.Errorf("unexpected startup concurrency %d", cap(manager.startSem))
+	}
+	if manager.panicPolicy != PanicExit {
+		t.Errorf("unexpected panic policy %s", manager.panicPolicy)
+	}
+	if code := lookupExitCode(manager.exitCodes, ErrUnitPanic); code != 9 {
+		t.Errorf("unexpected panic exit code %d", code)
+	}
+	if manager.emptyPolicy != EmptyWarn {
+		t.Errorf("unexpected empty policy %s", manager.emptyPolicy)
+	}
+	if manager.verbosity != logVerbose || manager.logSeverity != SeverityError {
+		t.Errorf("unexpected log verbosity %d and severity %s", manager.verbosity, manager.logSeverity)
+	}
+
+	// The restart defaults are overridden by the unit options
+	w := manager.newUnit(&readyWorker{}, "")
+	if w.restartPolicy != RestartOnFailure || w.restartIntensity != 3 || w.restartPeriod != time.Minute {
+		t.Errorf("unexpected restart defaults %s, %d within %s", w.restartPolicy, w.restartIntensity, w.restartPeriod)
+	}
+	w = manager.newUnit(&readyWorker{}, "", WithRestart(RestartNever))
+	if w.restartPolicy != RestartNever || w.restartIntensity != 3 {
+		t.Errorf("unexpected restart policy %s, %d restarts", w.restartPolicy, w.restartIntensity)
+	}
+}
+
+func TestWithEnvInvalid(t *testing.T) {
+	t.Setenv("GUM_SHUTDOWN_TIMEOUT", "soon")
+	t.Setenv("GUM_PANIC_POLICY", "ignore")
+	t.Setenv("GUM_LOG_VERBOSITY", "loud")
+	t.Setenv("GUM_RESTART_POLICY", "sometimes")
+	t.Setenv("GUM_RESTART_INTENSITY", "-1")
+	t.Setenv("GUM_PROFILE", "staging")
+
+	err := NewManager(WithEnv("")).Validate()
+	if err == nil {
+		t.Fatal("expected invalid environment to be reported")
+	}
+	for _, name := range []string{"GUM_SHUTDOWN_TIMEOUT", "GUM_PANIC_POLICY", "GUM_LOG_VERBOSITY", "GUM_RESTART_POLICY", "GUM_RESTART_INTENSITY", "GUM_PROFILE"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("expected %s to be reported, got: %v", name, err)
+		}
+	}
+}
+
+func TestEnvProfileOverridden(t *testing.T) {
+	t.Setenv("GUM_PROFILE", "prod")
+	t.Setenv("GUM_CONTROL_ADDR", "127.0.0.1:7070")
+
+	// The options override the profile, as with WithProfile
+	manager := NewManager(WithShutdownTimeout(time.Second), WithEnv(""))
+	if manager.shutdownTimeout != time.Second {
+		t.Errorf("expected the option to override the profile, got %s", manager.shutdownTimeout)
+	}
+	if !manager.runtimeMetrics {
+		t.Error("expected the profile to be applied")
+	}
+	if manager.controlAddr != "127.0.0.1:7070" {
+		t.Errorf("unexpected control address %q", manager.controlAddr)
+	}
+}
@@ -0,0 +1,111 @@
+package gum
+
+import (
+	"errors"
+)
+
+var (
+	// ErrUnitPanic is the shutdown cause when a unit called Panic.
+	ErrUnitPanic = errors.New("unit panic")
+
+	// ErrStartup is the shutdown cause when the manager failed to start.
+	ErrStartup = errors.New("startup failure")
+
+	// ErrForcedShutdown is the shutdown cause when units were abandoned
+	// before they were done, either forced or after a timeout.
+	ErrForcedShutdown = errors.New("forced shutdown")
+
+	// ErrStartupTimeout is joined to ErrStartup when the units were not
+	// ready within the startup timeout, see WithStartupTimeout.
+	ErrStartupTimeout = errors.New("startup timeout")
+
+	// ErrRestartIntensity is joined to the failure of a unit restarted too
+	// often, see WithRestartIntensity.
+	ErrRestartIntensity = errors.New("restart intensity exceeded")
+
+	// ErrStartRetries is joined to the failure of a unit which failed to
+	// start more often than its start retries, see WithStartRetry.
+	ErrStartRetries = errors.New("start retries exhausted")
+
+	// ErrReadyTimeout is the failure of a unit not ready within its ready
+	// timeout, see WithReadyTimeout.
+	ErrReadyTimeout = errors.New("ready timeout")
+
+	// ErrNotReady is returned by WaitReady when the manager shuts down
+	// before all units are ready.
+	ErrNotReady = errors.New("stopped before being ready")
+
+	// ErrHookTimeout is the error of EventHookTimeout events, published
+	// when a hook exceeded the hook timeout, see WithHookTimeout.
+	ErrHookTimeout = errors.New("hook timeout")
+
+	// ErrFrozen is the error of the topology changes attempted while the
+	// topology is frozen, see Freeze.
+	ErrFrozen = errors.New("topology frozen")
+
+	// ErrResources is joined to ErrStartup when the process limits can't
+	// accommodate the resources declared by the units, see WithFDBudget.
+	ErrResources = errors.New("insufficient resources")
+
+	// ErrReloadTimeout is the error of the units exceeding the reload
+	// timeout, see WithReloadTimeout.
+	ErrReloadTimeout = errors.New("reload timeout")
+
+	// ErrDependencyTimeout is the failure of a unit whose dependency was
+	// not ready in time, see AfterWithin and WithDependencyTimeout.
+	ErrDependencyTimeout = errors.New("dependency timeout")
+
+	// ErrDependencyFailed is the failure of a unit whose dependency failed
+	// for good before being ready, e.g. a contained failure, see
+	// WithFailurePolicy.
+	ErrDependencyFailed = errors.New("dependency failed")
+
+	// ErrTaskNotRun is the result of the tasks which did not run, e.g.
+	// because the manager quit first, see AddTask.
+	ErrTaskNotRun = errors.New("task not run")
+
+	// ErrNoUnits is reported by Validate when no unit is registered and
+	// the empty policy is EmptyError.
+	ErrNoUnits = errors.New("no units registered")
+)
+
+// Default process exit codes by shutdown cause.
+const (
+	ExitOK      = 0
+	ExitFailure = 1
+	ExitPanic   = 2
+	ExitStartup = 3
+	ExitForced  = 4
+)
+
+type exitCode struct {
+	cause error
+	code  int
+}
+
+var defaultExitCodes = []exitCode{
+	{ErrForcedShutdown, ExitForced},
+	{ErrStartup, ExitStartup},
+	{ErrUnitPanic, ExitPanic},
+}
+
+// ExitCode returns the default process exit code for the given shutdown
+// cause. A nil error, a clean shutdown, maps to ExitOK and unknown errors to
+// ExitFailure.
+func ExitCode(err error) int {
+	return lookupExitCode(defaultExitCodes, err)
+}
+
+func lookupExitCode(codes []exitCode, err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	for _, c := range codes {
+		if errors.Is(err, c.cause) {
+			return c.code
+		}
+	}
+
+	return ExitFailure
+}
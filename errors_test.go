@@ -0,0 +1,72 @@
+package gum
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		code int
+	}{
+		{nil, ExitOK},
+		{errors.New("unknown"), ExitFailure},
+		{fmt.Errorf("%w: boom", ErrUnitPanic), ExitPanic},
+		{ErrStartup, ExitStartup},
+		{ErrForcedShutdown, ExitForced},
+	}
+
+	for _, tt := range tests {
+		if code := ExitCode(tt.err); code != tt.code {
+			t.Errorf("ExitCode(%v) = %d, want %d", tt.err, code, tt.code)
+		}
+	}
+}
+
+func TestManagerExitCode(t *testing.T) {
+	manager := NewManager(WithExitCode(ErrUnitPanic, 42))
+	manager.AddUnit(&panicWorker{}, "")
+	manager.Run()
+
+	if !errors.Is(manager.Err(), ErrUnitPanic) {
+		t.Fatalf("expected unit panic cause, got %v", manager.Err())
+	}
+	if code := manager.ExitCode(); code != 42 {
+		t.Fatalf("expected exit code 42, got %d", code)
+	}
+}
+
+func TestRunReturnsCause(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&panicWorker{}, "", WithName("failing"))
+	manager.AddUnit(&readyWorker{}, "", WithName("other"))
+
+	err := manager.Run()
+	if !errors.Is(err, ErrUnitPanic) || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expected the unit failure to be returned, got %v", err)
+	}
+	if u, _ := manager.Status("other"); u.State != Stopped {
+		t.Fatalf("expected the failure to stop the other units, got %s", u.State)
+	}
+}
+
+func TestWait(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&readyWorker{}, "")
+
+	sub := manager.Subscribe()
+	go manager.Run()
+	waitEvent(t, sub, EventStartupComplete)
+
+	manager.Stop()
+	if err := manager.Wait(); err != nil {
+		t.Fatalf("unexpected shutdown cause: %v", err)
+	}
+	// Waiting again returns right away
+	if err := manager.Wait(); err != nil {
+		t.Fatalf("unexpected shutdown cause: %v", err)
+	}
+}
@@ -0,0 +1,425 @@
+package gum
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EventKind identifies a lifecycle event.
+type EventKind int
+
+const (
+	EventManagerStarted EventKind = iota
+	EventUnitStarted
+	EventUnitStopping
+	EventUnitDone
+	EventUnitPanic
+	EventUnitAbandoned
+	EventShutdown
+	EventManagerQuit
+	EventUnitReady
+	EventBudgetExceeded
+	EventMemoryExceeded
+	EventRegistered
+	EventDeregistered
+	EventStartupComplete
+	EventUnitParked
+	EventUnitWoken
+	EventRestartDecision
+	EventInternalError
+	EventShutdownPhase
+	EventNoUnits
+	EventUnitRestart
+	EventHookTimeout
+	EventFrozen
+	EventUnfrozen
+	EventChangeBlocked
+	EventSettingChanged
+	EventUnitReloaded
+	EventReplay
+)
+
+var eventKindNames = [...]string{
+	EventManagerStarted:  "manager-started",
+	EventUnitStarted:     "unit-started",
+	EventUnitStopping:    "unit-stopping",
+	EventUnitDone:        "unit-done",
+	EventUnitPanic:       "unit-panic",
+	EventUnitAbandoned:   "unit-abandoned",
+	EventShutdown:        "shutdown",
+	EventManagerQuit:     "manager-quit",
+	EventUnitReady:       "unit-ready",
+	EventBudgetExceeded:  "budget-exceeded",
+	EventMemoryExceeded:  "memory-exceeded",
+	EventRegistered:      "registered",
+	EventDeregistered:    "deregistered",
+	EventStartupComplete: "startup-complete",
+	EventUnitParked:      "unit-parked",
+	EventUnitWoken:       "unit-woken",
+	EventRestartDecision: "restart-decision",
+	EventInternalError:   "internal-error",
+	EventShutdownPhase:   "shutdown-phase",
+	EventNoUnits:         "no-units",
+	EventUnitRestart:     "unit-restart",
+	EventHookTimeout:     "hook-timeout",
+	EventFrozen:          "frozen",
+	EventUnfrozen:        "unfrozen",
+	EventChangeBlocked:   "change-blocked",
+	EventSettingChanged:  "setting-changed",
+	EventUnitReloaded:    "unit-reloaded",
+	EventReplay:          "replay",
+}
+
+func (k EventKind) String() string {
+	if k >= 0 && int(k) < len(eventKindNames) {
+		return eventKindNames[k]
+	}
+	return fmt.Sprintf("EventKind(%d)", int(k))
+}
+
+// Event is a lifecycle event published by the manager. Unit is empty for
+// events concerning the manager itself. Time is the wall clock time of the
+// event, durations are computed from the monotonic clock.
+type Event struct {
+	Kind     EventKind
+	Severity Severity
+	Unit     string
+	Time     time.Time
+	Err      error
+
+	// Stack is the stack trace of the panic of EventUnitPanic events, when
+	// Run panicked, see PanicError.
+	Stack []byte
+
+	// Uptime is the manager uptime when the event occurred.
+	Uptime time.Duration
+
+	// Latency is the time the unit took to be done once asked to stop, for
+	// EventUnitDone events.
+	Latency time.Duration
+
+	// Startup is the startup report of EventStartupComplete events.
+	Startup *StartupReport
+
+	// Build is the build metadata of EventManagerStarted events, see
+	// WithBuildInfo.
+	Build *BuildInfo
+
+	// Restart is the decision of the restart hooks of EventRestartDecision
+	// events, and the delay before the restart of EventUnitRestart events.
+	Restart *RestartDecision
+
+	// Phase is the report of EventShutdownPhase events.
+	Phase *PhaseReport
+
+	// Reason is the reason given to Freeze, of EventFrozen and
+	// EventChangeBlocked events.
+	Reason string
+
+	// Setting is the change of EventSettingChanged events, see Tune.
+	Setting *SettingChange
+
+	// Snapshot is the status of the manager of EventReplay events, see
+	// WithReplay.
+	Snapshot *Snapshot
+
+	// Replayed is set on the events of the history replayed to a new
+	// subscriber, see WithReplay.
+	Replayed bool
+
+	// TraceID correlates the events of a unit restart chain (the restart
+	// decision, the start of the new instance and the stop of the old one)
+	// or of a unit failure (budget exceeded, panic). It is empty for events
+	// unrelated to a restart or a failure.
+	TraceID string
+
+	// Baggage is the manager baggage, see WithBaggage. It is shared by all
+	// events and must not be modified.
+	Baggage map[string]string
+}
+
+func (e Event) String() string {
+	s := e.Kind.String()
+	if e.Unit != "" {
+		s += " <" + e.Unit + ">"
+	}
+	if e.Err != nil {
+		s += ": " + e.Err.Error()
+	}
+	return s
+}
+
+// OverflowPolicy defines what happens when an event is published to a
+// subscription whose buffer is full.
+type OverflowPolicy int
+
+const (
+	// DropOldest discards the oldest buffered event (default).
+	DropOldest OverflowPolicy = iota
+
+	// DropNewest discards the published event.
+	DropNewest
+
+	// Block waits for the subscriber to make room. This applies
+	// back-pressure on the manager and should only be used by subscribers
+	// that are always ready to receive.
+	Block
+)
+
+func (p OverflowPolicy) String() string {
+	switch p {
+	case DropOldest:
+		return "drop_oldest"
+	case DropNewest:
+		return "drop_newest"
+	case Block:
+		return "block"
+	}
+	return "unknown"
+}
+
+// SubscribeOption configures a Subscription.
+type SubscribeOption func(*Subscription)
+
+// WithBufferSize sets the number of events buffered for the subscriber. It
+// defaults to 64.
+func WithBufferSize(n int) SubscribeOption {
+	return func(s *Subscription) {
+		s.size = n
+	}
+}
+
+// WithOverflowPolicy sets the policy applied when the subscriber's buffer is
+// full.
+func WithOverflowPolicy(policy OverflowPolicy) SubscribeOption {
+	return func(s *Subscription) {
+		s.policy = policy
+	}
+}
+
+// Subscription receives the events published by the manager. Every
+// subscription has its own buffer so a slow subscriber can't stall the
+// manager unless it opted in the Block policy.
+type Subscription struct {
+	c       chan Event
+	size    int
+	policy  OverflowPolicy
+	dropped atomic.Uint64
+
+	minSeverity Severity
+	replay      bool
+
+	bus    *eventBus
+	closed chan struct{}
+	once   sync.Once
+}
+
+// Events returns the channel on which events are delivered. It is closed
+// when the subscription is closed.
+func (s *Subscription) Events() <-chan Event {
+	return s.c
+}
+
+// Dropped returns the number of events dropped because the subscriber's
+// buffer was full.
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close stops the delivery of events and closes the events channel.
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		close(s.closed)
+		s.bus.remove(s)
+		close(s.c)
+	})
+}
+
+// drop counts an event dropped by the subscription and by its bus.
+func (s *Subscription) drop(policy OverflowPolicy) {
+	s.dropped.Add(1)
+	if s.bus != nil {
+		s.bus.dropped[policy].Add(1)
+	}
+}
+
+func (s *Subscription) publish(ev Event) {
+	if ev.Severity < s.minSeverity {
+		return
+	}
+
+	switch s.policy {
+	case Block:
+		select {
+		case s.c <- ev:
+		case <-s.closed:
+		}
+
+	case DropNewest:
+		select {
+		case s.c <- ev:
+		default:
+			s.drop(DropNewest)
+		}
+
+	default:
+		for {
+			select {
+			case s.c <- ev:
+				return
+			default:
+			}
+
+			select {
+			case <-s.c:
+				s.drop(DropOldest)
+			default:
+			}
+		}
+	}
+}
+
+// eventBus fans out events to subscriptions.
+type eventBus struct {
+	mu   sync.RWMutex
+	subs []*Subscription
+
+	replayEvent func() Event // Status replayed to new subscribers
+
+	dropped [Block]atomic.Uint64 // By overflow policy, of all subscriptions
+
+	historyMu sync.Mutex
+	history   []Event // Ring buffer, see WithEventHistory
+	next      int
+	full      bool
+}
+
+func (b *eventBus) subscribe(opts ...SubscribeOption) *Subscription {
+	s := &Subscription{
+		size:   64,
+		bus:    b,
+		closed: make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.size < 1 && s.policy != Block {
+		s.size = 1
+	}
+
+	var status *Event
+	if s.replay && b.replayEvent != nil {
+		ev := b.replayEvent()
+		status = &ev
+	}
+
+	// No event is published while the history is replayed, the subscriber
+	// receives each event once and in order.
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var replay []Event
+	if s.replay {
+		replay = b.recent()
+		s.size = max(s.size, len(replay)+1)
+	}
+	s.c = make(chan Event, s.size)
+
+	if status != nil {
+		s.c <- *status
+	}
+	for _, ev := range replay {
+		if ev.Severity >= s.minSeverity {
+			ev.Replayed = true
+			s.c <- ev
+		}
+	}
+
+	b.subs = append(b.subs, s)
+	return s
+}
+
+func (b *eventBus) remove(s *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for i, sub := range b.subs {
+		if sub == s {
+			b.subs = append(b.subs[:i], b.subs[i+1:]...)
+			return
+		}
+	}
+}
+
+func (b *eventBus) publish(ev Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	b.record(ev)
+
+	for _, s := range b.subs {
+		s.publish(ev)
+	}
+}
+
+// droppedEvents returns the events dropped by all subscriptions, closed ones
+// included, by overflow policy.
+func (b *eventBus) droppedEvents() map[string]uint64 {
+	dropped := make(map[string]uint64, len(b.dropped))
+	for p := range b.dropped {
+		dropped[OverflowPolicy(p).String()] = b.dropped[p].Load()
+	}
+	return dropped
+}
+
+// Subscribe returns a subscription to the manager's lifecycle events.
+func (m *Manager) Subscribe(opts ...SubscribeOption) *Subscription {
+	return m.events.subscribe(opts...)
+}
+
+// Events returns a channel receiving the manager's lifecycle events, for
+// consumers selecting on them alongside their own channels, e.g. UIs or
+// tests asserting on the order of the events. Each call returns a channel
+// of its own buffering 256 events, the oldest are dropped when it is full,
+// see Subscribe for finer control. It is closed once the manager quit,
+// after the EventManagerQuit event.
+func (m *Manager) Events() <-chan Event {
+	sub := m.Subscribe(WithBufferSize(256))
+
+	m.notifyMu.Lock()
+	defer m.notifyMu.Unlock()
+	if m.notifyStopped {
+		sub.Close()
+	} else {
+		m.notifySubs = append(m.notifySubs, sub)
+	}
+	return sub.Events()
+}
+
+// emit publishes a lifecycle event to all subscribers.
+func (m *Manager) emit(kind EventKind, unit string, err error) {
+	m.emitEvent(Event{Kind: kind, Unit: unit, Err: err})
+}
+
+// emitEvent timestamps and publishes the event.
+func (m *Manager) emitEvent(ev Event) {
+	ev.Time = m.clock.Now()
+	ev.Baggage = m.baggage
+	ev.Severity = eventSeverity(ev)
+	if perr := (*PanicError)(nil); ev.Kind == EventUnitPanic && errors.As(ev.Err, &perr) {
+		ev.Stack = perr.Stack
+	}
+
+	m.regMu.RLock()
+	ev.Uptime = m.uptime(ev.Time)
+	m.regMu.RUnlock()
+
+	if sev := m.currentLogSeverity(); m.currentVerbosity() >= logVerbose || (sev > 0 && ev.Severity >= sev) {
+		m.logEvent(ev)
+	}
+	m.events.publish(ev)
+}
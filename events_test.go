@@ -0,0 +1,151 @@
+package gum
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSubscriptionDropOldest(t *testing.T) {
+	var bus eventBus
+	sub := bus.subscribe(WithBufferSize(2))
+
+	for kind := EventManagerStarted; kind <= EventUnitDone; kind++ {
+		bus.publish(Event{Kind: kind})
+	}
+
+	if dropped := sub.Dropped(); dropped != 2 {
+		t.Fatalf("expected 2 dropped events, got %d", dropped)
+	}
+	if ev := <-sub.Events(); ev.Kind != EventUnitStopping {
+		t.Fatalf("expected oldest events to be dropped, got %s", ev.Kind)
+	}
+}
+
+func TestSubscriptionDropNewest(t *testing.T) {
+	var bus eventBus
+	sub := bus.subscribe(WithBufferSize(2), WithOverflowPolicy(DropNewest))
+
+	for kind := EventManagerStarted; kind <= EventUnitDone; kind++ {
+		bus.publish(Event{Kind: kind})
+	}
+
+	if dropped := sub.Dropped(); dropped != 2 {
+		t.Fatalf("expected 2 dropped events, got %d", dropped)
+	}
+	if ev := <-sub.Events(); ev.Kind != EventManagerStarted {
+		t.Fatalf("expected newest events to be dropped, got %s", ev.Kind)
+	}
+}
+
+func TestSubscriptionSlowSubscriber(t *testing.T) {
+	var bus eventBus
+	bus.subscribe(WithBufferSize(1))
+	fast := bus.subscribe(WithBufferSize(100))
+
+	done := make(chan bool)
+	go func() {
+		for i := 0; i < 100; i++ {
+			bus.publish(Event{Kind: EventUnitStarted})
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("slow subscriber stalled the publisher")
+	}
+
+	if n := len(fast.Events()); n != 100 {
+		t.Fatalf("expected 100 events for the fast subscriber, got %d", n)
+	}
+}
+
+func TestSubscriptionCloseUnblocks(t *testing.T) {
+	var bus eventBus
+	sub := bus.subscribe(WithBufferSize(0), WithOverflowPolicy(Block))
+
+	done := make(chan bool)
+	go func() {
+		bus.publish(Event{Kind: EventUnitStarted})
+		close(done)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	sub.Close()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("closing the subscription did not unblock the publisher")
+	}
+}
+
+func TestManagerEvents(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&panicWorker{}, "")
+	sub := manager.Subscribe()
+
+	manager.Run()
+	sub.Close()
+
+	var kinds []EventKind
+	for ev := range sub.Events() {
+		// Published asynchronously, see TestStartupReport
+		if ev.Kind == EventStartupComplete {
+			continue
+		}
+		kinds = append(kinds, ev.Kind)
+	}
+
+	want := []EventKind{
+		EventManagerStarted,
+		EventUnitStarted,
+		EventUnitPanic,
+		EventShutdown,
+		EventShutdownPhase, // quiesce
+		EventUnitDone,
+		EventShutdownPhase, // stop
+		EventShutdownPhase, // finalize
+		EventManagerQuit,
+	}
+	if len(kinds) != len(want) {
+		t.Fatalf("expected events %v, got %v", want, kinds)
+	}
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Fatalf("expected events %v, got %v", want, kinds)
+		}
+	}
+}
+
+func TestEventsChannel(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&readyWorker{}, "")
+	events := manager.Events()
+
+	go manager.Run()
+
+	var kinds []EventKind
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				if kinds[0] != EventManagerStarted || kinds[len(kinds)-1] != EventManagerQuit {
+					t.Fatalf("expected the events of the whole run, got %v", kinds)
+				}
+				if _, ok := <-manager.Events(); ok {
+					t.Fatal("expected the channel to be closed once the manager quit")
+				}
+				return
+			}
+			if ev.Kind == EventUnitReady {
+				manager.Stop()
+			}
+			kinds = append(kinds, ev.Kind)
+		case <-timeout:
+			t.Fatalf("expected the channel to be closed, got %v", kinds)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package gum
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const (
+	// DefaultFileWatchInterval is the default interval between two scans of
+	// the watched files.
+	DefaultFileWatchInterval = time.Second
+
+	// DefaultFileWatchDebounce is the default quiet time after a change
+	// before it is delivered.
+	DefaultFileWatchDebounce = 100 * time.Millisecond
+)
+
+// FileWatcher is a unit watching files and directories for changes, e.g. to
+// reload certificates or a configuration file. Directories are watched one
+// level deep. A change is a file created, removed, or whose size, mode or
+// modification time changed.
+//
+//	unit := gum.WatchFiles("/etc/app/config.yaml", "/etc/app/certs").
+//		OnChange(func(changed []string) { reload() })
+//
+// The files are polled, keeping gum free of dependencies. Changes are
+// debounced: they are delivered once no other change was seen for the
+// debounce time, so an editor or a deployment rewriting several files
+// triggers a single reload.
+type FileWatcher struct {
+	paths    []string
+	interval time.Duration
+	debounce time.Duration
+	onChange func(changed []string)
+	changes  chan []string
+}
+
+// WatchFiles returns a FileWatcher watching the given paths.
+func WatchFiles(paths ...string) *FileWatcher {
+	return &FileWatcher{
+		paths:    paths,
+		interval: DefaultFileWatchInterval,
+		debounce: DefaultFileWatchDebounce,
+		changes:  make(chan []string, 1),
+	}
+}
+
+// Interval sets the interval between two scans of the files. Non-positive
+// intervals are ignored.
+func (f *FileWatcher) Interval(d time.Duration) *FileWatcher {
+	if d > 0 {
+		f.interval = d
+	}
+	return f
+}
+
+// Debounce sets the quiet time after a change before it is delivered, zero
+// to deliver changes as soon as they are seen. Negative values are ignored.
+func (f *FileWatcher) Debounce(d time.Duration) *FileWatcher {
+	if d >= 0 {
+		f.debounce = d
+	}
+	return f
+}
+
+// OnChange sets the function called with the sorted paths which changed. It
+// is called from the unit goroutine, a panic fails the unit.
+func (f *FileWatcher) OnChange(onChange func(changed []string)) *FileWatcher {
+	f.onChange = onChange
+	return f
+}
+
+// Changes returns a channel on which the changed paths are delivered, as an
+// alternative to OnChange. Changes not received yet are merged with the
+// following ones, a slow receiver never blocks the watcher.
+func (f *FileWatcher) Changes() <-chan []string {
+	return f.changes
+}
+
+// Run scans the files until the unit is asked to stop. It is ready once the
+// files were scanned a first time.
+func (f *FileWatcher) Run(um UnitManager) {
+	files := scanFiles(f.paths)
+	um.Ready()
+	f.watch(um.Context(), files)
+	um.Done()
+}
+
+// watch scans the files, starting from their given state, until ctx is done.
+func (f *FileWatcher) watch(ctx context.Context, files map[string]fileState) {
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	debounce := time.NewTimer(f.debounce)
+	debounce.Stop()
+	defer debounce.Stop()
+
+	pending := make(map[string]bool)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			scanned := scanFiles(f.paths)
+			changed := diffFiles(files, scanned)
+			files = scanned
+			if len(changed) == 0 {
+				continue
+			}
+			for _, path := range changed {
+				pending[path] = true
+			}
+			debounce.Reset(f.debounce)
+
+		case <-debounce.C:
+			changed := make([]string, 0, len(pending))
+			for path := range pending {
+				changed = append(changed, path)
+			}
+			sort.Strings(changed)
+			pending = make(map[string]bool)
+
+			if f.onChange != nil {
+				f.onChange(changed)
+			}
+			f.deliver(changed)
+		}
+	}
+}
+
+// deliver sends the changes, merged with the undelivered ones.
+func (f *FileWatcher) deliver(changed []string) {
+	select {
+	case undelivered := <-f.changes:
+		changed = mergePaths(undelivered, changed)
+	default:
+	}
+	f.changes <- changed
+}
+
+// fileState is what a FileWatcher compares to detect changes.
+type fileState struct {
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+// scanFiles returns the state of the paths and of the entries of the
+// directories among them. Missing paths are skipped.
+func scanFiles(paths []string) map[string]fileState {
+	files := make(map[string]fileState)
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		files[path] = fileState{info.Size(), info.Mode(), info.ModTime()}
+
+		if !info.IsDir() {
+			continue
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			files[filepath.Join(path, entry.Name())] = fileState{info.Size(), info.Mode(), info.ModTime()}
+		}
+	}
+	return files
+}
+
+// diffFiles returns the paths created, removed or changed.
+func diffFiles(from, to map[string]fileState) []string {
+	var changed []string
+	for path, state := range to {
+		if prev, ok := from[path]; !ok || prev.size != state.size ||
+			prev.mode != state.mode || !prev.modTime.Equal(state.modTime) {
+			changed = append(changed, path)
+		}
+	}
+	for path := range from {
+		if _, ok := to[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	return changed
+}
+
+// mergePaths returns the sorted union of two sorted path lists.
+func mergePaths(a, b []string) []string {
+	merged := make([]string, 0, len(a)+len(b))
+	for len(a) > 0 && len(b) > 0 {
+		switch {
+		case a[0] < b[0]:
+			merged, a = append(merged, a[0]), a[1:]
+		case a[0] > b[0]:
+			merged, b = append(merged, b[0]), b[1:]
+		default:
+			merged, a, b = append(merged, a[0]), a[1:], b[1:]
+		}
+	}
+	return append(append(merged, a...), b...)
+}
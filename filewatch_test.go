@@ -0,0 +1,81 @@
+package gum
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFileWatcher(t *testing.T) {
+	dir := t.TempDir()
+	config := filepath.Join(dir, "config")
+	cert := filepath.Join(dir, "cert")
+	if err := os.WriteFile(config, []byte("a"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var calls [][]string
+	unit := WatchFiles(dir).
+		Interval(2 * time.Millisecond).
+		Debounce(20 * time.Millisecond).
+		OnChange(func(changed []string) {
+			mu.Lock()
+			calls = append(calls, changed)
+			mu.Unlock()
+		})
+
+	manager := NewManager()
+	manager.AddUnit(unit, "")
+	sub := manager.Subscribe()
+	go manager.Run()
+	defer func() {
+		manager.Stop()
+		<-manager.Quit
+	}()
+	waitEvent(t, sub, EventUnitReady)
+
+	// Several writes in a row are delivered once
+	if err := os.WriteFile(config, []byte("ab"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if err := os.WriteFile(cert, []byte("c"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var changed []string
+	select {
+	case changed = <-unit.Changes():
+	case <-time.After(time.Second):
+		t.Fatal("changes not delivered")
+	}
+
+	seen := make(map[string]bool)
+	for _, path := range changed {
+		seen[path] = true
+	}
+	if !seen[config] || !seen[cert] {
+		t.Fatalf("expected both files to be changed, got %v", changed)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(calls) != 1 {
+		t.Fatalf("expected the changes to be debounced, got %v", calls)
+	}
+}
+
+func TestMergePaths(t *testing.T) {
+	merged := mergePaths([]string{"a", "c"}, []string{"b", "c", "d"})
+	want := []string{"a", "b", "c", "d"}
+	if len(merged) != len(want) {
+		t.Fatalf("expected %v, got %v", want, merged)
+	}
+	for i := range want {
+		if merged[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, merged)
+		}
+	}
+}
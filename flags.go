@@ -0,0 +1,79 @@
+package gum
+
+// FlagProvider decides whether a unit should run, typically backed by a
+// feature flag service. It is consulted when the unit is about to start and
+// on every ReloadFlags.
+type FlagProvider interface {
+	Enabled(unit UnitInfo) bool
+}
+
+// FlagFunc adapts a function to the FlagProvider interface.
+type FlagFunc func(unit UnitInfo) bool
+
+// Enabled calls f(unit).
+func (f FlagFunc) Enabled(unit UnitInfo) bool {
+	return f(unit)
+}
+
+// WithFlagProvider sets the provider deciding which units are started.
+// Disabled units are registered but never started, their state is Disabled.
+func WithFlagProvider(p FlagProvider) Option {
+	return func(m *Manager) {
+		m.flags = p
+	}
+}
+
+// unitEnabled consults the flag provider before starting the unit and marks
+// disabled units as such.
+func (m *Manager) unitEnabled(w *WorkUnitManager) bool {
+	if m.flags == nil {
+		return true
+	}
+
+	m.startMu.Lock()
+	defer m.startMu.Unlock()
+
+	if m.flags.Enabled(w.Info()) {
+		return true
+	}
+
+	m.unitLogf(w.name, "Skipping disabled <%s>\n", w)
+	m.setState(w, Disabled, nil)
+	m.unitSettled(w)
+	return false
+}
+
+// ReloadFlags consults the flag provider again: disabled units which are now
+// enabled are started and running units which are now disabled are asked to
+// stop. A unit stopped this way is not started again.
+func (m *Manager) ReloadFlags() {
+	if m.flags == nil {
+		return
+	}
+
+	m.startMu.Lock()
+	defer m.startMu.Unlock()
+
+	select {
+	case <-m.startStop:
+		return // Shutting down
+	default:
+	}
+
+	for _, w := range m.order {
+		m.regMu.RLock()
+		state := w.state
+		m.regMu.RUnlock()
+
+		enabled := m.flags.Enabled(w.Info())
+
+		switch {
+		case state == Disabled && enabled:
+			m.startUnit(w)
+
+		case (state == Running || state == Parked) && !enabled:
+			m.unitLogf(w.name, "Stopping disabled <%s>\n", w)
+			m.stopUnit(w)
+		}
+	}
+}
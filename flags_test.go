@@ -0,0 +1,53 @@
+package gum
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFlagProvider(t *testing.T) {
+	var enabled atomic.Bool
+	flags := FlagFunc(func(unit UnitInfo) bool {
+		return unit.Labels["flag"] != "beta" || enabled.Load()
+	})
+
+	manager := NewManager(WithFlagProvider(flags))
+	manager.AddUnit(NewWorker(), "")
+	manager.AddUnit(NewWorker(), "", WithLabels(map[string]string{"flag": "beta"}))
+
+	go manager.Run()
+	waitState(t, manager, 0, Running)
+	waitState(t, manager, 1, Disabled)
+
+	// Enable the unit
+	enabled.Store(true)
+	manager.ReloadFlags()
+	waitState(t, manager, 1, Running)
+
+	// Disable it again
+	enabled.Store(false)
+	manager.ReloadFlags()
+	waitState(t, manager, 1, Stopped)
+
+	manager.Stop()
+	select {
+	case <-manager.Quit:
+	case <-time.After(time.Second):
+		t.Fatal("manager did not quit")
+	}
+}
+
+func waitState(t *testing.T, manager *Manager, i int, state UnitState) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		units := manager.Snapshot().Units
+		if i < len(units) && units[i].State == state {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("expected unit %d to be %s, got %+v", i, state, manager.Snapshot().Units)
+}
@@ -0,0 +1,142 @@
+package gum
+
+import (
+	"context"
+	"fmt"
+)
+
+// FreezePolicy defines what happens to the topology changes attempted while
+// the topology is frozen, see Freeze.
+type FreezePolicy int
+
+const (
+	// FreezeReject rejects the changes (default).
+	FreezeReject FreezePolicy = iota
+
+	// FreezeQueue queues the changes, applied in order by Unfreeze.
+	// SwapUnit waits for Unfreeze instead.
+	FreezeQueue
+)
+
+var freezePolicyNames = [...]string{
+	FreezeReject: "reject",
+	FreezeQueue:  "queue",
+}
+
+func (p FreezePolicy) String() string {
+	if p >= 0 && int(p) < len(freezePolicyNames) {
+		return freezePolicyNames[p]
+	}
+	return fmt.Sprintf("FreezePolicy(%d)", int(p))
+}
+
+// WithFreezePolicy sets the policy applied to the topology changes attempted
+// while the topology is frozen.
+func WithFreezePolicy(p FreezePolicy) Option {
+	return func(m *Manager) {
+		if p < FreezeReject || p > FreezeQueue {
+			m.invalid(fmt.Errorf("invalid freeze policy %d", int(p)))
+			return
+		}
+		m.freezePolicy = p
+	}
+}
+
+// Freeze blocks the topology changes (AddUnit, AddSpec, RemoveUnit and
+// SwapUnit) until Unfreeze, e.g. during an incident or a sensitive window.
+// Changes attempted meanwhile are rejected with ErrFrozen or queued, see
+// WithFreezePolicy, and published as EventChangeBlocked events for the audit
+// trail, their error wrapping ErrFrozen. Restarts and recycling are not
+// topology changes and go on.
+func (m *Manager) Freeze(reason string) {
+	m.freezeMu.Lock()
+	defer m.freezeMu.Unlock()
+	if m.frozen {
+		return
+	}
+	m.frozen = true
+	m.freezeReason = reason
+	m.unfrozen = make(chan struct{})
+
+	m.logf("Topology frozen: %s\n", reason)
+	m.emitEvent(Event{Kind: EventFrozen, Reason: reason})
+}
+
+// Unfreeze allows the topology changes again and applies the queued ones,
+// in the order they were attempted.
+func (m *Manager) Unfreeze() {
+	m.freezeMu.Lock()
+	if !m.frozen {
+		m.freezeMu.Unlock()
+		return
+	}
+	m.frozen = false
+	queued := m.freezeQueue
+	m.freezeQueue = nil
+	close(m.unfrozen)
+	m.freezeMu.Unlock()
+
+	m.logf("Topology unfrozen, applying %d queued changes\n", len(queued))
+	m.emit(EventUnfrozen, "", nil)
+	for _, apply := range queued {
+		m.protect("queued change", apply)
+	}
+}
+
+// Frozen reports whether the topology is frozen, and the reason given to
+// Freeze.
+func (m *Manager) Frozen() (bool, string) {
+	m.freezeMu.Lock()
+	defer m.freezeMu.Unlock()
+	return m.frozen, m.freezeReason
+}
+
+// blockChange blocks a topology change of the unit while the topology is
+// frozen: it is queued if apply is not nil and the policy is FreezeQueue,
+// rejected otherwise. It reports whether the change was blocked, and the
+// error of a rejected change.
+func (m *Manager) blockChange(change, unit string, apply func()) (bool, error) {
+	m.freezeMu.Lock()
+	defer m.freezeMu.Unlock()
+	if !m.frozen {
+		return false, nil
+	}
+
+	if m.freezePolicy == FreezeQueue && apply != nil {
+		m.freezeQueue = append(m.freezeQueue, apply)
+		err := fmt.Errorf("%s <%s> queued: %w (%s)", change, unit, ErrFrozen, m.freezeReason)
+		m.logf("%s\n", err)
+		m.emitEvent(Event{Kind: EventChangeBlocked, Unit: unit, Err: err, Reason: m.freezeReason})
+		return true, nil
+	}
+
+	err := fmt.Errorf("can't %s <%s>: %w (%s)", change, unit, ErrFrozen, m.freezeReason)
+	m.warnf(unit, "%s\n", err)
+	m.emitEvent(Event{Kind: EventChangeBlocked, Unit: unit, Err: err, Reason: m.freezeReason})
+	return true, err
+}
+
+// waitUnfrozen blocks until the topology is unfrozen, or rejects the change
+// with the FreezeReject policy.
+func (m *Manager) waitUnfrozen(ctx context.Context, change, unit string) error {
+	m.freezeMu.Lock()
+	frozen, unfrozen, policy, reason := m.frozen, m.unfrozen, m.freezePolicy, m.freezeReason
+	m.freezeMu.Unlock()
+	if !frozen {
+		return nil
+	}
+	if policy != FreezeQueue {
+		_, err := m.blockChange(change, unit, nil)
+		return err
+	}
+
+	err := fmt.Errorf("%s <%s> waiting: %w (%s)", change, unit, ErrFrozen, reason)
+	m.logf("%s\n", err)
+	m.emitEvent(Event{Kind: EventChangeBlocked, Unit: unit, Err: err, Reason: reason})
+	select {
+	case <-unfrozen:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("can't %s <%s>: %w", change, unit, ctx.Err())
+	}
+}
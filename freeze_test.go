@@ -0,0 +1,91 @@
+package gum
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFreezeReject(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&readyWorker{}, "", WithName("worker"))
+
+	sub := manager.Subscribe()
+	quit := runAsync(manager)
+	waitEvent(t, sub, EventStartupComplete)
+	defer func() {
+		manager.Stop()
+		<-quit
+	}()
+
+	manager.Freeze("incident 42")
+	if frozen, reason := manager.Frozen(); !frozen || reason != "incident 42" {
+		t.Fatalf("expected the topology to be frozen, got %v %q", frozen, reason)
+	}
+
+	manager.AddUnit(&readyWorker{}, "", WithName("extra"))
+	ev := waitEvent(t, sub, EventChangeBlocked)
+	if ev.Unit != "extra" || !errors.Is(ev.Err, ErrFrozen) || ev.Reason != "incident 42" {
+		t.Fatalf("unexpected blocked change %+v", ev)
+	}
+	if err := manager.RemoveUnit("worker"); !errors.Is(err, ErrFrozen) {
+		t.Fatalf("expected the removal to be rejected, got %v", err)
+	}
+	if err := manager.SwapUnit(context.Background(), "worker", &readyWorker{}); !errors.Is(err, ErrFrozen) {
+		t.Fatalf("expected the swap to be rejected, got %v", err)
+	}
+
+	manager.Unfreeze()
+	if _, ok := manager.Status("extra"); ok {
+		t.Fatal("expected the rejected unit not to be added")
+	}
+	if err := manager.RemoveUnit("worker"); err != nil {
+		t.Fatalf("expected the removal once unfrozen, got %v", err)
+	}
+}
+
+func TestFreezeQueue(t *testing.T) {
+	manager := NewManager(WithFreezePolicy(FreezeQueue))
+	manager.AddUnit(&readyWorker{}, "", WithName("worker"))
+
+	sub := manager.Subscribe()
+	quit := runAsync(manager)
+	waitEvent(t, sub, EventStartupComplete)
+	defer func() {
+		manager.Stop()
+		<-quit
+	}()
+
+	manager.Freeze("deploy window")
+	manager.AddUnit(&readyWorker{}, "", WithName("extra"))
+	if err := manager.RemoveUnit("worker"); err != nil {
+		t.Fatalf("expected the removal to be queued, got %v", err)
+	}
+
+	swapped := make(chan error, 1)
+	go func() {
+		swapped <- manager.SwapUnit(context.Background(), "extra", &readyWorker{})
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	if _, ok := manager.Status("extra"); ok {
+		t.Fatal("expected the unit not to be added while frozen")
+	}
+	select {
+	case err := <-swapped:
+		t.Fatalf("expected the swap to wait, got %v", err)
+	default:
+	}
+
+	manager.Unfreeze()
+	if _, ok := manager.Status("extra"); !ok {
+		t.Fatal("expected the queued unit to be added")
+	}
+	if err := <-swapped; err != nil {
+		t.Fatalf("expected the swap once unfrozen, got %v", err)
+	}
+	if u, ok := manager.Status("worker"); ok && u.State == Running {
+		t.Fatalf("expected the queued removal, got %s", u.State)
+	}
+}
@@ -0,0 +1,50 @@
+package gum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// FuncUnit adapts a function taking a context to a WorkUnit, for small
+// workers not worth a type of their own. The context is cancelled when the
+// unit is asked to stop, see UnitManager.Context. The unit is ready once
+// started. When the function returns, the unit is done, or panics with the
+// returned error unless it is the cancellation of the stop.
+type FuncUnit func(ctx context.Context) error
+
+// Run runs the function.
+func (f FuncUnit) Run(um UnitManager) {
+	um.Ready()
+
+	err := f(um.Context())
+	if err != nil && !(um.Stopping() && errors.Is(err, context.Canceled)) {
+		um.Panic(err)
+		return
+	}
+	um.Done()
+}
+
+// AddFunc registers a function as a unit named name, see FuncUnit:
+//
+//	manager.AddFunc("cleanup", func(ctx context.Context) error {
+//		ticker := time.NewTicker(time.Minute)
+//		defer ticker.Stop()
+//		for {
+//			select {
+//			case <-ticker.C:
+//				purge()
+//			case <-ctx.Done():
+//				return nil
+//			}
+//		}
+//	})
+//
+// The name is used as is, as with WithName.
+func (m *Manager) AddFunc(name string, fn func(ctx context.Context) error, opts ...UnitOption) {
+	if fn == nil {
+		m.invalid(fmt.Errorf("nil func %q", name))
+		return
+	}
+	m.AddUnit(FuncUnit(fn), "", append([]UnitOption{WithName(name)}, opts...)...)
+}
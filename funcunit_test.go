@@ -0,0 +1,56 @@
+package gum
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestAddFunc(t *testing.T) {
+	manager := NewManager()
+	manager.AddFunc("ticker", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	manager.AddFunc("once", func(ctx context.Context) error {
+		return nil
+	})
+
+	sub := manager.Subscribe()
+	quit := runAsync(manager)
+	waitEvent(t, sub, EventStartupComplete)
+	manager.Stop()
+	<-quit
+
+	if manager.Err() != nil {
+		t.Fatalf("unexpected shutdown cause: %v", manager.Err())
+	}
+	for _, name := range []string{"ticker", "once"} {
+		if u, ok := manager.Status(name); !ok || u.State != Stopped {
+			t.Errorf("expected <%s> to be stopped, got %+v", name, u)
+		}
+	}
+}
+
+func TestAddFuncFailure(t *testing.T) {
+	errBroken := errors.New("broken pipe")
+	manager := NewManager()
+	manager.AddFunc("pipe", func(ctx context.Context) error {
+		return errBroken
+	})
+	manager.Run()
+
+	if !errors.Is(manager.Err(), errBroken) || !errors.Is(manager.Err(), ErrUnitPanic) {
+		t.Fatalf("expected the returned error to fail the unit, got %v", manager.Err())
+	}
+}
+
+func TestAddFuncValidate(t *testing.T) {
+	manager := NewManager()
+	manager.AddFunc("nil", nil)
+	manager.AddFunc("", func(ctx context.Context) error { return nil })
+
+	if err := manager.Validate(); err == nil {
+		t.Fatal("expected a nil func and an empty name to be invalid")
+	}
+}
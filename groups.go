@@ -0,0 +1,127 @@
+package gum
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ParseSelector parses a label selector made of comma separated
+// requirements, all of which must be met: "k=v" or "k==v" for the units
+// labeled k with the value v, "k!=v" for the others, "k" for the units
+// having the label k and "!k" for the units not having it. An empty selector
+// selects all units.
+//
+//	sel, err := gum.ParseSelector("tier=db,region!=eu")
+func ParseSelector(s string) (Selector, error) {
+	type requirement struct {
+		key, value string
+		negate     bool
+		exists     bool
+	}
+
+	var reqs []requirement
+	for _, term := range strings.Split(s, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			if strings.TrimSpace(s) == "" {
+				break
+			}
+			return nil, fmt.Errorf("invalid selector %q: empty requirement", s)
+		}
+
+		var r requirement
+		switch {
+		case strings.Contains(term, "!="):
+			r.key, r.value, _ = strings.Cut(term, "!=")
+			r.negate = true
+		case strings.Contains(term, "="):
+			r.key, r.value, _ = strings.Cut(term, "=")
+			r.value = strings.TrimPrefix(r.value, "=")
+		case strings.HasPrefix(term, "!"):
+			r.key, r.negate, r.exists = term[1:], true, true
+		default:
+			r.key, r.exists = term, true
+		}
+		r.key, r.value = strings.TrimSpace(r.key), strings.TrimSpace(r.value)
+		if r.key == "" {
+			return nil, fmt.Errorf("invalid selector %q: empty label name in %q", s, term)
+		}
+		reqs = append(reqs, r)
+	}
+
+	return func(u UnitStatus) bool {
+		for _, r := range reqs {
+			v, ok := u.Labels[r.key]
+			match := ok && (r.exists || v == r.value)
+			if match == r.negate {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// StatusGroup returns the status of the units selected by sel, e.g. with
+// MatchLabels or ParseSelector, in registration order. A nil Selector
+// selects all units.
+func (m *Manager) StatusGroup(sel Selector) []UnitStatus {
+	var units []UnitStatus
+	for _, u := range m.Units() {
+		if sel == nil || sel(u) {
+			units = append(units, u)
+		}
+	}
+	return units
+}
+
+// StopGroup stops the running units selected by sel, parked ones included,
+// see StopUnit.
+func (m *Manager) StopGroup(sel Selector) error {
+	var errs []error
+	for _, u := range m.StatusGroup(sel) {
+		if u.State == Running || u.State == Parked {
+			errs = append(errs, m.StopUnit(u.Name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RestartGroup restarts the running units selected by sel, parked ones
+// included, see RestartUnit.
+func (m *Manager) RestartGroup(sel Selector) error {
+	var errs []error
+	for _, u := range m.StatusGroup(sel) {
+		if u.State == Running || u.State == Parked {
+			errs = append(errs, m.RestartUnit(u.Name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// StopUnit asks the named unit to stop. The unit stays registered and is not
+// restarted, whatever its restart policy, until restarted with RestartUnit.
+// Stopping a unit which is already stopping or done is a no-op.
+func (m *Manager) StopUnit(name string) error {
+	m.startMu.Lock()
+	defer m.startMu.Unlock()
+
+	m.regMu.RLock()
+	w, ok := m.workers[name]
+	m.regMu.RUnlock()
+
+	switch {
+	case !ok:
+		return fmt.Errorf("can't stop <%s>: unknown unit", name)
+	case !w.started.Load():
+		return fmt.Errorf("can't stop <%s>: unit not started", name)
+	case w.removed.Load():
+		return fmt.Errorf("can't stop <%s>: unit removed", name)
+	case w.done.Load() || w.Stopping():
+		return nil
+	}
+
+	m.unitLogf(w.name, "Stopping <%s>\n", w)
+	m.stopUnit(w)
+	return nil
+}
@@ -0,0 +1,98 @@
+package gum
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGroups(t *testing.T) {
+	manager := NewManager()
+	db := map[string]string{"tier": "db"}
+	manager.AddUnit(&readyWorker{}, "", WithName("primary"), WithLabels(db), WithRestart(RestartAlways))
+	manager.AddUnit(&readyWorker{}, "", WithName("replica"), WithLabels(db), WithRestart(RestartAlways))
+	web := map[string]string{"tier": "web"}
+	manager.AddUnit(&readyWorker{}, "", WithName("api"), WithLabels(web))
+	manager.AddUnit(funcWorker(func(um UnitManager) {
+		um.Ready()
+		select {
+		case <-um.Park(time.Time{}):
+		case <-um.ShouldStop():
+		}
+		um.Done()
+	}), "", WithName("batch"), WithLabels(web))
+
+	sub := manager.Subscribe()
+	quit := runAsync(manager)
+	waitEvent(t, sub, EventStartupComplete)
+	waitUnitState(t, manager, "batch", Parked)
+
+	sel, err := ParseSelector("tier=db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if units := manager.StatusGroup(sel); len(units) != 2 || units[0].Name != "primary" || units[1].Name != "replica" {
+		t.Fatalf("unexpected group %+v", units)
+	}
+
+	// Stopped units are not restarted
+	if err := manager.StopGroup(sel); err != nil {
+		t.Fatalf("unexpected stop error: %v", err)
+	}
+	waitUnitState(t, manager, "primary", Stopped)
+	waitUnitState(t, manager, "replica", Stopped)
+	if u, _ := manager.Status("api"); u.State != Running {
+		t.Fatalf("expected <api> to keep running, got %s", u.State)
+	}
+
+	// Parked units are restarted along with the running ones
+	if err := manager.RestartGroup(MatchLabels(web)); err != nil {
+		t.Fatalf("unexpected restart error: %v", err)
+	}
+	restarted := map[string]bool{}
+	for len(restarted) < 2 {
+		restarted[waitEvent(t, sub, EventUnitRestart).Unit] = true
+	}
+	if !restarted["api"] || !restarted["batch"] {
+		t.Fatalf("expected <api> and <batch> to be restarted, got %v", restarted)
+	}
+	if err := manager.StopUnit("unknown"); err == nil {
+		t.Fatal("expected an unknown unit not to be stopped")
+	}
+
+	manager.Stop()
+	<-quit
+}
+
+func TestParseSelector(t *testing.T) {
+	labels := map[string]string{"tier": "db", "region": "eu"}
+	tests := []struct {
+		selector string
+		want     bool
+	}{
+		{"", true},
+		{"tier=db", true},
+		{"tier==db", true},
+		{"tier=web", false},
+		{"tier=db, region!=us", true},
+		{"tier=db,region!=eu", false},
+		{"region", true},
+		{"!region", false},
+		{"!canary", true},
+		{"zone!=a", true},
+	}
+	for _, tt := range tests {
+		sel, err := ParseSelector(tt.selector)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tt.selector, err)
+		}
+		if got := sel(UnitStatus{Labels: labels}); got != tt.want {
+			t.Errorf("%q: expected %t, got %t", tt.selector, tt.want, got)
+		}
+	}
+
+	for _, s := range []string{"tier=db,", "=db", "!"} {
+		if _, err := ParseSelector(s); err == nil {
+			t.Errorf("%q: expected an error", s)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package gum
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// WithHistoryFile persists the panic history of the units to the given file
+// so a crash-looping unit doesn't get a fresh panic budget every time the
+// process is restarted by its supervisor. Units are matched by name across
+// restarts, which is stable as long as they are added in the same order.
+func WithHistoryFile(path string) Option {
+	return func(m *Manager) {
+		if path == "" {
+			m.invalid(fmt.Errorf("empty history file path"))
+			return
+		}
+		m.historyPath = path
+	}
+}
+
+// unitHistory is the persisted history of a unit.
+type unitHistory struct {
+	Panics      []time.Time `json:"panics,omitempty"` // Within the panic budget window
+	PanicsTotal int         `json:"panics_total"`
+}
+
+type history struct {
+	Units map[string]unitHistory `json:"units"`
+}
+
+// loadHistory restores the history of the registered units. A missing file
+// is not an error, the history starts empty.
+func (m *Manager) loadHistory() error {
+	data, err := os.ReadFile(m.historyPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var h history
+	if err := json.Unmarshal(data, &h); err != nil {
+		return fmt.Errorf("%s: %w", m.historyPath, err)
+	}
+
+	now := m.clock.Now()
+
+	m.regMu.Lock()
+	defer m.regMu.Unlock()
+
+	for _, w := range m.order {
+		uh, ok := h.Units[w.name]
+		if !ok {
+			continue
+		}
+
+		w.panics.total = uh.PanicsTotal
+		w.panics.times = w.panics.times[:0]
+		for _, t := range uh.Panics {
+			if w.panicBudgetWindow > 0 && now.Sub(t) <= w.panicBudgetWindow {
+				w.panics.times = append(w.panics.times, t)
+			}
+		}
+	}
+	m.touch()
+
+	return nil
+}
+
+// saveHistory writes the history of the registered units. The file is
+// replaced atomically so a crash while saving can't corrupt it.
+func (m *Manager) saveHistory() error {
+	h := history{Units: make(map[string]unitHistory)}
+
+	m.regMu.RLock()
+	for _, w := range m.order {
+		if w.panics.total == 0 {
+			continue
+		}
+		h.Units[w.name] = unitHistory{
+			Panics:      append([]time.Time(nil), w.panics.times...),
+			PanicsTotal: w.panics.total,
+		}
+	}
+	m.regMu.RUnlock()
+
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(m.historyPath, data)
+}
+
+// writeFileAtomic replaces the file with data atomically, so a crash while
+// writing can't corrupt it.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
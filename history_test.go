@@ -0,0 +1,73 @@
+package gum
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHistorySaved(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	manager := NewManager(WithHistoryFile(path))
+	manager.AddUnit(&panicWorker{}, "", WithPanicBudget(1, time.Hour))
+	name := manager.order[0].name
+
+	manager.Run()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var h history
+	if err := json.Unmarshal(data, &h); err != nil {
+		t.Fatal(err)
+	}
+	if uh := h.Units[name]; uh.PanicsTotal != 1 || len(uh.Panics) != 1 {
+		t.Fatalf("unexpected history for <%s>: %+v", name, uh)
+	}
+}
+
+func TestHistoryRestored(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	manager := NewManager(WithHistoryFile(path))
+	manager.AddUnit(&stopWorker{}, "", WithPanicBudget(2, time.Hour))
+	w := manager.order[0]
+
+	manager.recordPanic(w)
+	manager.recordPanic(w)
+	if err := manager.saveHistory(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a process restart
+	*w.panics = panicHistory{}
+	if err := manager.loadHistory(); err != nil {
+		t.Fatal(err)
+	}
+
+	sub := manager.Subscribe()
+	manager.recordPanic(w)
+	sub.Close()
+
+	exceeded := false
+	for ev := range sub.Events() {
+		exceeded = exceeded || ev.Kind == EventBudgetExceeded
+	}
+	if !exceeded {
+		t.Fatal("expected restored panics to count against the budget")
+	}
+	if panics := manager.Snapshot().Units[0].Panics; panics != 3 {
+		t.Fatalf("expected 3 panics in status, got %d", panics)
+	}
+}
+
+func TestHistoryMissing(t *testing.T) {
+	manager := NewManager(WithHistoryFile(filepath.Join(t.TempDir(), "none.json")))
+	manager.AddUnit(&stopWorker{}, "")
+
+	if err := manager.loadHistory(); err != nil {
+		t.Fatalf("expected a missing history to be empty, got %s", err)
+	}
+}
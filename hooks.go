@@ -0,0 +1,89 @@
+package gum
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultHookTimeout is the default time a hook is given to return, see
+// WithHookTimeout.
+const DefaultHookTimeout = 10 * time.Second
+
+// WithHookTimeout bounds the time the user-supplied hooks called by the
+// manager (restart hooks, accounting, pressure hooks, notifiers and OnEvent
+// hooks) are given to return, so a misbehaving hook can't wedge the
+// manager. A hook exceeding it is left running on its own, the manager goes
+// on and publishes an EventHookTimeout event. Zero disables the timeout,
+// hooks are still protected against panics, see EventInternalError.
+func WithHookTimeout(d time.Duration) Option {
+	return func(m *Manager) {
+		if d < 0 {
+			m.invalid(fmt.Errorf("negative hook timeout: %s", d))
+			return
+		}
+		m.hookTimeout = d
+	}
+}
+
+// OnEvent registers a hook called with the events of the given kinds, every
+// event if none, e.g. to push unit failures to an alerting system or to
+// write an audit log:
+//
+//	manager.OnEvent(func(ev gum.Event) {
+//		alert(ev.Unit, ev.Err, ev.Stack)
+//	}, gum.EventUnitPanic, gum.EventUnitRestart)
+//
+// Hooks can be registered before or while the manager runs. Like notifiers,
+// see WithNotifier, events are delivered in order on a goroutine of the hook,
+// a panic of the hook is only logged, and the manager waits for the pending
+// events to be delivered before quitting. The returned function unregisters
+// the hook.
+func (m *Manager) OnEvent(hook func(ev Event), kinds ...EventKind) (remove func()) {
+	sub := m.Subscribe(WithBufferSize(256))
+	m.startNotifier(NotifierFunc(hook), sub, kinds)
+	return sub.Close
+}
+
+// callHook calls a user-supplied hook within the hook timeout, protected
+// against panics. It reports false if the hook panicked or timed out.
+func (m *Manager) callHook(what, unit string, hook func()) bool {
+	ok := false
+	if !m.withinHookTimeout(func() { ok = m.protect(what, hook) }) {
+		m.hookTimedOut(what, unit)
+		return false
+	}
+	return ok
+}
+
+// withinHookTimeout runs f, on a goroutine of its own when the hook timeout
+// is set. It reports false if f did not return in time, f is then left
+// running.
+func (m *Manager) withinHookTimeout(f func()) bool {
+	if m.hookTimeout <= 0 {
+		f()
+		return true
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f()
+	}()
+
+	timer := time.NewTimer(m.hookTimeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// hookTimedOut logs and publishes the timeout of a hook, about the unit if
+// not empty.
+func (m *Manager) hookTimedOut(what, unit string) {
+	err := fmt.Errorf("%w: %s did not return within %s", ErrHookTimeout, what, m.hookTimeout)
+	m.warnf(unit, "%s\n", err)
+	m.emit(EventHookTimeout, unit, err)
+}
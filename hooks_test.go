@@ -0,0 +1,105 @@
+package gum
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOnEvent(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&crashWorker{}, "", WithName("crash"))
+
+	var panics, all []Event
+	manager.OnEvent(func(ev Event) {
+		panics = append(panics, ev)
+	}, EventUnitPanic)
+	manager.OnEvent(func(ev Event) {
+		all = append(all, ev)
+	})
+	removed := manager.OnEvent(func(ev Event) {
+		t.Errorf("unexpected event after removal: %s", ev)
+	})
+	removed()
+
+	manager.Run()
+
+	if len(panics) != 1 || panics[0].Unit != "crash" || panics[0].Err == nil {
+		t.Fatalf("expected the unit panic, got %v", panics)
+	}
+	if !strings.Contains(string(panics[0].Stack), "crashWorker") {
+		t.Fatalf("expected the stack of the panic, got:\n%s", panics[0].Stack)
+	}
+	if len(all) == 0 || all[len(all)-1].Kind != EventManagerQuit {
+		t.Fatalf("expected every event to be delivered before quitting, got %v", all)
+	}
+}
+
+func TestOnEventRunning(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&readyWorker{}, "", WithName("worker"))
+
+	sub := manager.Subscribe()
+	quit := runAsync(manager)
+	waitEvent(t, sub, EventStartupComplete)
+
+	var stopped []string
+	manager.OnEvent(func(ev Event) {
+		stopped = append(stopped, ev.Unit)
+	}, EventUnitDone)
+	manager.Stop()
+	<-quit
+
+	if len(stopped) != 1 || stopped[0] != "worker" {
+		t.Fatalf("expected the unit stop, got %v", stopped)
+	}
+}
+
+func TestHookTimeout(t *testing.T) {
+	wedged := make(chan struct{})
+	defer close(wedged)
+
+	manager := NewManager(
+		WithHookTimeout(10*time.Millisecond),
+		WithRestartHook(func(UnitInfo) RestartDecision {
+			<-wedged
+			return RestartDecision{Veto: true}
+		}),
+		WithNotifier(NotifierFunc(func(ev Event) {
+			if ev.Kind == EventManagerQuit {
+				<-wedged
+			}
+		}), SeverityDebug),
+	)
+	manager.AddUnit(&readyWorker{}, "", WithName("worker"))
+
+	start := time.Now()
+	d := manager.restartDecision(manager.order[0])
+	if d.Veto {
+		t.Fatal("expected the decision of the timed out hook to be ignored")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected the hook to be given up on, took %s", elapsed)
+	}
+
+	sub := manager.Subscribe()
+	go manager.Run()
+	manager.Stop()
+	select {
+	case <-manager.Quit:
+	case <-time.After(time.Second):
+		t.Fatal("manager wedged by the notifier")
+	}
+
+	ev := waitEvent(t, sub, EventHookTimeout)
+	if !errors.Is(ev.Err, ErrHookTimeout) || ev.Severity != SeverityError {
+		t.Fatalf("unexpected hook timeout event %+v", ev)
+	}
+}
+
+func TestHookTimeoutValidate(t *testing.T) {
+	if err := NewManager(WithHookTimeout(-time.Second)).Validate(); err == nil {
+		t.Fatal("expected a negative hook timeout to be invalid")
+	}
+}
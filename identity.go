@@ -0,0 +1,100 @@
+package gum
+
+import "fmt"
+
+// UnitInfo is the identity of a unit as known by the manager. It is set when
+// the unit is added and never changes, units can use it to tag their logs and
+// metrics the same way the manager refers to them.
+type UnitInfo struct {
+	// Name is the full unit name, as found in logs, events and snapshots.
+	Name string
+
+	// ID distinguishes units sharing the same base name and type.
+	ID int
+
+	// Type is the unit type, given by the Namer interface or the Go type.
+	Type string
+
+	// Labels are the labels set with WithLabels.
+	Labels map[string]string
+}
+
+// WithName overrides the unit name derived from the name given to AddUnit and
+// the unit type. The name is used as is and must be unique.
+func WithName(name string) UnitOption {
+	return func(w *WorkUnitManager) {
+		if name == "" {
+			w.invalid(fmt.Errorf("empty unit name"))
+			return
+		}
+		w.name = name
+	}
+}
+
+// WithStableNames numbers the units sharing a name and type per manager, in
+// registration order, rather than with a counter shared by the process. A
+// restarted unit keeps the name of the instance it replaces, as swapped and
+// recycled units always do. Unit names, and the metrics series labeled with
+// them, are then stable across restarts and deploys. Explicit names, see
+// WithName, are unaffected.
+func WithStableNames() Option {
+	return func(m *Manager) {
+		m.stableNames = true
+	}
+}
+
+// unitID returns the ID of a new unit whose name starts with prefix.
+func (m *Manager) unitID(prefix string) int {
+	if !m.stableNames {
+		return idGenerator(prefix)
+	}
+
+	m.idsMu.Lock()
+	defer m.idsMu.Unlock()
+
+	if m.ids == nil {
+		m.ids = make(map[string]int)
+	}
+	id := m.ids[prefix]
+	m.ids[prefix]++
+	return id
+}
+
+// replacedID returns the ID of a new instance replacing the unit: the ID of
+// the unit with stable names, -1 for a new ID otherwise.
+func (m *Manager) replacedID(w *WorkUnitManager) int {
+	if !m.stableNames {
+		return -1
+	}
+	return w.info.ID
+}
+
+// WithLabels attaches labels to the unit. Labels are part of the unit
+// identity returned by Info.
+func WithLabels(labels map[string]string) UnitOption {
+	return func(w *WorkUnitManager) {
+		if w.info.Labels == nil {
+			w.info.Labels = make(map[string]string, len(labels))
+		}
+		for k, v := range labels {
+			if k == "" {
+				w.invalid(fmt.Errorf("empty label name"))
+				continue
+			}
+			w.info.Labels[k] = v
+		}
+	}
+}
+
+// Info returns the identity of the unit. The returned labels are a copy and
+// can be modified freely.
+func (w *WorkUnitManager) Info() UnitInfo {
+	info := w.info
+	if w.info.Labels != nil {
+		info.Labels = make(map[string]string, len(w.info.Labels))
+		for k, v := range w.info.Labels {
+			info.Labels[k] = v
+		}
+	}
+	return info
+}
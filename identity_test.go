@@ -0,0 +1,92 @@
+package gum
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUnitInfo(t *testing.T) {
+	manager := NewManager()
+	labels := map[string]string{"team": "infra"}
+	manager.AddUnit(&namedWorker{}, "info", WithLabels(labels))
+	w := manager.order[0]
+
+	info := w.Info()
+	if info.Name != w.String() {
+		t.Fatalf("expected name %q, got %q", w.String(), info.Name)
+	}
+	if info.Type != "poller" {
+		t.Fatalf("expected type poller, got %q", info.Type)
+	}
+	if info.Labels["team"] != "infra" {
+		t.Fatalf("expected team label, got %v", info.Labels)
+	}
+
+	// Identity is immutable
+	labels["team"] = "web"
+	info.Labels["team"] = "web"
+	if w.Info().Labels["team"] != "infra" {
+		t.Fatal("unit labels were modified")
+	}
+}
+
+func TestUnitInfoID(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&namedWorker{}, "id")
+	manager.AddUnit(&namedWorker{}, "id")
+
+	first, second := manager.order[0].Info(), manager.order[1].Info()
+	if second.ID != first.ID+1 {
+		t.Fatalf("expected consecutive ids, got %d and %d", first.ID, second.ID)
+	}
+}
+
+func TestUnitInfoEmptyLabel(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&Worker{}, "", WithLabels(map[string]string{"": "x"}))
+
+	if err := manager.Validate(); err == nil {
+		t.Fatal("expected empty label name to be invalid")
+	}
+}
+
+func TestStableNames(t *testing.T) {
+	names := func() []string {
+		manager := NewManager(WithStableNames())
+		manager.AddUnit(&readyWorker{}, "pool")
+		manager.AddUnit(&readyWorker{}, "pool")
+		manager.AddUnit(&readyWorker{}, "db")
+		var names []string
+		for _, w := range manager.order {
+			names = append(names, w.name)
+		}
+		return names
+	}
+
+	first, second := names(), names()
+	want := []string{"pool[readyWorker#0]", "pool[readyWorker#1]", "db[readyWorker#0]"}
+	for i := range want {
+		if first[i] != want[i] || second[i] != want[i] {
+			t.Fatalf("expected stable names %v, got %v and %v", want, first, second)
+		}
+	}
+}
+
+func TestStableNamesRestart(t *testing.T) {
+	manager := NewManager(WithStableNames())
+	manager.AddUnit(&flakyWorker{failures: 1}, "consumer", WithRestart(RestartOnFailure),
+		WithRestartBackoff(time.Millisecond, time.Millisecond))
+
+	sub := manager.Subscribe()
+	quit := runAsync(manager)
+	waitEvent(t, sub, EventUnitRestart)
+	waitEvent(t, sub, EventUnitReady)
+
+	units := manager.Units()
+	if len(units) != 1 || units[0].Name != "consumer[flakyWorker#0]" || units[0].Restarts != 1 {
+		t.Fatalf("expected the restarted unit to keep its name, got %+v", units)
+	}
+
+	manager.Stop()
+	<-quit
+}
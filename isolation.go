@@ -0,0 +1,57 @@
+package gum
+
+import "fmt"
+
+// FailurePolicy defines whether the failure of a unit, which panicked or
+// called Panic and is not restarted, shuts the manager down.
+type FailurePolicy int
+
+const (
+	// FailureEscalate shuts the manager down, see WithPanicPolicy
+	// (default).
+	FailureEscalate FailurePolicy = iota
+
+	// FailureContain leaves the unit Failed while the other units keep
+	// running, e.g. for a non-critical metrics exporter.
+	FailureContain
+)
+
+var failurePolicyNames = [...]string{
+	FailureEscalate: "escalate",
+	FailureContain:  "contain",
+}
+
+func (p FailurePolicy) String() string {
+	if p >= 0 && int(p) < len(failurePolicyNames) {
+		return failurePolicyNames[p]
+	}
+	return fmt.Sprintf("FailurePolicy(%d)", int(p))
+}
+
+// WithFailurePolicy sets the failure policy of the unit. A contained unit is
+// still restarted according to its restart policy, see WithRestart: its
+// failure is contained once it is not restarted, e.g. past the restart
+// intensity. Contained failures are published as EventUnitPanic events but
+// are not part of the shutdown cause.
+func WithFailurePolicy(p FailurePolicy) UnitOption {
+	return func(w *WorkUnitManager) {
+		if p < FailureEscalate || p > FailureContain {
+			w.invalid(fmt.Errorf("unknown failure policy: %d", p))
+			return
+		}
+		w.failurePolicy = p
+	}
+}
+
+// containFailure records the failure of a contained unit, the manager keeps
+// running.
+func (m *Manager) containFailure(w *WorkUnitManager, err error) {
+	m.failf(w.name, err, nil, "Contained failure of <%s> (trace %s)\n", w, m.unitTrace(w))
+	m.emitUnit(EventUnitPanic, w, err)
+
+	if m.historyPath != "" {
+		if err := m.saveHistory(); err != nil {
+			m.warnf("", "Could not save history: %s\n", err)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package gum
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFailureContain(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&readyWorker{}, "", WithName("server"))
+	manager.AddUnit(funcWorker(func(UnitManager) { panic("boom") }), "",
+		WithName("exporter"), WithFailurePolicy(FailureContain))
+	// Restarted once, then contained past the restart intensity
+	flaky := &flakyWorker{failures: 3}
+	manager.AddUnit(flaky, "", WithName("flaky"), WithFailurePolicy(FailureContain),
+		WithRestart(RestartOnFailure), WithRestartBackoff(time.Millisecond, time.Millisecond), WithRestartIntensity(1, time.Minute))
+
+	sub := manager.Subscribe()
+	quit := runAsync(manager)
+
+	failed := map[string]bool{}
+	for len(failed) < 2 {
+		ev := waitEvent(t, sub, EventUnitPanic)
+		failed[ev.Unit] = true
+	}
+	if !failed["exporter"] || !failed["flaky"] {
+		t.Fatalf("unexpected failures %v", failed)
+	}
+	if flaky.runs.Load() != 2 {
+		t.Fatalf("expected the contained unit to be restarted once, got %d runs", flaky.runs.Load())
+	}
+
+	for name, state := range map[string]UnitState{"server": Running, "exporter": Failed, "flaky": Failed} {
+		waitUnitState(t, manager, name, state)
+	}
+
+	manager.Stop()
+	<-quit
+	if manager.Err() != nil {
+		t.Fatalf("expected contained failures not to be a shutdown cause, got %v", manager.Err())
+	}
+
+	// Escalated by default
+	manager = NewManager()
+	manager.AddUnit(&readyWorker{}, "", WithName("server"))
+	manager.AddUnit(&panicWorker{}, "", WithName("exporter"))
+	manager.Run()
+	if !errors.Is(manager.Err(), ErrUnitPanic) {
+		t.Fatalf("expected the failure to shut the manager down, got %v", manager.Err())
+	}
+
+	manager = NewManager()
+	manager.AddUnit(&readyWorker{}, "", WithFailurePolicy(-1))
+	if err := manager.Validate(); err == nil {
+		t.Fatal("expected an unknown failure policy to be reported")
+	}
+}
+
+func waitUnitState(t *testing.T, manager *Manager, name string, state UnitState) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		u, _ := manager.Status(name)
+		if u.State == state {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected <%s> %s, got %s", name, state, u.State)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,37 @@
+package gum
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// WithRandSource sets the random source of all the jitter applied by the
+// manager, e.g. when recycling units. Use a seeded source to make restart
+// schedules reproducible in tests.
+func WithRandSource(src rand.Source) Option {
+	return func(m *Manager) {
+		if src == nil {
+			m.invalid(fmt.Errorf("nil random source"))
+			return
+		}
+		m.rand = rand.New(src)
+	}
+}
+
+// WithSeed seeds the random source of the manager, see WithRandSource.
+func WithSeed(seed int64) Option {
+	return WithRandSource(rand.NewSource(seed))
+}
+
+// jitter returns a random duration in [0, max).
+func (m *Manager) jitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+
+	m.randMu.Lock()
+	defer m.randMu.Unlock()
+
+	return time.Duration(m.rand.Int63n(int64(max)))
+}
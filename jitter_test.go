@@ -0,0 +1,30 @@
+package gum
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSeededJitter(t *testing.T) {
+	a, b := NewManager(WithSeed(42)), NewManager(WithSeed(42))
+
+	for i := 0; i < 10; i++ {
+		ja, jb := a.jitter(time.Hour), b.jitter(time.Hour)
+		if ja != jb {
+			t.Fatalf("expected reproducible jitter, got %s and %s", ja, jb)
+		}
+		if ja < 0 || ja >= time.Hour {
+			t.Fatalf("jitter %s out of range", ja)
+		}
+	}
+
+	if j := a.jitter(0); j != 0 {
+		t.Fatalf("expected no jitter, got %s", j)
+	}
+}
+
+func TestNilRandSource(t *testing.T) {
+	if err := NewManager(WithRandSource(nil)).Validate(); err == nil {
+		t.Fatal("expected nil random source to be invalid")
+	}
+}
@@ -0,0 +1,89 @@
+package gum
+
+import (
+	"math"
+	"time"
+)
+
+// Latency histogram buckets grow by a factor of 2^(1/4) from 1ms, covering
+// about 17 minutes with a relative error below 20%.
+const (
+	latencyBase       = time.Millisecond
+	latencyBucketsLog = 4 // Buckets per power of two
+	latencyBuckets    = 20*latencyBucketsLog + 1
+)
+
+// Percentiles summarizes a distribution of durations.
+type Percentiles struct {
+	Count uint64        `json:"count"`
+	P50   time.Duration `json:"p50"`
+	P90   time.Duration `json:"p90"`
+	P99   time.Duration `json:"p99"`
+	Max   time.Duration `json:"max"`
+}
+
+// latencyHistogram is a fixed size histogram of durations. It is guarded by
+// the manager's regMu.
+type latencyHistogram struct {
+	counts [latencyBuckets + 1]uint64 // Last bucket is the overflow
+	count  uint64
+	max    time.Duration
+}
+
+func latencyBucket(d time.Duration) int {
+	if d <= latencyBase {
+		return 0
+	}
+	i := int(math.Ceil(latencyBucketsLog * math.Log2(float64(d)/float64(latencyBase))))
+	if i > latencyBuckets {
+		return latencyBuckets
+	}
+	return i
+}
+
+// latencyBound returns the upper bound of the bucket.
+func latencyBound(i int) time.Duration {
+	return time.Duration(float64(latencyBase) * math.Exp2(float64(i)/latencyBucketsLog))
+}
+
+func (h *latencyHistogram) record(d time.Duration) {
+	h.counts[latencyBucket(d)]++
+	h.count++
+	if d > h.max {
+		h.max = d
+	}
+}
+
+// percentile returns the upper bound of the bucket holding the p quantile,
+// capped to the maximum recorded duration.
+func (h *latencyHistogram) percentile(p float64) time.Duration {
+	if h.count == 0 {
+		return 0
+	}
+
+	rank := uint64(math.Ceil(p * float64(h.count)))
+	var seen uint64
+	for i, c := range h.counts {
+		seen += c
+		if seen >= rank {
+			if i == latencyBuckets {
+				break // Overflow
+			}
+			return min(latencyBound(i), h.max)
+		}
+	}
+	return h.max
+}
+
+func (h *latencyHistogram) percentiles() Percentiles {
+	if h == nil {
+		return Percentiles{}
+	}
+	return Percentiles{
+		Count: h.count,
+		P50:   h.percentile(0.5),
+		P90:   h.percentile(0.9),
+		P99:   h.percentile(0.99),
+		Max:   h.max,
+	}
+}
@@ -0,0 +1,64 @@
+package gum
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLatencyHistogram(t *testing.T) {
+	var h latencyHistogram
+	for i := 1; i <= 100; i++ {
+		h.record(time.Duration(i) * 10 * time.Millisecond)
+	}
+
+	p := h.percentiles()
+	if p.Count != 100 || p.Max != time.Second {
+		t.Fatalf("unexpected count or max: %+v", p)
+	}
+
+	// Buckets have a relative error below 20%
+	for _, tt := range []struct {
+		got, want time.Duration
+	}{
+		{p.P50, 500 * time.Millisecond},
+		{p.P90, 900 * time.Millisecond},
+		{p.P99, 990 * time.Millisecond},
+	} {
+		if tt.got < tt.want || tt.got > tt.want*6/5 {
+			t.Errorf("expected about %s, got %s", tt.want, tt.got)
+		}
+	}
+}
+
+func TestLatencyHistogramOverflow(t *testing.T) {
+	var h latencyHistogram
+	h.record(time.Hour)
+
+	if p := h.percentiles(); p.P50 != time.Hour {
+		t.Fatalf("expected overflow to report the max, got %s", p.P50)
+	}
+}
+
+func TestStopLatenciesLineage(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&readyWorker{}, "api")
+
+	go manager.Run()
+	waitState(t, manager, 0, Running)
+
+	if err := manager.SwapUnit(context.Background(), manager.order[0].name, &readyWorker{}); err != nil {
+		t.Fatal(err)
+	}
+	waitUnits(t, manager, 1)
+
+	manager.Stop()
+	<-manager.Quit
+
+	// The replaced unit and its replacement share the distribution
+	for _, u := range manager.Snapshot().Units {
+		if u.StopLatencies.Count != 2 {
+			t.Fatalf("expected 2 stop latencies for <%s>, got %+v", u.Name, u.StopLatencies)
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package gum
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Log verbosity of the manager
+const (
+	logQuiet   = iota // Manager messages only
+	logNormal         // Manager and unit lifecycle messages
+	logVerbose        // Every event
+)
+
+// Lifecycle phases of the manager, tagged on structured logs
+const (
+	lifecycleInit int32 = iota
+	lifecycleStartup
+	lifecycleRunning
+	lifecycleShutdown
+	lifecycleStopped
+)
+
+var lifecycleNames = [...]string{
+	lifecycleInit:     "init",
+	lifecycleStartup:  "startup",
+	lifecycleRunning:  "running",
+	lifecycleShutdown: "shutdown",
+	lifecycleStopped:  "stopped",
+}
+
+// WithLogger sets the logger used by the manager. It defaults to the
+// standard logger.
+func WithLogger(l *log.Logger) Option {
+	return func(m *Manager) {
+		if l == nil {
+			m.invalid(fmt.Errorf("nil logger, use WithSilent to disable logging"))
+			return
+		}
+		m.logger = l
+	}
+}
+
+// WithSlog logs through a structured logger instead of the standard logger.
+// Records carry the lifecycle phase of the manager (init, startup, running,
+// shutdown or stopped), the unit name when the message is about a unit and
+// the baggage as a group. Failures are logged at the warn level and events
+// at the level of their severity, the other messages at the info level.
+func WithSlog(l *slog.Logger) Option {
+	return func(m *Manager) {
+		if l == nil {
+			m.invalid(fmt.Errorf("nil slog logger, use WithSilent to disable logging"))
+			return
+		}
+		m.slog = l
+	}
+}
+
+// WithSilent disables the manager logs, for libraries embedding a manager
+// and reporting its events through their own logging.
+func WithSilent() Option {
+	return func(m *Manager) {
+		m.silent = true
+	}
+}
+
+// logf logs a manager message.
+func (m *Manager) logf(format string, args ...any) {
+	m.log(slog.LevelInfo, "", format, args...)
+}
+
+// warnf logs a failure, of the unit if not empty.
+func (m *Manager) warnf(unit string, format string, args ...any) {
+	m.log(slog.LevelWarn, unit, format, args...)
+}
+
+// unitLogf logs a lifecycle message of the unit.
+func (m *Manager) unitLogf(unit string, format string, args ...any) {
+	if m.currentVerbosity() >= logNormal {
+		m.log(slog.LevelInfo, unit, format, args...)
+	}
+}
+
+// failf logs a failure of the unit. The error text, and the stack if any,
+// are carried as fields rather than interpolated into the message: unit
+// errors may contain newlines or anything looking like log structure.
+func (m *Manager) failf(unit string, err error, stack []byte, format string, args ...any) {
+	fields := []slog.Attr{slog.String("err", err.Error())}
+	if stack != nil {
+		fields = append(fields, slog.String("stack", string(stack)))
+	}
+	m.logAttrs(slog.LevelWarn, unit, fields, format, args...)
+}
+
+// log writes a message to the configured logger.
+func (m *Manager) log(level slog.Level, unit string, format string, args ...any) {
+	m.logAttrs(level, unit, nil, format, args...)
+}
+
+// logAttrs writes a message with fields to the configured logger. The
+// standard logger gets the fields appended as quoted key=value pairs, so
+// they stay on the line of the message.
+func (m *Manager) logAttrs(level slog.Level, unit string, fields []slog.Attr, format string, args ...any) {
+	m.settingsMu.RLock()
+	logger, slogger, silent := m.logger, m.slog, m.silent
+	m.settingsMu.RUnlock()
+
+	switch {
+	case silent:
+	case slogger != nil:
+		attrs := []slog.Attr{slog.String("phase", lifecycleNames[m.lifecycle.Load()])}
+		if unit != "" {
+			attrs = append(attrs, slog.String("unit", unit))
+		}
+		attrs = append(attrs, fields...)
+		msg := strings.TrimRight(fmt.Sprintf(format, args...), "\n ")
+		slogger.LogAttrs(context.Background(), level, msg, attrs...)
+	case len(fields) > 0:
+		var b strings.Builder
+		b.WriteString(strings.TrimRight(fmt.Sprintf(format, args...), "\n "))
+		for _, f := range fields {
+			b.WriteString(" " + f.Key + "=" + strconv.Quote(f.Value.String()))
+		}
+		logger.Printf(m.logPrefix+"%s", b.String())
+	default:
+		logger.Printf(m.logPrefix+format, args...)
+	}
+}
+
+// logEvent logs the event, its error as a field.
+func (m *Manager) logEvent(ev Event) {
+	if ev.Err == nil {
+		m.log(severityLevel(ev.Severity), ev.Unit, "event: %s\n", ev)
+		return
+	}
+	fields := []slog.Attr{slog.String("err", ev.Err.Error())}
+	ev.Err = nil
+	m.logAttrs(severityLevel(ev.Severity), ev.Unit, fields, "event: %s\n", ev)
+}
+
+// severityLevel returns the slog level of an event severity.
+func severityLevel(s Severity) slog.Level {
+	switch {
+	case s >= SeverityError:
+		return slog.LevelError
+	case s == SeverityWarn:
+		return slog.LevelWarn
+	case s == SeverityInfo:
+		return slog.LevelInfo
+	default:
+		return slog.LevelDebug
+	}
+}
+
+var verbosityNames = [...]string{
+	logQuiet:   "quiet",
+	logNormal:  "normal",
+	logVerbose: "verbose",
+}
+
+func parseVerbosity(s string) (int, error) {
+	for v, name := range verbosityNames {
+		if name == s {
+			return v, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown log verbosity %q", s)
+}
+
+// currentVerbosity returns the log verbosity, which may be tuned while the
+// manager runs.
+func (m *Manager) currentVerbosity() int {
+	m.settingsMu.RLock()
+	defer m.settingsMu.RUnlock()
+	return m.verbosity
+}
+
+// SetLogger swaps the logger of the manager while it runs, e.g. to raise the
+// log level of a live process temporarily. The change applies at once to the
+// manager logs and to the unit loggers, see UnitManager.Logger.
+func (m *Manager) SetLogger(l *log.Logger) error {
+	if l == nil {
+		return fmt.Errorf("nil logger")
+	}
+
+	m.settingsMu.Lock()
+	m.logger, m.slog, m.silent = l, nil, false
+	m.settingsMu.Unlock()
+
+	m.logf("logger changed\n")
+	return nil
+}
+
+// SetSlog swaps the structured logger of the manager while it runs, as
+// SetLogger does. The baggage is added to its records.
+func (m *Manager) SetSlog(l *slog.Logger) error {
+	if l == nil {
+		return fmt.Errorf("nil slog logger")
+	}
+
+	m.settingsMu.Lock()
+	m.slog, m.slogBase, m.silent = m.withBaggage(l), l, false
+	m.settingsMu.Unlock()
+
+	m.logf("logger changed\n")
+	return nil
+}
+
+// DebugLogOn registers signals toggling verbose logging, e.g. SIGUSR2: the
+// first signal sets the log verbosity to verbose, the next one restores it,
+// see SettingLogVerbosity.
+func (m *Manager) DebugLogOn(sig ...os.Signal) {
+	var mu sync.Mutex
+	restore := ""
+	for _, s := range sig {
+		m.HandleSignal(s, func() {
+			mu.Lock()
+			defer mu.Unlock()
+
+			if restore == "" {
+				restore = m.Settings()[SettingLogVerbosity]
+				m.Tune(SettingLogVerbosity, verbosityNames[logVerbose])
+				return
+			}
+			m.Tune(SettingLogVerbosity, restore)
+			restore = ""
+		})
+	}
+}
+
+// unitHandler is the slog handler of the unit loggers. It logs through the
+// current logger of the manager, so swapping the logger or the verbosity
+// applies to the units as well. Debug records are only logged with the
+// verbose verbosity.
+type unitHandler struct {
+	m      *Manager
+	unit   string
+	attrs  []slog.Attr
+	prefix string // Of the keys, from the groups
+}
+
+// Logger returns a logger tagging the records with the unit name, which
+// logs through the manager logger. See SetLogger.
+func (w *WorkUnitManager) Logger() *slog.Logger {
+	return slog.New(&unitHandler{m: w.manager, unit: w.name})
+}
+
+func (h *unitHandler) Enabled(_ context.Context, level slog.Level) bool {
+	h.m.settingsMu.RLock()
+	defer h.m.settingsMu.RUnlock()
+	return !h.m.silent && (level > slog.LevelDebug || h.m.verbosity >= logVerbose)
+}
+
+func (h *unitHandler) Handle(_ context.Context, r slog.Record) error {
+	fields := append([]slog.Attr(nil), h.attrs...)
+	r.Attrs(func(a slog.Attr) bool {
+		fields = append(fields, h.prefixed(a))
+		return true
+	})
+	h.m.logAttrs(r.Level, h.unit, fields, "%s", r.Message)
+	return nil
+}
+
+func (h *unitHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := *h
+	c.attrs = slices.Clip(c.attrs)
+	for _, a := range attrs {
+		c.attrs = append(c.attrs, h.prefixed(a))
+	}
+	return &c
+}
+
+func (h *unitHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	c := *h
+	c.prefix += name + "."
+	return &c
+}
+
+func (h *unitHandler) prefixed(a slog.Attr) slog.Attr {
+	a.Key = h.prefix + a.Key
+	return a
+}
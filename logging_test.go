@@ -0,0 +1,195 @@
+package gum
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"log/slog"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestSlog(t *testing.T) {
+	var buf bytes.Buffer
+	manager := NewManager(
+		WithSlog(slog.New(slog.NewJSONHandler(&buf, nil))),
+		WithBaggage(map[string]string{"run_id": "42"}),
+	)
+	manager.AddUnit(&stopWorker{}, "", WithName("worker"))
+
+	go manager.Run()
+	waitState(t, manager, 0, Running)
+	manager.Stop()
+	<-manager.Quit
+
+	phases := make(map[string]bool)
+	var unitTagged bool
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var rec struct {
+			Msg     string
+			Phase   string
+			Unit    string
+			Baggage map[string]string
+		}
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("invalid record %q: %v", line, err)
+		}
+		if strings.HasSuffix(rec.Msg, "\n") {
+			t.Fatalf("expected the trailing newline to be trimmed, got %q", rec.Msg)
+		}
+		if rec.Baggage["run_id"] != "42" {
+			t.Fatalf("expected the baggage on %q", line)
+		}
+		phases[rec.Phase] = true
+		if rec.Unit == "worker" {
+			unitTagged = true
+		}
+	}
+
+	for _, phase := range []string{"startup", "shutdown"} {
+		if !phases[phase] {
+			t.Errorf("expected records in the %s phase, got %v", phase, phases)
+		}
+	}
+	if !unitTagged {
+		t.Errorf("expected records tagged with the unit\n%s", buf.String())
+	}
+}
+
+func TestSilent(t *testing.T) {
+	var buf bytes.Buffer
+	manager := NewManager(WithLogger(log.New(&buf, "", 0)), WithSilent())
+	manager.AddUnit(&stopWorker{}, "")
+
+	go manager.Run()
+	manager.Stop()
+	<-manager.Quit
+
+	if buf.Len() > 0 {
+		t.Fatalf("expected no logs, got:\n%s", buf.String())
+	}
+}
+
+func TestNilLogger(t *testing.T) {
+	if err := NewManager(WithSlog(nil)).Validate(); err == nil {
+		t.Fatal("expected a nil slog logger to be invalid")
+	}
+	if err := NewManager(WithLogger(nil)).Validate(); err == nil {
+		t.Fatal("expected a nil logger to be invalid")
+	}
+}
+
+func TestPanicFields(t *testing.T) {
+	errBad := errors.New("bad input\npanic: %s")
+	crash := funcWorker(func(um UnitManager) {
+		um.Panic(errBad)
+	})
+
+	var buf bytes.Buffer
+	manager := NewManager(WithLogger(log.New(&buf, "", 0)))
+	manager.AddUnit(crash, "", WithName("crash"))
+	manager.Run()
+
+	var found bool
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if strings.HasPrefix(line, "Panicing for <crash>") {
+			found = strings.HasSuffix(line, ` err="bad input\npanic: %s"`)
+		}
+		if strings.HasPrefix(line, "panic: ") {
+			t.Fatalf("expected the error text to stay on its line:\n%s", buf.String())
+		}
+	}
+	if !found {
+		t.Fatalf("expected the error as a quoted field:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	manager = NewManager(WithSlog(slog.New(slog.NewJSONHandler(&buf, nil))))
+	manager.AddUnit(crash, "", WithName("crash"))
+	manager.Run()
+
+	if !strings.Contains(buf.String(), `"msg":"Panicing for <crash> (trace `) ||
+		!strings.Contains(buf.String(), `"err":"bad input\npanic: %s"`) {
+		t.Fatalf("expected the error as a field:\n%s", buf.String())
+	}
+}
+
+func TestSetLogger(t *testing.T) {
+	var before, after syncBuffer
+	logs := make(chan *slog.Logger, 1)
+	manager := NewManager(WithLogger(log.New(&before, "", 0)), WithBaggage(map[string]string{"run_id": "42"}))
+	manager.AddUnit(funcWorker(func(um UnitManager) {
+		logs <- um.Logger()
+		um.Ready()
+		<-um.ShouldStop()
+		um.Done()
+	}), "", WithName("worker"))
+
+	sub := manager.Subscribe()
+	quit := runAsync(manager)
+	waitEvent(t, sub, EventStartupComplete)
+	logger := <-logs
+
+	logger.Debug("hidden")
+	logger.Info("polling", "topic", "orders")
+	if !strings.Contains(before.String(), `polling topic="orders"`) || strings.Contains(before.String(), "hidden") {
+		t.Fatalf("unexpected unit logs:\n%s", before.String())
+	}
+
+	if err := manager.SetSlog(slog.New(slog.NewJSONHandler(&after, &slog.HandlerOptions{Level: slog.LevelDebug}))); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Tune(SettingLogVerbosity, "verbose"); err != nil {
+		t.Fatal(err)
+	}
+	logger.WithGroup("poll").Debug("shown", "topic", "orders")
+
+	var rec struct {
+		Msg     string
+		Unit    string
+		Topic   string `json:"poll.topic"`
+		Baggage map[string]string
+	}
+	for _, line := range strings.Split(strings.TrimSpace(after.String()), "\n") {
+		if strings.Contains(line, `"shown"`) {
+			if err := json.Unmarshal([]byte(line), &rec); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if rec.Unit != "worker" || rec.Topic != "orders" || rec.Baggage["run_id"] != "42" {
+		t.Fatalf("unexpected unit record %+v in:\n%s", rec, after.String())
+	}
+	if strings.Contains(before.String(), "shown") {
+		t.Fatal("expected the swapped logger not to be used anymore")
+	}
+
+	if err := manager.SetLogger(nil); err == nil {
+		t.Fatal("expected an error setting a nil logger")
+	}
+
+	manager.Stop()
+	<-quit
+}
+
+func TestDebugLogOn(t *testing.T) {
+	manager := NewManager(WithSilent())
+	manager.DebugLogOn(syscall.SIGUSR2)
+	manager.AddUnit(&readyWorker{}, "")
+
+	sub := manager.Subscribe()
+	quit := runAsync(manager)
+	waitEvent(t, sub, EventStartupComplete)
+
+	for _, want := range []string{"verbose", "normal"} {
+		manager.signalIn <- syscall.SIGUSR2
+		if ev := waitEvent(t, sub, EventSettingChanged); ev.Setting.New != want {
+			t.Fatalf("expected the verbosity to be %s, got %+v", want, ev.Setting)
+		}
+	}
+
+	manager.Stop()
+	<-quit
+}
@@ -1,12 +1,22 @@
 package gum
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"log/slog"
+	"math/rand"
+	"net"
+	"net/http"
 	"os"
-	"os/signal"
 	"reflect"
+	"regexp"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 var idGenerator = genID()
@@ -17,173 +27,1287 @@ type WorkUnit interface {
 	Run(UnitManager)
 }
 
+// The Namer interface can optionally be implemented by a unit to provide a
+// human readable name. It is preferred over the name derived from the unit's
+// type.
+type Namer interface {
+	Name() string
+}
+
+// The Describer interface can optionally be implemented by a unit to provide
+// a short description shown in the manager's status output.
+type Describer interface {
+	Describe() string
+}
+
 // The UnitManager interface is used to manage a unit of work.
-// The ShouldStop method returns a channel that will be closed when the unit
-// should stop.
+// The ShouldStop method returns a channel that receives a value once when the
+// unit should stop.
+// The Stopping method tells if the unit was asked to stop. It is latched so
+// units still initializing can't miss the stop request.
+// The Ready method should be called once the unit is initialized.
 // The Done method should be called when the unit is done.
+// The Info method returns the identity of the unit.
+// The Park method tells the manager the unit is intentionally dormant.
+// The Pressure method returns the runtime pressure so background units can
+// slow down when the process is under load.
+// The Signals method subscribes the unit to OS signals.
+// The Context method returns a context cancelled as soon as the unit is asked
+// to stop, to cancel its blocking calls.
+// The ShutdownContext method returns, once stopping, a context whose deadline
+// is the end of the shutdown budget.
+// The ShutdownMode method tells if the unit is stopped gracefully or if it
+// should only do a minimal cleanup.
 type UnitManager interface {
 	ShouldStop() <-chan bool
+	Stopping() bool
 	Done()
 	Panic(err error)
+	ShutdownMode() ShutdownMode
+	Signals(sig ...os.Signal) <-chan os.Signal
+	Context() context.Context
+	ShutdownContext() context.Context
+	Ready()
+	Pressure() Pressure
+	Info() UnitInfo
+	Park(until time.Time) <-chan struct{}
+	OnStop(f func())
+	Barrier(name string) *Barrier
+	Logger() *slog.Logger
 }
 
 type WorkUnitManager struct {
-	stop       chan bool
-	workerQuit chan bool
-	unit       WorkUnit
-	panic      chan error
-	isPaniced  bool
+	name    string
+	base    string // Name given to AddUnit
+	lineage string // Shared by the instances replacing the unit
+	info    UnitInfo
+	stop    chan bool
+	ctx     context.Context // Cancelled on stop, see Context
+	cancel  context.CancelFunc
+	unit    WorkUnit
+	manager *Manager
+
+	started atomic.Bool // Run was called
+
+	// Status, guarded by the manager's regMu
+	state       UnitState
+	startedAt   time.Time
+	stopAt      time.Time // Stop requested
+	stoppedAt   time.Time
+	err         error
+	ready       bool
+	readyAt     time.Time
+	parkedUntil time.Time
+	wake        chan struct{} // Closed when a parked unit is woken
+	parkTimer   interface{ Stop() bool }
+	panics      *panicHistory // Of the unit lineage
+
+	onStopMu sync.Mutex
+	onStop   []func() // Run once the stop is requested
+
+	slotHeld atomic.Bool // Holds a startup concurrency slot
+	stopping atomic.Bool // Stop was requested
+	settled  atomic.Bool // Counted as ready for the startup completion
+	done     atomic.Bool // Done was called
+	removed  atomic.Bool // Unregistered once done, see RemoveUnit
+	awaited  bool        // Waited for by the shutdown
+	drained  bool        // Done was handled by the manager
+
+	// Closed on Ready and Done, only for units started by SwapUnit
+	readyC chan struct{}
+	doneCh chan struct{}
+
+	description   string
+	family        string                   // Template family or pool, see InstantiateTemplate and AddPool
+	task          bool                     // See AsTask
+	failurePolicy FailurePolicy            // See WithFailurePolicy
+	preStop       []StopHook               // See WithPreStop
+	postStop      []StopHook               // See WithPostStop
+	after         []string                 // Dependencies, see After
+	afterWithin   map[string]time.Duration // Dependency timeouts, see AfterWithin
+	readyTimeout  time.Duration
+	configErrs    []error
+	opts          []UnitOption // Given to AddUnit, reused to recycle the unit
+
+	recycleEvery  time.Duration
+	recycleJitter time.Duration
+	recycleNew    func() WorkUnit
+	restartHooks  []RestartHook
+
+	restartPolicy     RestartPolicy
+	restartBackoff    time.Duration
+	restartMaxBackoff time.Duration
+	restartIntensity  int
+	restartPeriod     time.Duration
+	restartChecked    atomic.Bool // The restart policy was applied
+	restarting        atomic.Bool // A new instance is scheduled
+
+	startRetry    bool // See WithStartRetry
+	startRetries  int
+	startBackoff  time.Duration
+	startAttempt  int  // Earlier failed starts of the unit
+	retryingStart bool // The new instance retries the start
+
+	panicBudget       int
+	panicBudgetWindow time.Duration
+
+	memoryBudget uint64
+	overMemory   bool // Guarded by the manager's regMu
+	fdBudget     int  // See WithFDBudget
+
+	stopLatencies *latencyHistogram // Of the unit lineage
+	replacedBy    *WorkUnitManager  // New instance, guarded by the manager's regMu
+	restarts      int               // Earlier instances of the lineage
+	traceID       string            // Latest restart chain or failure, guarded by the manager's regMu
+	clockedOut    atomic.Bool
+}
+
+func (w *WorkUnitManager) ShutdownMode() ShutdownMode {
+	return w.manager.ShutdownMode()
+}
+
+// ShutdownContext returns a context carrying the deadline of the ongoing
+// shutdown so the unit can size its drain work.
+func (w *WorkUnitManager) ShutdownContext() context.Context {
+	return w.manager.ShutdownContext()
+}
+
+// Context returns the context of the unit. It is cancelled as soon as the
+// unit is asked to stop, or once it is done, and carries the manager
+// baggage, see WithBaggage. Pass it to blocking calls such as HTTP requests
+// or database queries so they are cancelled by the stop.
+func (w *WorkUnitManager) Context() context.Context {
+	return w.ctx
 }
 
 func (w *WorkUnitManager) ShouldStop() <-chan bool {
 	return w.stop
 }
 
+// Stopping reports whether the unit was asked to stop. Once true it stays
+// true, it can be polled by units which are not yet selecting on ShouldStop.
+func (w *WorkUnitManager) Stopping() bool {
+	return w.stopping.Load()
+}
+
+// requestStop asks the unit to stop and reports whether it was. The request
+// is delivered on ShouldStop only once, further requests are no-ops, so it
+// never blocks even if the unit doesn't listen.
+//
+// A unit calling Done concurrently with the stop request is either done
+// first, the request is then a no-op: ShouldStop is not signaled, the OnStop
+// callbacks are not run and the unit is not Stopping; or asked to stop
+// first, Done then completes the stop as usual. Either way the unit is
+// drained once by the shutdown.
+func (w *WorkUnitManager) requestStop() bool {
+	if w.done.Load() || !w.stopping.CompareAndSwap(false, true) {
+		return false
+	}
+	w.stop <- true // Buffered, only sent once
+	w.cancel()
+	w.stopRequested()
+	return true
+}
+
+// String returns the name of the unit. Logging the unit rather than its
+// name avoids allocating on the lifecycle hot path.
+func (w *WorkUnitManager) String() string {
+	return w.name
+}
+
+// Done notifies the manager that the unit is done. It can be called more
+// than once and never blocks.
 func (w *WorkUnitManager) Done() {
-	w.workerQuit <- true
+	if !w.done.CompareAndSwap(false, true) {
+		if w.manager.strict {
+			w.manager.logf("strict: <%s> called Done more than once\n", w)
+		}
+		return
+	}
+	w.cancel()
+	if w.doneCh != nil {
+		close(w.doneCh)
+	}
+	w.manager.releaseSlot(w)
+	if !w.retryingStart {
+		w.manager.unitSettled(w)
+	}
+	w.manager.setState(w, Stopped, nil)
+	w.manager.clockOut(w, false)
+	w.manager.unitDone(w)
+
+	if w.task && w.manager.completeTask(w) {
+		w.manager.unitLogf(w.name, "<%s> completed\n", w)
+	}
+	if w.removed.Load() {
+		go w.manager.protect("removal", func() {
+			w.manager.startMu.Lock()
+			defer w.manager.startMu.Unlock()
+			w.manager.unregister(w)
+		})
+	}
+
+	if _, err := w.manager.restartUnit(w, nil); err != nil {
+		w.manager.setState(w, Failed, err)
+		w.manager.unitPanic(w, err)
+	}
+	w.manager.checkEmpty(w)
 }
 
+// Panic reports a failure of the unit to the manager and marks the unit as
+// done. It never blocks. Unless the unit is restarted, see WithRestart, the
+// manager shuts down.
 func (w *WorkUnitManager) Panic(err error) {
-	w.panic <- err
-	w.isPaniced = true
-	w.workerQuit <- true
-	close(w.stop)
+	w.manager.setTrace(w, w.manager.newTraceID())
+	w.manager.setState(w, Failed, err)
+	w.manager.recordPanic(w)
+
+	restarting, escalate := w.manager.restartUnit(w, err)
+	if escalate != nil {
+		err = fmt.Errorf("%w: %w", escalate, err)
+	}
+	if !restarting {
+		w.manager.unitPanic(w, err)
+	}
+	w.Done()
 }
 
 type Manager struct {
 	signalIn chan os.Signal
 
-	shutdownSigs []os.Signal
+	shutdownSigs  []os.Signal
+	immediateSigs []os.Signal
+	reloadSigs    []os.Signal
+	forceExit     bool // See WithForceExit
+	forceExitCode int
+
+	reloadMu      sync.Mutex // Serializes reloads
+	reloadTimeout time.Duration
+	depTimeout    time.Duration // See WithDependencyTimeout
+	mode          atomic.Int32  // ShutdownMode
 
+	events eventBus
+
+	mu          sync.Mutex
+	signalSubs  []signalSub
+	signalHooks []signalHook
+	shutdownCtx context.Context
+
+	settingsMu      sync.RWMutex // Guards the settings tunable at runtime and the loggers, see Tune
+	shutdownTimeout time.Duration
+	phaseTimeouts   [PhaseFinalize + 1]time.Duration
+	finalizers      []Finalizer
+
+	// Unit registry, every change increments version
+	regMu   sync.RWMutex
+	version uint64
+	changed chan struct{} // Closed on the next change, see Watch
 	workers map[string]*WorkUnitManager
+	order   []*WorkUnitManager // Registration order
+
+	stopLatencies map[string]*latencyHistogram // By unit lineage
+	starts        map[string]int               // Instances started, by unit lineage
+	restartTimes  map[string][]time.Time       // Within the restart period, by unit lineage
+	panicHistory  map[string]*panicHistory     // By unit lineage
+
+	wheelMu      sync.Mutex
+	wheel        *timerWheel // Times the scheduled units, see timers
+	wheelStopped bool        // The manager quit
+
+	startSem      chan struct{} // Startup concurrency slots
+	startMu       sync.Mutex    // Guards starting units and changes of order
+	running       bool          // Run started the initial units, guarded by startMu
+	startStop     chan struct{}
+	startDone     chan struct{}
+	reportDone    chan struct{} // Closed once the startup report is done
+	startStopOnce sync.Once
+	flags         FlagProvider
+
+	Quit  chan bool
+	quitC chan struct{} // Closed once Quit is notified
+
+	stopC    chan struct{}
+	stopOnce sync.Once
+	stopCtx  context.Context // Given to Shutdown, guarded by mu
+
+	startupTimeout time.Duration
+	abortC         chan struct{} // Closed when the startup times out
+	rollingBack    atomic.Bool   // Stop units one at a time in reverse order
+
+	// Units which called Panic
+	panicMu    sync.Mutex
+	panicQueue []unitPanic
+	panicC     chan struct{}
+
+	panicPolicy PanicPolicy
+	emptyPolicy EmptyPolicy
+	idle        atomic.Bool // No unit running, see checkEmpty
+	historyPath string      // Persisted panic history
+	exitCodes   []exitCode
+	reasonCodes map[StopReason]int // See WithReasonExitCode
+	reason      atomic.Int32       // StopReason
+	envPrefix   string
+
+	// Control handler served by the manager, see WithControlAddr
+	controlAddr string
+	controlMu   sync.Mutex
+	controlSrv  *http.Server
+	controlLn   net.Listener
+
+	shutdownReport string        // Path of the ShutdownReport
+	shutdownAt     time.Time     // Guarded by regMu
+	phases         []PhaseReport // Guarded by regMu
+	shutdownChain  []string      // Guarded by regMu
+	summary        *Summary      // Guarded by regMu
+
+	strategy Strategy // See WithStrategy
+
+	// Topology freeze, see Freeze
+	freezePolicy FreezePolicy
+	freezeMu     sync.Mutex
+	frozen       bool
+	freezeReason string
+	freezeQueue  []func()
+	unfrozen     chan struct{} // Closed by Unfreeze
+
+	factories     map[string]UnitFactory
+	templates     map[string]UnitTemplate // See WithUnitTemplate
+	poolMu        sync.Mutex              // Serializes the pool resizes
+	pools         map[string]pool         // See AddPool
+	stableNames   bool                    // See WithStableNames
+	idsMu         sync.Mutex
+	ids           map[string]int // Next unit ID by name prefix, with stable names
+	topologyStore TopologyStore
+	specs         []UnitSpec // Added with AddSpec
+	unitDefaults  []UnitOption
+
+	logger      *log.Logger
+	logPrefix   string // Baggage
+	verbosity   int
+	logSeverity Severity     // Events logged whatever the verbosity
+	slog        *slog.Logger // See WithSlog
+	slogBase    *slog.Logger // Without the baggage, inherited by SubManager
+	silent      bool
+	lifecycle   atomic.Int32 // Tagged on structured logs
+	strict      bool         // Report misuses of the UnitManager API
+	noSignals   bool         // Do not call signal.Notify
+
+	build   *BuildInfo
+	baggage map[string]string // Immutable once the manager is created
+	baseCtx context.Context   // Carries the baggage
+
+	maxUnavailable int
+	recycleSem     chan struct{} // Units being recycled
+	restartHooks   []RestartHook
+	accounting     []Accounting
+
+	notifiers     []notifier
+	notifyMu      sync.Mutex
+	notifySubs    []*Subscription // Closed on quit, guarded by notifyMu
+	notifyStopped bool            // Guarded by notifyMu
+	notifyWG      sync.WaitGroup
+
+	randMu sync.Mutex
+	rand   *rand.Rand // Jitter source
+	clock  Clock
+
+	// Startup completion, once all units are ready
+	readyPending atomic.Int64 // Units not ready yet
+	allReady     chan struct{}
+	allReadyOnce sync.Once
+	startup      *StartupReport // Guarded by regMu
+
+	// Service discovery, see WithRegistrar
+	registrars []Registrar
+	registered []Registrar
+	drainC     chan struct{} // Closed when the shutdown starts
+	regDone    chan struct{}
+
+	sampleInterval time.Duration
+	pressure       atomic.Pointer[Pressure]
+	pressureHooks  []func(Pressure)
+	runtimeMetrics bool          // See WithRuntimeMetrics
+	hookTimeout    time.Duration // See WithHookTimeout
 
-	Quit chan bool
+	barriersMu sync.Mutex
+	barriers   map[string]*Barrier // See Barrier
 
-	panic chan error // Used for panicing goroutines
+	errMu      sync.Mutex
+	err        error     // Shutdown cause
+	configErrs []error   // Guarded by regMu
+	startedAt  time.Time // Guarded by regMu
+
+	// Units which called Done, preallocated to keep Done allocation free.
+	doneMu    sync.Mutex
+	doneQueue []*WorkUnitManager
+	doneSpare []*WorkUnitManager
+	doneC     chan struct{}
+}
+
+// Run starts all registered units and blocks until the manager is shut down,
+// either by one of the registered shutdown signals, a call to Stop or by a
+// panicing unit. It returns the shutdown cause, see Err: the failure of a
+// unit shuts the other units down and is returned, joined with the failures
+// that happened meanwhile.
+func (m *Manager) Run() error {
+	m.run()
+	return m.Err()
+}
+
+// Wait blocks until the manager quit and returns the shutdown cause, see
+// Err, for callers running the manager on another goroutine.
+func (m *Manager) Wait() error {
+	<-m.quitC
+	return m.Err()
 }
 
-func (m *Manager) Run() {
-	log.Println("Starting manager ...")
+func (m *Manager) run() {
+	m.lifecycle.Store(lifecycleStartup)
+	m.logf("Starting manager ...\n")
+	m.startNotifiers()
+
+	if m.topologyStore != nil {
+		m.syncTopology()
+	}
+
+	if err := m.Validate(); err != nil {
+		m.warnf("", "Invalid configuration, not starting:\n%s\n", err)
+		m.addErr(fmt.Errorf("%w: %w", ErrStartup, err))
+		m.setReason(ReasonStartup)
+		m.quit()
+		return
+	}
 
-	for unitName, w := range m.workers {
-		log.Printf("Starting <%s>\n", unitName)
-		go w.unit.Run(w)
+	if err := m.checkResources(m.order); err != nil {
+		m.warnf("", "Not enough resources, not starting: %s\n", err)
+		m.addErr(fmt.Errorf("%w: %w", ErrStartup, err))
+		m.setReason(ReasonStartup)
+		m.quit()
+		return
 	}
 
+	if err := m.serveControl(); err != nil {
+		m.warnf("", "Could not serve the control handler, not starting: %s\n", err)
+		m.addErr(fmt.Errorf("%w: %w", ErrStartup, err))
+		m.setReason(ReasonStartup)
+		m.quit()
+		return
+	}
+
+	if m.historyPath != "" {
+		if err := m.loadHistory(); err != nil {
+			m.warnf("", "Could not load history, starting with an empty one: %s\n", err)
+		}
+	}
+
+	m.regMu.Lock()
+	m.startedAt = m.clock.Now()
+	m.regMu.Unlock()
+	m.doneQueue = make([]*WorkUnitManager, 0, len(m.workers))
+	m.doneSpare = make([]*WorkUnitManager, 0, len(m.workers))
+	if m.build != nil {
+		m.logf("Version %s\n", m.build)
+	}
+	m.emitEvent(Event{Kind: EventManagerStarted, Build: m.build})
+
+	// Units added from now on are started by AddUnit
+	m.startMu.Lock()
+	m.running = true
+	initial := m.order
+	m.startMu.Unlock()
+	initial = m.dependencyOrder(initial)
+
+	m.readyPending.Store(int64(len(initial)))
+	if len(initial) == 0 {
+		m.allReadyOnce.Do(func() { close(m.allReady) })
+		m.checkEmpty(nil)
+	}
+	go m.protect("startup report", m.reportStartup)
+
+	if m.registrars != nil {
+		go m.protect("registration", m.register)
+	}
+
+	startUnits := func() { m.startUnits(initial) }
+	if m.startSem != nil {
+		go m.protect("startup", startUnits)
+	} else {
+		m.protect("startup", startUnits)
+	}
+
+	samplerStop := make(chan struct{})
+	defer close(samplerStop)
+	go m.sampler(samplerStop)
+
+	// Every step is protected, an internal error must not prevent the
+	// shutdown of the units.
 	for {
 		select {
 		case sig := <-m.signalIn:
 
-			if !in(m.shutdownSigs, sig) {
+			var mode ShutdownMode
+			var ok bool
+			m.protect("signal dispatch", func() {
+				m.dispatchSignal(sig)
+				mode, ok = m.signalMode(sig)
+			})
+			if in(m.reloadSigs, sig) {
+				m.logf("reload event received (%s) ... \n", sig)
+				go m.protect("reload", func() { m.Reload() })
+				break
+			}
+			if !ok {
 				break
 			}
 
-			log.Println("shutting event received ... ")
+			m.logf("shutting event received (%s on %s) ... \n", mode, sig)
+			m.mode.Store(int32(mode))
+			m.setReason(ReasonSignal)
+
+			m.protect("shutdown", m.shutdown)
+			m.quit()
+			return
+
+		case <-m.stopC:
+
+			m.logf("stop requested ... \n")
+			m.setReason(ReasonShutdownCall)
+
+			m.protect("shutdown", m.shutdown)
+			m.quit()
+			return
+
+		case <-m.abortC:
+
+			m.logf("startup timeout, rolling back ... \n")
+			m.setReason(ReasonStartup)
+
+			m.protect("shutdown", m.shutdown)
+			m.quit()
+			return
 
-			// send shutdown event to all worker units
-			for name, w := range m.workers {
-				log.Printf("shutting down <%s>\n", name)
-				w.stop <- true
+		case <-m.panicC:
+
+			var panics []unitPanic
+			m.protect("panic handling", func() { panics = m.handlePanics() })
+			m.setReason(panicReason(panics))
+			m.protect("shutdown", m.shutdown)
+
+			switch m.panicPolicy {
+			case PanicRethrow:
+				if len(panics) > 0 {
+					panic(panics[0].err)
+				}
+			case PanicExit:
+				code := m.ExitCode()
+				m.logf("Exiting with code %d\n", code)
+				exit(code)
 			}
 
-			// Wait for all units to quit
-			for name, w := range m.workers {
-				<-w.workerQuit
-				log.Printf("<%s> down", name)
+			m.quit()
+			return
+		}
+	}
+}
+
+// quit notifies the end of the manager.
+func (m *Manager) quit() {
+	m.lifecycle.Store(lifecycleStopped)
+	m.protect("quit", func() { m.emit(EventManagerQuit, "", m.Err()) })
+	m.stopNotifiers()
+	m.stopTimers()
+	m.stopControl()
+	m.Quit <- true
+	close(m.quitC)
+}
+
+// shutdown runs the shutdown phases: the quiesce phase deregisters the
+// process from service discovery, the stop phase sends the stop event to all
+// units that are still running and waits for all of them to quit, and the
+// finalize phase runs the finalizers.
+func (m *Manager) shutdown() {
+	ctx, cancel := m.newShutdownContext()
+	defer cancel()
+
+	m.lifecycle.Store(lifecycleShutdown)
+	m.regMu.Lock()
+	m.shutdownAt = m.clock.Now()
+	m.regMu.Unlock()
+	defer m.writeShutdownReport()
+
+	m.emit(EventShutdown, "", nil)
+
+	m.runPhase(ctx, PhaseQuiesce, m.deregister)
+	m.runPhase(ctx, PhaseStop, m.stopUnits)
+	m.runPhase(ctx, PhaseFinalize, m.finalize)
+	m.summarize()
+}
+
+// stopUnits sends the stop event to all units that are still running and
+// waits for all of them to quit. A second shutdown signal received while
+// waiting, or the end of the phase budget, forces the shutdown: the
+// remaining units are abandoned.
+func (m *Manager) stopUnits(ctx context.Context) {
+	m.mu.Lock()
+	m.shutdownCtx = ctx
+	m.mu.Unlock()
+
+	m.stopStarting()
+
+	// Pre-stop hooks delay the stop event of their unit, post-stop hooks
+	// run once it is drained
+	var hooks sync.WaitGroup
+	stop := func(w *WorkUnitManager) {
+		m.unitLogf(w.name, "shutting down <%s>\n", w)
+		if len(w.preStop) == 0 || m.ShutdownMode() == Immediate {
+			m.stopUnit(w)
+			return
+		}
+		go func() {
+			m.runStopHooks(ctx, w, "pre-stop", w.preStop)
+			if !w.done.Load() {
+				m.stopUnit(w)
 			}
+		}()
+	}
 
-			// All workers have shutdown
-			log.Println("All workers have shutdown, shutting down manager ...")
+	// send shutdown event to all worker units, units with running dependents
+	// once their dependents are done. A rollback stops them one at a time in
+	// reverse order instead.
+	pending := 0
+	var rollback, held []*WorkUnitManager
+	m.startMu.Lock()
+	units := m.dependencyOrder(m.order)
+	dependents := m.dependents(units)
+	for _, w := range units {
+		if !w.started.Load() {
+			continue
+		}
+		w.awaited = true
+		pending++
 
-			m.Quit <- true
+		if w.done.Load() || w.Stopping() {
+			continue
+		}
+		if m.rollingBack.Load() {
+			rollback = append([]*WorkUnitManager{w}, rollback...)
+			continue
+		}
+		if m.ShutdownMode() != Immediate && unitsRunning(dependents[w]) {
+			held = append(held, w)
+			continue
+		}
+		stop(w)
+	}
+	m.startMu.Unlock()
+	rollback = m.rollbackNext(rollback)
 
-		case p := <-m.panic:
+	if m.ShutdownMode() == Immediate {
+		m.logf("Immediate shutdown, not waiting for units ...\n")
+		return
+	}
 
-			for name, w := range m.workers {
-				if w.isPaniced {
-					log.Printf("Panicing for <%s>: %s", name, p)
+	// Wait for all units to quit, recording the dependent which released
+	// each held unit for the shutdown chain
+	drained := make(map[*WorkUnitManager]int)
+	releasedBy := make(map[*WorkUnitManager]*WorkUnitManager)
+	var last *WorkUnitManager
+	for pending > 0 {
+		select {
+		case <-m.doneC:
+			for _, w := range m.takeDone() {
+				if !w.awaited {
+					continue // Removed before the shutdown
+				}
+				w.drained = true
+				drained[w] = len(drained) + 1
+				last = w
+				pending--
+				m.unitLogf(w.name, "<%s> down", w)
+				m.emitEvent(Event{
+					Kind:    EventUnitDone,
+					Unit:    w.name,
+					Latency: m.unitStopLatency(w),
+					TraceID: m.unitTrace(w),
+				})
+				if len(w.postStop) > 0 {
+					hooks.Add(1)
+					go func(w *WorkUnitManager) {
+						defer hooks.Done()
+						m.runStopHooks(ctx, w, "post-stop", w.postStop)
+					}(w)
 				}
 			}
-
-			for name, w := range m.workers {
-				log.Printf("shuting down <%s>\n", name)
-				if !w.isPaniced {
-					w.stop <- true
+			if len(rollback) > 0 && rollback[0].done.Load() {
+				rollback = m.rollbackNext(rollback[1:])
+			}
+			held = slices.DeleteFunc(held, func(w *WorkUnitManager) bool {
+				if unitsRunning(dependents[w]) {
+					return false
 				}
+				releasedBy[w] = lastDrained(dependents[w], drained)
+				stop(w)
+				return true
+			})
+
+		case <-m.panicC:
+			m.handlePanics()
+
+		case sig := <-m.signalIn:
+			m.dispatchSignal(sig)
+
+			if _, ok := m.signalMode(sig); !ok {
+				break
 			}
 
-			// Wait for all units to quit
-			for name, w := range m.workers {
-				<-w.workerQuit
-				log.Printf("<%s> down", name)
+			m.logf("second shutting event received, forcing shutdown ...\n")
+			m.mode.Store(int32(Immediate))
+			abandoned := m.abandon()
+			if len(abandoned) > 0 {
+				m.warnf("", "abandoned units: %s\n", strings.Join(abandoned, ", "))
 			}
+			if m.forceExit {
+				m.logf("Exiting with code %d\n", m.forceExitCode)
+				exit(m.forceExitCode)
+			}
+			return
+
+		case <-ctx.Done():
+			m.warnf("", "shutdown timeout exceeded, forcing shutdown ...\n")
+			m.abandon()
+			return
+		}
+	}
+
+	m.waitStopHooks(ctx, &hooks)
 
-			// All workers have shutdown
-			log.Println("All workers have shutdown, shutting down manager ...")
+	// All workers have shutdown
+	m.logf("All workers have shutdown, shutting down manager ...\n")
+	m.recordShutdownChain(last, releasedBy)
+}
+
+// unitDone queues a unit which called Done and wakes up the manager.
+func (m *Manager) unitDone(w *WorkUnitManager) {
+	m.doneMu.Lock()
+	m.doneQueue = append(m.doneQueue, w)
+	m.doneMu.Unlock()
+
+	select {
+	case m.doneC <- struct{}{}:
+	default:
+	}
+}
+
+// takeDone returns the units which called Done since the last call. The
+// returned slice is only valid until the next call.
+func (m *Manager) takeDone() []*WorkUnitManager {
+	m.doneMu.Lock()
+	done := m.doneQueue
+	m.doneQueue = m.doneSpare[:0]
+	m.doneMu.Unlock()
+
+	m.doneSpare = done
+	return done
+}
+
+// Stop triggers a graceful shutdown of the manager, as if a shutdown signal
+// was received. It does not wait for the shutdown to complete.
+func (m *Manager) Stop() {
+	m.stopOnce.Do(func() { close(m.stopC) })
+}
+
+// Shutdown triggers a graceful shutdown of the manager, as Stop does, and
+// waits for the manager to quit. The deadline and cancellation of ctx bound
+// the shutdown, on top of the shutdown timeout: units still running when ctx
+// is done are abandoned. It returns the shutdown cause, see Err, joined with
+// the error of ctx if it is done before the manager quit.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	if m.stopCtx == nil {
+		m.stopCtx = ctx
+	}
+	m.mu.Unlock()
 
-			m.Quit <- true
+	m.Stop()
 
+	select {
+	case <-m.quitC:
+		return m.Err()
+	case <-ctx.Done():
+		return errors.Join(ctx.Err(), m.Err())
+	}
+}
+
+// abandon gives up on waiting for the pending units and returns their names.
+func (m *Manager) abandon() []string {
+	m.regMu.RLock()
+	units := m.order
+	m.regMu.RUnlock()
+
+	var abandoned []string
+	for _, w := range units {
+		if !w.awaited || w.drained {
+			continue
+		}
+		m.warnf(w.name, "abandoning <%s>\n", w)
+		m.clockOut(w, true)
+		m.emitUnit(EventUnitAbandoned, w, nil)
+		abandoned = append(abandoned, w.name)
+	}
+	m.addErr(ErrForcedShutdown)
+	return abandoned
+}
+
+// newShutdownContext creates the context handed to units during shutdown. Its
+// deadline is the end of the shutdown timeout or of the context given to
+// Shutdown, if any.
+func (m *Manager) newShutdownContext() (context.Context, context.CancelFunc) {
+	var ctx context.Context
+	var cancel context.CancelFunc
+
+	if timeout := m.currentShutdownTimeout(); timeout > 0 {
+		ctx, cancel = context.WithTimeout(m.baseCtx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(m.baseCtx)
+	}
+
+	m.mu.Lock()
+	stopCtx := m.stopCtx
+	m.mu.Unlock()
+
+	// Bound the shutdown by the context given to Shutdown
+	if stopCtx != nil {
+		timeoutCancel := cancel
+		if deadline, ok := stopCtx.Deadline(); ok {
+			ctx, cancel = context.WithDeadline(ctx, deadline)
+		} else {
+			ctx, cancel = context.WithCancel(ctx)
 		}
+		stop := context.AfterFunc(stopCtx, cancel)
+		shutdownCancel := cancel
+		cancel = func() {
+			stop()
+			shutdownCancel()
+			timeoutCancel()
+		}
+	}
+
+	m.mu.Lock()
+	m.shutdownCtx = ctx
+	m.mu.Unlock()
+
+	return ctx, cancel
+}
+
+// ShutdownContext returns the context of the ongoing shutdown. Its deadline
+// is the end of the stop phase budget, see WithShutdownTimeout and
+// WithPhaseTimeout. Before the
+// shutdown begins it returns a background context.
+func (m *Manager) ShutdownContext() context.Context {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.shutdownCtx == nil {
+		return m.baseCtx
 	}
+	return m.shutdownCtx
 }
 
-func (m *Manager) ShutdownOn(sig ...os.Signal) {
+// Err returns the cause of the manager shutdown, nil after a clean shutdown.
+// It is valid once the Quit channel has been notified, see Wait.
+func (m *Manager) Err() error {
+	m.errMu.Lock()
+	defer m.errMu.Unlock()
+
+	return m.err
+}
 
-	for _, s := range sig {
-		log.Printf("Registering shutdown signal: %s\n", s)
-		signal.Notify(m.signalIn, s)
+// addErr joins err to the shutdown cause.
+func (m *Manager) addErr(err error) {
+	m.errMu.Lock()
+	m.err = errors.Join(m.err, err)
+	m.errMu.Unlock()
+}
+
+// ExitCode returns the process exit code matching the shutdown cause
+// returned by Err. See ExitCode and WithExitCode.
+func (m *Manager) ExitCode() int {
+	if code, ok := m.reasonCodes[m.StopReason()]; ok {
+		return code
 	}
+	return lookupExitCode(m.exitCodes, m.Err())
+}
 
-	m.shutdownSigs = append(m.shutdownSigs, sig...)
+// ShutdownMode returns how the manager is being shut down.
+func (m *Manager) ShutdownMode() ShutdownMode {
+	return ShutdownMode(m.mode.Load())
+}
+
+// ShutdownMode defines how units are stopped.
+type ShutdownMode int
+
+const (
+	// Graceful shutdown waits for all units to be done.
+	Graceful ShutdownMode = iota
+
+	// Immediate shutdown skips waiting for units, which should only do a
+	// minimal cleanup.
+	Immediate
+)
+
+func (s ShutdownMode) String() string {
+	switch s {
+	case Graceful:
+		return "graceful"
+	case Immediate:
+		return "immediate"
+	}
+	return fmt.Sprintf("ShutdownMode(%d)", int(s))
 }
 
 type IDGenerator func(string) int
 
 func genID() IDGenerator {
+	var mu sync.Mutex
 	ids := make(map[string]int)
 
 	return func(unit string) int {
+		mu.Lock()
+		defer mu.Unlock()
+
 		ret := ids[unit]
 		ids[unit]++
 		return ret
 	}
 }
 
-func (m *Manager) AddUnit(unit WorkUnit, name string) {
+// AddUnit registers a unit with the manager. The unit is started by Run, or
+// right away once the manager is running. Invalid units added at runtime are
+// logged and not added, as Run already validated the configuration.
+func (m *Manager) AddUnit(unit WorkUnit, name string, opts ...UnitOption) {
+	_ = m.tryAddUnit(unit, name, opts...)
+}
 
+// tryAddUnit implements AddUnit and returns the error of a unit which was
+// not added. A change queued while the topology is frozen is not an error.
+func (m *Manager) tryAddUnit(unit WorkUnit, name string, opts ...UnitOption) error {
+	if unit == nil {
+		err := fmt.Errorf("nil unit %q", name)
+		m.invalid(err)
+		return err
+	}
+	w := m.newUnit(unit, name, opts...)
+	if blocked, err := m.blockChange("add", w.name, func() { _ = m.registerUnit(w) }); blocked {
+		return err
+	}
+	return m.registerUnit(w)
+}
+
+// registerUnit registers the unit, and starts it if the manager is running.
+// Invalid units are only reported by Validate until the manager runs.
+func (m *Manager) registerUnit(w *WorkUnitManager) error {
+	m.startMu.Lock()
+	defer m.startMu.Unlock()
+
+	if !m.running {
+		m.addUnit(w)
+		return nil
+	}
+
+	select {
+	case <-m.startStop:
+		err := fmt.Errorf("can't add <%s>: manager is shutting down", w)
+		m.warnf(w.name, "%s\n", err)
+		return err
+	default:
+	}
+
+	m.regMu.RLock()
+	_, dup := m.workers[w.name]
+	_, depErr := m.unitDeps(w)
+	m.regMu.RUnlock()
+	if dup {
+		w.invalid(fmt.Errorf("duplicate unit name <%s>", w.name))
+	}
+	if depErr != nil {
+		w.invalid(depErr)
+	}
+	if err := m.checkResources([]*WorkUnitManager{w}); err != nil {
+		w.invalid(err)
+	}
+	if len(w.configErrs) > 0 {
+		err := fmt.Errorf("can't add <%s>: %w", w, errors.Join(w.configErrs...))
+		m.warnf(w.name, "%s\n", err)
+		return err
+	}
+
+	w.settled.Store(true) // The startup completion only waits for the initial units
+	m.addUnit(w)
+
+	if m.flags != nil && !m.flags.Enabled(w.Info()) {
+		m.unitLogf(w.name, "Skipping disabled <%s>\n", w)
+		m.setState(w, Disabled, nil)
+		return nil
+	}
+	if len(w.after) > 0 {
+		go m.protect("dependencies", func() { m.startAfterDeps(w) })
+		return nil
+	}
+	m.startUnit(w)
+	return nil
+}
+
+// RemoveUnit asks the named unit to stop and unregisters it once it is done,
+// e.g. to scale workers down. A unit which was not started is unregistered
+// right away. The removed unit is not restarted nor recycled.
+func (m *Manager) RemoveUnit(name string) error {
+	blocked, err := m.blockChange("remove", name, func() {
+		if err := m.RemoveUnit(name); err != nil {
+			m.warnf(name, "%s\n", err)
+		}
+	})
+	if blocked {
+		return err
+	}
+
+	m.startMu.Lock()
+	defer m.startMu.Unlock()
+
+	m.regMu.RLock()
+	w, ok := m.workers[name]
+	m.regMu.RUnlock()
+	if !ok {
+		return fmt.Errorf("can't remove <%s>: unknown unit", name)
+	}
+	if !w.removed.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	m.unitLogf(w.name, "Removing <%s>\n", w)
+	if !w.started.Load() || w.done.Load() {
+		m.unregister(w)
+		return nil
+	}
+	m.stopUnit(w)
+	return nil
+}
+
+// unregister removes the unit from the registry. order is copied so the
+// units iterated by others are unaffected. startMu must be held.
+func (m *Manager) unregister(w *WorkUnitManager) {
+	m.regMu.Lock()
+	defer m.regMu.Unlock()
+
+	if m.workers[w.name] == w {
+		delete(m.workers, w.name)
+	}
+	order := make([]*WorkUnitManager, 0, len(m.order))
+	for _, u := range m.order {
+		if u != w {
+			order = append(order, u)
+		}
+	}
+	m.order = order
+	m.touch()
+}
+
+// newUnit creates the manager of the unit and names it.
+func (m *Manager) newUnit(unit WorkUnit, name string, opts ...UnitOption) *WorkUnitManager {
+	return m.newUnitID(unit, name, -1, opts...)
+}
+
+// newUnitID creates the manager of the unit, numbered id unless negative.
+func (m *Manager) newUnitID(unit WorkUnit, name string, id int, opts ...UnitOption) *WorkUnitManager {
 	workUnitManager := &WorkUnitManager{
-		workerQuit: make(chan bool, 1),
-		stop:       make(chan bool, 1),
-		unit:       unit,
-		panic:      m.panic,
+		base:    name,
+		opts:    opts,
+		stop:    make(chan bool, 1),
+		unit:    unit,
+		manager: m,
+
+		restartBackoff:    DefaultRestartBackoff,
+		restartMaxBackoff: DefaultMaxRestartBackoff,
+		restartIntensity:  DefaultRestartIntensity,
+		restartPeriod:     DefaultRestartPeriod,
+	}
+	workUnitManager.ctx, workUnitManager.cancel = context.WithCancel(m.baseCtx)
+
+	for _, opt := range m.unitDefaults {
+		opt(workUnitManager)
+	}
+	for _, opt := range opts {
+		opt(workUnitManager)
 	}
 
-	unitType := reflect.TypeOf(unit)
-	unitClass := strings.Split(unitType.String(), ".")[1]
-	unitName := fmt.Sprintf("%s[%s", name, unitClass)
-	unitID := idGenerator(unitName)
+	if d, ok := unit.(Describer); ok {
+		workUnitManager.description = d.Describe()
+	}
+
+	class := unitClass(unit)
+	unitName := fmt.Sprintf("%s[%s", name, class)
+	unitID := id
+	if unitID < 0 {
+		unitID = m.unitID(unitName)
+	}
 	unitName = fmt.Sprintf("%s#%d]", unitName, unitID)
 
-	log.Println("Adding unit ", unitName)
+	// Explicit names are used as is
+	if workUnitManager.name != "" {
+		unitName = workUnitManager.name
+	}
+
+	workUnitManager.lineage = fmt.Sprintf("%s[%s]", name, class)
+	if workUnitManager.name != "" {
+		workUnitManager.lineage = workUnitManager.name
+	}
+
+	workUnitManager.name = unitName
+	workUnitManager.info.Name = unitName
+	workUnitManager.info.ID = unitID
+	workUnitManager.info.Type = class
 
-	m.workers[unitName] = workUnitManager
+	return workUnitManager
 }
 
-func NewManager() *Manager {
-	return &Manager{
-		signalIn: make(chan os.Signal, 1),
-		Quit:     make(chan bool, 1),
-		workers:  make(map[string]*WorkUnitManager),
-		panic:    make(chan error, 1),
+// addUnit adds the unit to the registry.
+func (m *Manager) addUnit(w *WorkUnitManager) {
+	m.unitLogf(w.name, "Adding unit %s\n", w)
+
+	m.regMu.Lock()
+	m.inheritLineage(w)
+	m.workers[w.name] = w
+	m.order = append(m.order, w)
+	m.touch()
+	m.regMu.Unlock()
+}
+
+// replaceUnit registers the unit in place of old, its previous instance, so
+// the registry doesn't grow with restarts. order is copied so the units
+// iterated by others are unaffected.
+func (m *Manager) replaceUnit(old, w *WorkUnitManager) {
+	m.unitLogf(w.name, "Adding unit %s in place of %s\n", w, old)
+
+	m.regMu.Lock()
+	defer m.regMu.Unlock()
+
+	m.inheritLineage(w)
+	old.replacedBy = w
+	if m.workers[old.name] == old {
+		delete(m.workers, old.name)
+	}
+	m.workers[w.name] = w
+
+	order := make([]*WorkUnitManager, 0, len(m.order)+1)
+	replaced := false
+	for _, u := range m.order {
+		if u == old {
+			u, replaced = w, true
+		}
+		order = append(order, u)
+	}
+	if !replaced {
+		order = append(order, w)
+	}
+	m.order = order
+	m.touch()
+}
+
+// inheritLineage gives the unit the state shared by the instances of its
+// lineage. regMu must be held.
+func (m *Manager) inheritLineage(w *WorkUnitManager) {
+	w.stopLatencies = m.stopLatencies[w.lineage]
+	if w.stopLatencies == nil {
+		w.stopLatencies = &latencyHistogram{}
+		m.stopLatencies[w.lineage] = w.stopLatencies
+	}
+	w.panics = m.panicHistory[w.lineage]
+	if w.panics == nil {
+		w.panics = &panicHistory{}
+		m.panicHistory[w.lineage] = w.panics
+	}
+}
+
+// unitClass returns the name used to identify the kind of unit. Units
+// implementing Namer are named after the value returned by Name().
+func unitClass(unit WorkUnit) string {
+	if n, ok := unit.(Namer); ok {
+		if name := n.Name(); name != "" {
+			return name
+		}
+	}
+
+	t := reflect.TypeOf(unit)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	// Anonymous types such as struct literals or func types
+	if t.Name() == "" {
+		return t.Kind().String()
 	}
+
+	// Generic instantiations carry the qualified type arguments
+	return pkgQualifier.ReplaceAllString(t.Name(), "")
 }
 
-// Test if signal is in array
-func in(arr []os.Signal, sig os.Signal) bool {
-	for _, s := range arr {
-		if s == sig {
-			return true
+// pkgQualifier matches the package path qualifying a type name, as found in
+// the type arguments of generic types, e.g. example.com/pkg.Type.
+var pkgQualifier = regexp.MustCompile(`(?:[\w\-.~]*/)*[\w\-]+\.`)
+
+func NewManager(opts ...Option) *Manager {
+	m := newManager()
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	// The profile of the environment is overridden by the options, as a
+	// profile given with WithProfile before them
+	if profile, ok := m.envProfile(); ok {
+		m = newManager()
+		WithProfile(profile)(m)
+		for _, opt := range opts {
+			opt(m)
 		}
 	}
-	return false
+
+	m.applyEnv()
+	m.initBaggage()
+	m.recycleSem = make(chan struct{}, m.maxUnavailable)
+	m.events.replayEvent = m.replayEvent
+
+	return m
+}
+
+// newManager returns a manager with the default settings.
+func newManager() *Manager {
+	return &Manager{
+		signalIn:  make(chan os.Signal, 1),
+		Quit:      make(chan bool, 1),
+		quitC:     make(chan struct{}),
+		workers:   make(map[string]*WorkUnitManager),
+		doneC:     make(chan struct{}, 1),
+		stopC:     make(chan struct{}),
+		abortC:    make(chan struct{}),
+		logger:    log.Default(),
+		verbosity: logNormal,
+
+		sampleInterval: DefaultSampleInterval,
+		hookTimeout:    DefaultHookTimeout,
+		reloadTimeout:  DefaultReloadTimeout,
+		stopLatencies:  make(map[string]*latencyHistogram),
+		starts:         make(map[string]int),
+		restartTimes:   make(map[string][]time.Time),
+		panicHistory:   make(map[string]*panicHistory),
+		startStop:      make(chan struct{}),
+		startDone:      make(chan struct{}),
+		reportDone:     make(chan struct{}),
+		panicC:         make(chan struct{}, 1),
+		allReady:       make(chan struct{}),
+		drainC:         make(chan struct{}),
+		regDone:        make(chan struct{}),
+		maxUnavailable: DefaultMaxUnavailable,
+		rand:           rand.New(rand.NewSource(time.Now().UnixNano())),
+		clock:          systemClock{},
+		exitCodes:      append([]exitCode(nil), defaultExitCodes...),
+	}
 }
@@ -1,8 +1,13 @@
 package gum
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
 	"log"
 	"os"
+	"strings"
 	"syscall"
 	"testing"
 	"time"
@@ -44,8 +49,8 @@ func NewWorker() *Worker {
 }
 
 func DoRun(pid chan int,
-				quit chan<- bool,
-				signals ...os.Signal) {
+	quit chan<- bool,
+	signals ...os.Signal) {
 
 	pid <- os.Getpid()
 
@@ -71,11 +76,9 @@ func DoRun(pid chan int,
 
 }
 
-
-
 func TestRunMain(t *testing.T) {
 	signals := map[string]os.Signal{
-		"interrupt":  os.Interrupt,
+		"interrupt": os.Interrupt,
 	}
 	mainPid := make(chan int, 1)
 	quit := make(chan bool)
@@ -93,3 +96,448 @@ func TestRunMain(t *testing.T) {
 		<-quit
 	}
 }
+
+type namedWorker struct{ Worker }
+
+func (w *namedWorker) Name() string     { return "poller" }
+func (w *namedWorker) Describe() string { return "polls the upstream api" }
+
+func TestAddUnitNamer(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&namedWorker{}, "work")
+
+	var w *WorkUnitManager
+	for name, unit := range manager.workers {
+		if strings.HasPrefix(name, "work[poller#") {
+			w = unit
+		}
+	}
+	if w == nil {
+		t.Fatalf("expected unit to be named after Name(), got %v", manager.workers)
+	}
+	if w.description != "polls the upstream api" {
+		t.Errorf("unexpected description: %q", w.description)
+	}
+}
+
+type genericWorker[T any] struct{ Worker }
+
+type funcWorker func(UnitManager)
+
+func (f funcWorker) Run(um UnitManager) { f(um) }
+
+func TestUnitClass(t *testing.T) {
+	tests := []struct {
+		unit WorkUnit
+		want string
+	}{
+		{&Worker{}, "Worker"},
+		{&genericWorker[int]{}, "genericWorker[int]"},
+		{&genericWorker[Worker]{}, "genericWorker[Worker]"},
+		{&genericWorker[map[string]*Worker]{}, "genericWorker[map[string]*Worker]"},
+		{&struct{ Worker }{}, "struct"},
+		{funcWorker(func(um UnitManager) { um.Done() }), "funcWorker"},
+	}
+
+	for _, tt := range tests {
+		if got := unitClass(tt.unit); got != tt.want {
+			t.Errorf("expected class %q, got %q", tt.want, got)
+		}
+	}
+}
+
+func TestAddUnitWithName(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&Worker{}, "", WithName("api"))
+	manager.AddUnit(&Worker{}, "", WithName("api"))
+
+	if name := manager.order[0].Info().Name; name != "api" {
+		t.Fatalf("expected explicit name, got %q", name)
+	}
+	if err := manager.Validate(); err == nil {
+		t.Fatal("expected duplicate explicit names to be invalid")
+	}
+}
+
+// initWorker is still initializing when the manager stops
+type initWorker struct {
+	init     chan struct{}
+	stopping chan bool
+}
+
+func (w *initWorker) Run(um UnitManager) {
+	<-w.init
+	w.stopping <- um.Stopping()
+	<-um.ShouldStop()
+	um.Done()
+}
+
+func TestStoppingLatched(t *testing.T) {
+	manager := NewManager()
+	w := &initWorker{make(chan struct{}), make(chan bool, 1)}
+	manager.AddUnit(w, "")
+
+	go manager.Run()
+	manager.Stop()
+
+	// Wait for the stop request before finishing initialization
+	for !manager.order[0].Stopping() {
+		time.Sleep(time.Millisecond)
+	}
+	close(w.init)
+
+	if !<-w.stopping {
+		t.Fatal("expected the unit to see the stop request")
+	}
+
+	select {
+	case <-manager.Quit:
+	case <-time.After(time.Second):
+		t.Fatal("the unit missed the stop request")
+	}
+}
+
+func TestRequestStopOnce(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&Worker{}, "")
+	w := manager.order[0]
+
+	if !w.requestStop() {
+		t.Fatal("expected first stop request to be delivered")
+	}
+	if w.requestStop() {
+		t.Fatal("expected second stop request to be ignored")
+	}
+	if len(w.ShouldStop()) != 1 {
+		t.Fatalf("expected a single stop value, got %d", len(w.ShouldStop()))
+	}
+}
+
+// stuckWorker never reports itself as done
+type stuckWorker struct{}
+
+func (w *stuckWorker) Run(um UnitManager) {
+	<-um.ShouldStop()
+}
+
+func TestSecondSignalForcesShutdown(t *testing.T) {
+	manager := NewManager()
+	manager.ShutdownOn(os.Interrupt)
+	manager.AddUnit(&stuckWorker{}, "")
+	manager.AddUnit(NewWorker(), "")
+
+	go manager.Run()
+
+	manager.signalIn <- os.Interrupt
+	manager.signalIn <- os.Interrupt
+
+	select {
+	case <-manager.Quit:
+	case <-time.After(time.Second):
+		t.Fatal("manager did not quit after second signal")
+	}
+
+	if !errors.Is(manager.Err(), ErrForcedShutdown) {
+		t.Fatalf("expected forced shutdown, got %v", manager.Err())
+	}
+}
+
+func TestSecondSignalForceExit(t *testing.T) {
+	exited := make(chan int, 1)
+	exit = func(c int) { exited <- c }
+	defer func() { exit = osExit }()
+
+	var logs bytes.Buffer
+	manager := NewManager(WithForceExit(130), WithLogger(log.New(&logs, "", 0)))
+	manager.ShutdownOn(os.Interrupt)
+	manager.AddUnit(&stuckWorker{}, "", WithName("stuck"))
+	manager.AddUnit(&readyWorker{}, "")
+
+	sub := manager.Subscribe()
+	go manager.Run()
+	waitState(t, manager, 0, Running)
+
+	manager.signalIn <- os.Interrupt
+	waitEvent(t, sub, EventUnitStopping)
+	manager.signalIn <- os.Interrupt
+
+	select {
+	case code := <-exited:
+		if code != 130 {
+			t.Fatalf("expected exit code 130, got %d", code)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("manager did not exit after second signal")
+	}
+	<-manager.Quit
+
+	if !strings.Contains(logs.String(), "abandoned units: stuck") {
+		t.Fatalf("expected the abandoned units to be logged:\n%s", logs.String())
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&stopWorker{}, "")
+	go manager.Run()
+	waitState(t, manager, 0, Running)
+
+	if err := manager.Shutdown(context.Background()); err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
+	if state := manager.Snapshot().Units[0].State; state != Stopped {
+		t.Fatalf("expected the unit to be stopped, got %s", state)
+	}
+	<-manager.Quit
+}
+
+func TestShutdownDeadline(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&stuckWorker{}, "")
+	go manager.Run()
+	waitState(t, manager, 0, Running)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := manager.Shutdown(ctx)
+	<-manager.Quit
+	deadline, _ := manager.ShutdownContext().Deadline()
+
+	if !errors.Is(manager.Err(), ErrForcedShutdown) {
+		t.Fatalf("expected the stuck unit to be abandoned, got %v", manager.Err())
+	}
+	if err == nil {
+		t.Fatal("expected a shutdown error")
+	}
+	if ctxDeadline, _ := ctx.Deadline(); !deadline.Equal(ctxDeadline) {
+		t.Fatalf("expected the shutdown context to carry the deadline %s, got %s", ctxDeadline, deadline)
+	}
+}
+
+func TestAddRemoveUnitRunning(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&stopWorker{}, "")
+	go manager.Run()
+	waitState(t, manager, 0, Running)
+
+	for _, name := range []string{"worker-1", "worker-2"} {
+		manager.AddUnit(&readyWorker{}, "", WithName(name))
+	}
+	waitState(t, manager, 1, Running)
+	waitState(t, manager, 2, Running)
+
+	if err := manager.RemoveUnit("worker-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.RemoveUnit("unknown"); err == nil {
+		t.Fatal("expected an error removing an unknown unit")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(manager.Snapshot().Units) != 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the unit to be unregistered, got %+v", manager.Snapshot().Units)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if name := manager.Snapshot().Units[1].Name; name != "worker-2" {
+		t.Fatalf("expected worker-2 to be kept, got %s", name)
+	}
+
+	manager.Stop()
+	<-manager.Quit
+	if manager.Err() != nil {
+		t.Fatalf("unexpected shutdown cause: %v", manager.Err())
+	}
+}
+
+// modeWorker reports the shutdown mode it was stopped with
+type modeWorker struct {
+	mode chan ShutdownMode
+}
+
+func (w *modeWorker) Run(um UnitManager) {
+	<-um.ShouldStop()
+	w.mode <- um.ShutdownMode()
+}
+
+func TestImmediateShutdown(t *testing.T) {
+	manager := NewManager()
+	manager.ShutdownOn(os.Interrupt)
+	manager.ImmediateShutdownOn(syscall.SIGABRT)
+
+	worker := &modeWorker{mode: make(chan ShutdownMode, 1)}
+	manager.AddUnit(worker, "")
+
+	go manager.Run()
+	manager.signalIn <- syscall.SIGABRT
+
+	select {
+	case <-manager.Quit:
+	case <-time.After(time.Second):
+		t.Fatal("manager waited for units on immediate shutdown")
+	}
+
+	if mode := <-worker.mode; mode != Immediate {
+		t.Fatalf("expected unit to be stopped with immediate mode, got %s", mode)
+	}
+	if manager.Err() != nil {
+		t.Fatalf("unexpected shutdown cause: %v", manager.Err())
+	}
+}
+
+// deadlineWorker reports the remaining shutdown budget
+type deadlineWorker struct {
+	remaining chan time.Duration
+}
+
+func (w *deadlineWorker) Run(um UnitManager) {
+	<-um.ShouldStop()
+	deadline, ok := um.ShutdownContext().Deadline()
+	if !ok {
+		w.remaining <- 0
+	} else {
+		w.remaining <- time.Until(deadline)
+	}
+	um.Done()
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	manager := NewManager(WithShutdownTimeout(100 * time.Millisecond))
+	manager.ShutdownOn(os.Interrupt)
+
+	worker := &deadlineWorker{remaining: make(chan time.Duration, 1)}
+	manager.AddUnit(worker, "")
+	manager.AddUnit(&stuckWorker{}, "")
+
+	go manager.Run()
+	manager.signalIn <- os.Interrupt
+
+	select {
+	case <-manager.Quit:
+	case <-time.After(time.Second):
+		t.Fatal("manager did not quit after shutdown timeout")
+	}
+
+	if remaining := <-worker.remaining; remaining <= 0 || remaining > 100*time.Millisecond {
+		t.Fatalf("unexpected remaining shutdown budget: %s", remaining)
+	}
+	if !errors.Is(manager.Err(), ErrForcedShutdown) {
+		t.Fatalf("expected forced shutdown, got %v", manager.Err())
+	}
+}
+
+// stopWorker is the minimal unit: it is done as soon as it is stopped
+type stopWorker struct{}
+
+func (w *stopWorker) Run(um UnitManager) {
+	<-um.ShouldStop()
+	um.Done()
+}
+
+// Start and shutdown 10k units
+func BenchmarkShutdown(b *testing.B) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		manager := NewManager()
+		manager.shutdownSigs = []os.Signal{os.Interrupt}
+		for j := 0; j < 10000; j++ {
+			manager.AddUnit(&stopWorker{}, "bench")
+		}
+		b.StartTimer()
+
+		go manager.Run()
+		manager.signalIn <- os.Interrupt
+		<-manager.Quit
+	}
+}
+
+// Done is on the hot path of every unit and must not allocate
+func BenchmarkDone(b *testing.B) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	manager := NewManager()
+	for j := 0; j < 10000; j++ {
+		manager.AddUnit(&stopWorker{}, "bench")
+	}
+	manager.doneQueue = make([]*WorkUnitManager, 0, len(manager.workers))
+	manager.doneSpare = make([]*WorkUnitManager, 0, len(manager.workers))
+
+	units := make([]*WorkUnitManager, 0, len(manager.workers))
+	for _, w := range manager.workers {
+		units = append(units, w)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		w := units[i%len(units)]
+		w.Done()
+
+		if len(manager.doneQueue) == cap(manager.doneQueue) {
+			for _, w := range manager.takeDone() {
+				w.done.Store(false)
+			}
+		}
+	}
+}
+
+func TestDoneAllocs(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&stopWorker{}, "")
+	manager.doneQueue = make([]*WorkUnitManager, 0, 1)
+	manager.doneSpare = make([]*WorkUnitManager, 0, 1)
+
+	var w *WorkUnitManager
+	for _, unit := range manager.workers {
+		w = unit
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		w.Done()
+		manager.takeDone()
+		w.done.Store(false)
+	})
+	if allocs != 0 {
+		t.Fatalf("expected Done to be allocation free, got %v allocs", allocs)
+	}
+}
+
+// ctxWorker blocks on its context as a blocking call would
+type ctxWorker struct {
+	baggage chan map[string]string
+}
+
+func (w *ctxWorker) Run(um UnitManager) {
+	ctx := um.Context()
+	<-ctx.Done()
+	w.baggage <- BaggageFromContext(ctx)
+	um.Done()
+}
+
+func TestUnitContext(t *testing.T) {
+	manager := NewManager(WithBaggage(map[string]string{"run_id": "42"}))
+	worker := &ctxWorker{baggage: make(chan map[string]string, 1)}
+	manager.AddUnit(worker, "")
+
+	go manager.Run()
+	waitState(t, manager, 0, Running)
+	manager.Stop()
+
+	select {
+	case <-manager.Quit:
+	case <-time.After(time.Second):
+		t.Fatal("unit context not cancelled on stop")
+	}
+	if baggage := <-worker.baggage; baggage["run_id"] != "42" {
+		t.Fatalf("expected baggage in the unit context, got %v", baggage)
+	}
+}
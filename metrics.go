@@ -0,0 +1,201 @@
+package gum
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Content types of the metrics exposition formats.
+const (
+	MetricsContentType     = "text/plain; version=0.0.4; charset=utf-8"
+	OpenMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+)
+
+// WriteMetrics writes the state of the manager and of its units in the
+// Prometheus text exposition format, so minimal binaries can be scraped
+// without depending on a Prometheus client library.
+func (m *Manager) WriteMetrics(w io.Writer) error {
+	return writeMetrics(w, m.Snapshot(), false)
+}
+
+// WriteOpenMetrics writes the same metrics as WriteMetrics in the
+// OpenMetrics text format.
+func (m *Manager) WriteOpenMetrics(w io.Writer) error {
+	return writeMetrics(w, m.Snapshot(), true)
+}
+
+// metricsWriter writes metric families, keeping the first write error.
+type metricsWriter struct {
+	w           *bufio.Writer
+	openMetrics bool
+	err         error
+}
+
+func (mw *metricsWriter) printf(format string, args ...any) {
+	if mw.err == nil {
+		_, mw.err = fmt.Fprintf(mw.w, format, args...)
+	}
+}
+
+// family writes the metadata of a metric family. Counter samples are named
+// with the _total suffix, which OpenMetrics omits from the family name.
+func (mw *metricsWriter) family(name, typ, help string) {
+	if typ == "counter" && !mw.openMetrics {
+		name += "_total"
+	}
+	mw.printf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// sample writes a sample, labels are given as name/value pairs.
+func (mw *metricsWriter) sample(name string, value float64, labels ...string) {
+	var b strings.Builder
+	b.WriteString(name)
+	if len(labels) > 0 {
+		b.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(labels[i])
+			b.WriteString(`="`)
+			b.WriteString(labelEscaper.Replace(labels[i+1]))
+			b.WriteByte('"')
+		}
+		b.WriteByte('}')
+	}
+	mw.printf("%s %s\n", b.String(), strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// latestUnits returns the units of the snapshot, keeping only the latest
+// instance of units sharing the same name, e.g. swapped units with an
+// explicit name, so series are unique.
+func latestUnits(units []UnitStatus) []UnitStatus {
+	last := make(map[string]int, len(units))
+	for i, u := range units {
+		last[u.Name] = i
+	}
+
+	latest := make([]UnitStatus, 0, len(last))
+	for i, u := range units {
+		if last[u.Name] == i {
+			latest = append(latest, u)
+		}
+	}
+	return latest
+}
+
+func writeMetrics(w io.Writer, snap Snapshot, openMetrics bool) error {
+	mw := &metricsWriter{w: bufio.NewWriter(w), openMetrics: openMetrics}
+	snap.Units = latestUnits(snap.Units)
+
+	if b := snap.Build; b != nil {
+		mw.family("gum_build_info", "gauge", "Build metadata of the supervised program.")
+		mw.sample("gum_build_info", 1, "version", b.Version, "commit", b.Commit)
+	}
+
+	mw.family("gum_uptime_seconds", "gauge", "Uptime of the manager.")
+	mw.sample("gum_uptime_seconds", snap.Uptime.Seconds())
+
+	if snap.Startup != nil {
+		mw.family("gum_startup_seconds", "gauge", "Time the units took to be ready.")
+		mw.sample("gum_startup_seconds", snap.Startup.Duration.Seconds())
+	}
+
+	if len(snap.Shutdown) > 0 {
+		mw.family("gum_shutdown_phase_seconds", "gauge", "Time the shutdown phase took.")
+		for _, p := range snap.Shutdown {
+			mw.sample("gum_shutdown_phase_seconds", p.Duration.Seconds(), "phase", p.Phase.String())
+		}
+	}
+
+	if p := snap.Pressure; p != nil {
+		for _, g := range []struct {
+			name, help string
+			value      float64
+		}{
+			{"gum_runtime_cpu_ratio", "Fraction of the available CPU in use.", p.CPU},
+			{"gum_runtime_gc_pause_ratio", "Fraction of the CPU time spent in GC pauses.", p.GCPause},
+			{"gum_runtime_sched_latency_seconds", "99th percentile of the time goroutines spent runnable before running.", p.SchedLatency.Seconds()},
+			{"gum_runtime_goroutines", "Goroutines.", float64(p.Goroutines)},
+			{"gum_runtime_gomaxprocs", "GOMAXPROCS.", float64(p.GOMAXPROCS)},
+		} {
+			mw.family(g.name, "gauge", g.help)
+			mw.sample(g.name, g.value)
+		}
+	}
+
+	if len(snap.EventsDropped) > 0 {
+		mw.family("gum_events_dropped", "counter", "Events dropped because the subscriber's buffer was full.")
+		for _, p := range []OverflowPolicy{DropOldest, DropNewest} {
+			mw.sample("gum_events_dropped_total", float64(snap.EventsDropped[p.String()]), "policy", p.String())
+		}
+	}
+
+	running := 0
+	for _, u := range snap.Units {
+		if u.State == Running {
+			running++
+		}
+	}
+	mw.family("gum_units_running", "gauge", "Units running.")
+	mw.sample("gum_units_running", float64(running))
+
+	mw.family("gum_unit_state", "gauge", "Current state of the unit.")
+	for _, u := range snap.Units {
+		mw.sample("gum_unit_state", 1, "unit", u.Name, "state", u.State.String())
+	}
+
+	mw.family("gum_unit_ready", "gauge", "Whether the unit is ready.")
+	for _, u := range snap.Units {
+		mw.sample("gum_unit_ready", boolValue(u.Ready), "unit", u.Name)
+	}
+
+	mw.family("gum_unit_uptime_seconds", "gauge", "Time the unit has been running.")
+	for _, u := range snap.Units {
+		mw.sample("gum_unit_uptime_seconds", u.Uptime.Seconds(), "unit", u.Name)
+	}
+
+	mw.family("gum_unit_panics", "counter", "Panics of the unit.")
+	for _, u := range snap.Units {
+		mw.sample("gum_unit_panics_total", float64(u.Panics), "unit", u.Name)
+	}
+
+	mw.family("gum_unit_restarts", "counter", "Restarts of the unit.")
+	for _, u := range snap.Units {
+		mw.sample("gum_unit_restarts_total", float64(u.Restarts), "unit", u.Name)
+	}
+
+	mw.family("gum_unit_stop_latency_seconds", "summary", "Time the unit and the instances it replaced took to be done once asked to stop.")
+	for _, u := range snap.Units {
+		p := u.StopLatencies
+		for _, q := range []struct {
+			quantile string
+			d        time.Duration
+		}{{"0.5", p.P50}, {"0.9", p.P90}, {"0.99", p.P99}} {
+			mw.sample("gum_unit_stop_latency_seconds", q.d.Seconds(), "unit", u.Name, "quantile", q.quantile)
+		}
+		mw.sample("gum_unit_stop_latency_seconds_count", float64(p.Count), "unit", u.Name)
+	}
+
+	if openMetrics {
+		mw.printf("# EOF\n")
+	}
+
+	if mw.err != nil {
+		return mw.err
+	}
+	return mw.w.Flush()
+}
@@ -0,0 +1,135 @@
+package gum
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWriteMetrics(t *testing.T) {
+	manager := NewManager(WithBuildInfo(BuildInfo{Version: "v1.2.0", Commit: `ab"c`}))
+	manager.AddUnit(&readyWorker{}, "api", WithName("api"))
+
+	go manager.Run()
+	waitState(t, manager, 0, Running)
+	defer func() {
+		manager.Stop()
+		<-manager.Quit
+	}()
+
+	var buf bytes.Buffer
+	if err := manager.WriteMetrics(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		`gum_build_info{version="v1.2.0",commit="ab\"c"} 1`,
+		`# TYPE gum_unit_panics_total counter`,
+		`gum_unit_state{unit="api",state="running"} 1`,
+		`gum_unit_panics_total{unit="api"} 0`,
+		`gum_unit_restarts_total{unit="api"} 0`,
+		`gum_units_running 1`,
+		`gum_unit_stop_latency_seconds{unit="api",quantile="0.99"} 0`,
+		`gum_unit_stop_latency_seconds_count{unit="api"} 0`,
+	} {
+		if !strings.Contains(out, want+"\n") {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "# EOF") {
+		t.Error("unexpected OpenMetrics EOF in the Prometheus format")
+	}
+}
+
+func TestEventsDroppedMetrics(t *testing.T) {
+	manager := NewManager()
+	sub := manager.Subscribe(WithBufferSize(1), WithOverflowPolicy(DropNewest))
+	for i := 0; i < 3; i++ {
+		manager.emit(EventUnitStarted, "api", nil)
+	}
+	sub.Close()
+
+	var buf bytes.Buffer
+	if err := manager.WriteMetrics(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	// Closed subscriptions are still accounted for
+	for _, want := range []string{
+		`# TYPE gum_events_dropped_total counter`,
+		`gum_events_dropped_total{policy="drop_newest"} 2`,
+		`gum_events_dropped_total{policy="drop_oldest"} 0`,
+	} {
+		if !strings.Contains(out, want+"\n") {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestShutdownMetrics(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&readyWorker{}, "")
+
+	go manager.Run()
+	waitState(t, manager, 0, Running)
+	manager.Stop()
+	<-manager.Quit
+
+	var buf bytes.Buffer
+	if err := manager.WriteMetrics(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"gum_units_running 0\n",
+		"# TYPE gum_shutdown_phase_seconds gauge\n",
+		`gum_shutdown_phase_seconds{phase="stop"} `,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteOpenMetrics(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&stopWorker{}, "")
+
+	var buf bytes.Buffer
+	if err := manager.WriteOpenMetrics(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	if !strings.Contains(out, "# TYPE gum_unit_panics counter\n") {
+		t.Errorf("expected the counter family without suffix:\n%s", out)
+	}
+	if !strings.HasSuffix(out, "# EOF\n") {
+		t.Errorf("expected the OpenMetrics EOF:\n%s", out)
+	}
+}
+
+func TestControlMetrics(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&stopWorker{}, "")
+	handler := manager.ControlHandler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); ct != MetricsContentType {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Accept", "application/openmetrics-text; version=1.0.0")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if ct := rec.Header().Get("Content-Type"); ct != OpenMetricsContentType {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+}
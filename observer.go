@@ -0,0 +1,131 @@
+package gum
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Observer is a read-only view of a Manager. It can be handed to dashboard
+// or metrics code without exposing any operation able to change the
+// manager, such as Stop or SwapUnit.
+type Observer interface {
+	Snapshot() Snapshot
+	Units() []UnitStatus
+	Status(name string) (UnitStatus, bool)
+	Subscribe(opts ...SubscribeOption) *Subscription
+	Watch(ctx context.Context, sel Selector) <-chan []UnitUpdate
+	Err() error
+	Summary() *Summary
+	ShutdownMode() ShutdownMode
+	Pressure() Pressure
+	Baggage() map[string]string
+	Topology() []UnitSpec
+	WriteMetrics(w io.Writer) error
+	WriteOpenMetrics(w io.Writer) error
+}
+
+// observer hides the manager so observers can't type assert their way back
+// to it.
+type observer struct {
+	m *Manager
+}
+
+// Observer returns a read-only view of the manager.
+func (m *Manager) Observer() Observer {
+	return observer{m}
+}
+
+func (o observer) Snapshot() Snapshot         { return o.m.Snapshot() }
+func (o observer) Units() []UnitStatus        { return o.m.Units() }
+func (o observer) Err() error                 { return o.m.Err() }
+func (o observer) Summary() *Summary          { return o.m.Summary() }
+func (o observer) ShutdownMode() ShutdownMode { return o.m.ShutdownMode() }
+func (o observer) Pressure() Pressure         { return o.m.Pressure() }
+func (o observer) Baggage() map[string]string { return o.m.Baggage() }
+func (o observer) Topology() []UnitSpec       { return o.m.Topology() }
+
+func (o observer) Status(name string) (UnitStatus, bool) {
+	return o.m.Status(name)
+}
+
+func (o observer) Subscribe(opts ...SubscribeOption) *Subscription {
+	return o.m.Subscribe(opts...)
+}
+
+func (o observer) Watch(ctx context.Context, sel Selector) <-chan []UnitUpdate {
+	return o.m.Watch(ctx, sel)
+}
+
+func (o observer) WriteMetrics(w io.Writer) error {
+	return o.m.WriteMetrics(w)
+}
+
+func (o observer) WriteOpenMetrics(w io.Writer) error {
+	return o.m.WriteOpenMetrics(w)
+}
+
+// ObserverHandler returns an HTTP handler exposing an observer. It serves
+// the read-only endpoints of ControlHandler:
+//
+//	GET  /status  the manager Snapshot as JSON
+//	GET  /metrics the manager metrics, see WriteMetrics
+func ObserverHandler(o Observer) http.Handler {
+	mux := http.NewServeMux()
+	handleObserver(mux, o)
+	return mux
+}
+
+// handleObserver registers the read-only endpoints.
+func handleObserver(mux *http.ServeMux, o Observer) {
+	mux.HandleFunc("/status", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(rw).Encode(newWireSnapshot(o.Snapshot()))
+	})
+
+	mux.HandleFunc("/units/", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		name := strings.TrimPrefix(r.URL.Path, "/units/")
+		if name == "" {
+			units := o.Units()
+			wire := make([]wireUnit, len(units))
+			for i, u := range units {
+				wire[i] = newWireUnit(u)
+			}
+			rw.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(rw).Encode(wire)
+			return
+		}
+
+		u, ok := o.Status(name)
+		if !ok {
+			http.Error(rw, "unknown unit", http.StatusNotFound)
+			return
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(rw).Encode(newWireUnit(u))
+	})
+
+	mux.HandleFunc("/metrics", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if strings.Contains(r.Header.Get("Accept"), "application/openmetrics-text") {
+			rw.Header().Set("Content-Type", OpenMetricsContentType)
+			o.WriteOpenMetrics(rw)
+			return
+		}
+		rw.Header().Set("Content-Type", MetricsContentType)
+		o.WriteMetrics(rw)
+	})
+}
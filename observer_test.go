@@ -0,0 +1,56 @@
+package gum
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestObserver(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&readyWorker{}, "")
+	obs := manager.Observer()
+
+	if _, ok := obs.(*Manager); ok {
+		t.Fatal("the observer must not expose the manager")
+	}
+	if _, ok := obs.(interface{ Stop() }); ok {
+		t.Fatal("the observer must not be able to stop the manager")
+	}
+
+	sub := obs.Subscribe()
+	go manager.Run()
+	waitEvent(t, sub, EventUnitStarted)
+	sub.Close()
+
+	if units := obs.Snapshot().Units; len(units) != 1 {
+		t.Fatalf("unexpected snapshot %+v", units)
+	}
+
+	manager.Stop()
+	<-manager.Quit
+}
+
+func TestObserverHandler(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&stopWorker{}, "", WithName("worker"))
+	handler := ObserverHandler(manager.Observer())
+
+	for _, tc := range []struct {
+		method, path string
+		code         int
+	}{
+		{http.MethodGet, "/status", http.StatusOK},
+		{http.MethodGet, "/metrics", http.StatusOK},
+		{http.MethodGet, "/units/", http.StatusOK},
+		{http.MethodGet, "/units/worker", http.StatusOK},
+		{http.MethodGet, "/units/unknown", http.StatusNotFound},
+		{http.MethodPost, "/stop", http.StatusNotFound},
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(tc.method, tc.path, nil))
+		if rec.Code != tc.code {
+			t.Errorf("%s %s: expected status %d, got %d", tc.method, tc.path, tc.code, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package gum
+
+import "fmt"
+
+// OnStop registers f to be called as soon as the unit is asked to stop, for
+// units blocked in calls such as Accept or Read which only return once their
+// listener or connection is closed. Callbacks run in registration order on
+// a goroutine of their own, so they must not wait for the unit. If the stop
+// was already requested f is called right away. A panic in a callback is
+// reported as a panic of the unit.
+func (w *WorkUnitManager) OnStop(f func()) {
+	w.onStopMu.Lock()
+	if !w.stopping.Load() {
+		w.onStop = append(w.onStop, f)
+		w.onStopMu.Unlock()
+		return
+	}
+	w.onStopMu.Unlock()
+
+	go w.runOnStop([]func(){f})
+}
+
+// stopRequested runs the OnStop callbacks once the stop was requested.
+func (w *WorkUnitManager) stopRequested() {
+	w.onStopMu.Lock()
+	callbacks := w.onStop
+	w.onStop = nil
+	w.onStopMu.Unlock()
+
+	if len(callbacks) > 0 {
+		go w.runOnStop(callbacks)
+	}
+}
+
+func (w *WorkUnitManager) runOnStop(callbacks []func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			w.Panic(fmt.Errorf("OnStop callback: %v", r))
+		}
+	}()
+
+	for _, f := range callbacks {
+		f()
+	}
+}
@@ -0,0 +1,80 @@
+package gum
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// acceptWorker blocks in Accept until its listener is closed
+type acceptWorker struct {
+	ln net.Listener
+}
+
+func (w *acceptWorker) Run(um UnitManager) {
+	um.OnStop(func() { w.ln.Close() })
+	for {
+		conn, err := w.ln.Accept()
+		if err != nil {
+			break
+		}
+		conn.Close()
+	}
+	um.Done()
+}
+
+func TestOnStop(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	manager := NewManager()
+	manager.AddUnit(&acceptWorker{ln: ln}, "")
+
+	go manager.Run()
+	waitState(t, manager, 0, Running)
+	manager.Stop()
+
+	select {
+	case <-manager.Quit:
+	case <-time.After(time.Second):
+		t.Fatal("OnStop callback did not unblock the unit")
+	}
+	if manager.Err() != nil {
+		t.Fatalf("unexpected shutdown cause: %v", manager.Err())
+	}
+}
+
+func TestOnStopAfterStop(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&stopWorker{}, "")
+	w := manager.order[0]
+	w.requestStop()
+
+	called := make(chan struct{})
+	w.OnStop(func() { close(called) })
+
+	select {
+	case <-called:
+	case <-time.After(time.Second):
+		t.Fatal("OnStop callback not called once stopping")
+	}
+}
+
+func TestOnStopPanic(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&stuckWorker{}, "")
+	w := manager.order[0]
+	w.OnStop(func() { panic("boom") })
+
+	go manager.Run()
+	waitState(t, manager, 0, Running)
+	manager.Stop()
+	<-manager.Quit
+
+	if !errors.Is(manager.Err(), ErrUnitPanic) {
+		t.Fatalf("expected a unit panic, got %v", manager.Err())
+	}
+}
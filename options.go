@@ -0,0 +1,161 @@
+package gum
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+var (
+	osExit = os.Exit
+
+	// exit is used to terminate the process, it is replaced in tests.
+	exit = osExit
+)
+
+// Option configures a Manager. Options are passed to NewManager.
+type Option func(*Manager)
+
+// PanicPolicy defines what the manager does once all units have been shut
+// down after one of them called Panic.
+type PanicPolicy int
+
+const (
+	// PanicShutdown notifies the Quit channel after shutdown (default).
+	PanicShutdown PanicPolicy = iota
+
+	// PanicRethrow re-panics with the unit's error from the goroutine
+	// running Run. Call Run on the main goroutine to crash the process from
+	// there.
+	PanicRethrow
+
+	// PanicExit exits the process with the configured exit code, see
+	// WithPanicExitCode.
+	PanicExit
+)
+
+var panicPolicyNames = [...]string{
+	PanicShutdown: "shutdown",
+	PanicRethrow:  "rethrow",
+	PanicExit:     "exit",
+}
+
+func (p PanicPolicy) String() string {
+	if p >= 0 && int(p) < len(panicPolicyNames) {
+		return panicPolicyNames[p]
+	}
+	return fmt.Sprintf("PanicPolicy(%d)", int(p))
+}
+
+func parsePanicPolicy(s string) (PanicPolicy, error) {
+	for p, name := range panicPolicyNames {
+		if name == s {
+			return PanicPolicy(p), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown panic policy %q", s)
+}
+
+// WithPanicPolicy sets the policy applied when a unit panics. Use
+// PanicRethrow or PanicExit for crash-only semantics, letting the process
+// supervisor (systemd, k8s ...) restart the whole process.
+func WithPanicPolicy(policy PanicPolicy) Option {
+	return func(m *Manager) {
+		if policy < PanicShutdown || policy > PanicExit {
+			m.invalid(fmt.Errorf("unknown panic policy: %d", policy))
+			return
+		}
+		m.panicPolicy = policy
+	}
+}
+
+// WithPanicExitCode sets the exit code used by the PanicExit policy. It
+// defaults to ExitPanic.
+func WithPanicExitCode(code int) Option {
+	return WithExitCode(ErrUnitPanic, code)
+}
+
+// WithExitCode overrides the process exit code returned by Manager.ExitCode
+// for the given shutdown cause. Causes are matched with errors.Is.
+func WithExitCode(cause error, code int) Option {
+	return func(m *Manager) {
+		if cause == nil {
+			m.invalid(fmt.Errorf("exit code %d for nil cause", code))
+			return
+		}
+		m.exitCodes = append([]exitCode{{cause, code}}, m.exitCodes...)
+	}
+}
+
+// WithShutdownTimeout sets the shutdown budget. Units still running when it
+// is exceeded are abandoned and the shutdown cause is ErrForcedShutdown. The
+// default, zero, waits for units indefinitely.
+func WithShutdownTimeout(d time.Duration) Option {
+	return func(m *Manager) {
+		if d < 0 {
+			m.invalid(fmt.Errorf("negative shutdown timeout: %s", d))
+			return
+		}
+		m.shutdownTimeout = d
+	}
+}
+
+// WithStartupConcurrency bounds the number of units starting at the same
+// time. A unit is starting until it calls Ready or Done on its UnitManager,
+// units are started in registration order, see After. The default, zero,
+// starts all units at once.
+func WithStartupConcurrency(n int) Option {
+	return func(m *Manager) {
+		switch {
+		case n < 0:
+			m.invalid(fmt.Errorf("negative startup concurrency: %d", n))
+		case n > 0:
+			m.startSem = make(chan struct{}, n)
+		default:
+			m.startSem = nil
+		}
+	}
+}
+
+// WithStartupTimeout bounds the startup of the manager: if all units are not
+// ready (or done or disabled) within d of Run, the startup is aborted. The
+// started units are stopped one at a time in reverse order and the cause is
+// ErrStartup and ErrStartupTimeout. The startup report identifies the unit
+// blocking the startup. The default, zero, waits for units indefinitely.
+func WithStartupTimeout(d time.Duration) Option {
+	return func(m *Manager) {
+		if d < 0 {
+			m.invalid(fmt.Errorf("negative startup timeout: %s", d))
+			return
+		}
+		m.startupTimeout = d
+	}
+}
+
+// UnitOption configures a unit. Unit options are passed to AddUnit.
+type UnitOption func(*WorkUnitManager)
+
+// WithUnitDefaults applies the unit options to every unit added to the
+// manager before the options of the unit, which override them. E.g. all
+// units restarted on failure unless told otherwise:
+//
+//	manager := gum.NewManager(gum.WithUnitDefaults(gum.WithRestart(gum.RestartOnFailure)))
+func WithUnitDefaults(opts ...UnitOption) Option {
+	return func(m *Manager) {
+		m.unitDefaults = append(m.unitDefaults, opts...)
+	}
+}
+
+// WithPanicBudget sets the error budget of the unit: max panics within the
+// window. Crossing the budget publishes an EventBudgetExceeded event,
+// separating degraded units from dead ones.
+func WithPanicBudget(max int, window time.Duration) UnitOption {
+	return func(w *WorkUnitManager) {
+		if max < 0 || window <= 0 {
+			w.invalid(fmt.Errorf("invalid panic budget: %d panics within %s", max, window))
+			return
+		}
+		w.panicBudget = max
+		w.panicBudgetWindow = window
+	}
+}
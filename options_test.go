@@ -0,0 +1,68 @@
+package gum
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type panicWorker struct{}
+
+func (w *panicWorker) Run(um UnitManager) {
+	um.Panic(errors.New("boom"))
+}
+
+func TestPanicRethrow(t *testing.T) {
+	manager := NewManager(WithPanicPolicy(PanicRethrow))
+	manager.AddUnit(&panicWorker{}, "")
+	manager.AddUnit(NewWorker(), "")
+
+	defer func() {
+		err, ok := recover().(error)
+		if !ok || err.Error() != "boom" {
+			t.Fatalf("expected Run to rethrow the unit error, got %v", err)
+		}
+	}()
+
+	manager.Run()
+	t.Fatal("Run returned without panicing")
+}
+
+func TestPanicExit(t *testing.T) {
+	code := -1
+	exit = func(c int) { code = c }
+	defer func() { exit = osExit }()
+
+	manager := NewManager(WithPanicPolicy(PanicExit), WithPanicExitCode(3))
+	manager.AddUnit(&panicWorker{}, "")
+	manager.Run()
+
+	if code != 3 {
+		t.Fatalf("expected exit code 3, got %d", code)
+	}
+}
+
+func TestConcurrentPanics(t *testing.T) {
+	manager := NewManager()
+	for i := 0; i < 10; i++ {
+		manager.AddUnit(&panicWorker{}, "")
+	}
+
+	done := make(chan bool)
+	go func() {
+		manager.Run()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("concurrent panics blocked the manager")
+	}
+
+	for _, u := range manager.Snapshot().Units {
+		if u.State != Failed {
+			t.Fatalf("expected all units to be failed, got %s for <%s>", u.State, u.Name)
+		}
+	}
+}
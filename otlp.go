@@ -0,0 +1,254 @@
+package gum
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// otlpTimeout bounds an export, below the default hook timeout.
+const otlpTimeout = 5 * time.Second
+
+// otlpSeverities maps the severities to the OpenTelemetry severity numbers.
+var otlpSeverities = [...]int{
+	SeverityDebug:    5,
+	SeverityInfo:     9,
+	SeverityWarn:     13,
+	SeverityError:    17,
+	SeverityCritical: 21,
+}
+
+// OTLPExporter is a Notifier exporting the events as OpenTelemetry log
+// records to a collector, over OTLP/HTTP with the JSON encoding. Records
+// carry the event in their body and attributes, the resource attributes
+// identify the service.
+type OTLPExporter struct {
+	endpoint string
+	client   *http.Client
+	headers  map[string]string
+	onError  func(error)
+
+	mu       sync.Mutex
+	resource map[string]string
+}
+
+// OTLPOption configures an OTLPExporter.
+type OTLPOption func(*OTLPExporter)
+
+// WithOTLPResource adds resource attributes, e.g. deployment.environment.
+// service.name defaults to the executable name, service.version is taken
+// from the build info once the manager started.
+func WithOTLPResource(attrs map[string]string) OTLPOption {
+	return func(e *OTLPExporter) {
+		for k, v := range attrs {
+			e.resource[k] = v
+		}
+	}
+}
+
+// WithOTLPHeaders sets headers sent with every export, e.g. for
+// authentication.
+func WithOTLPHeaders(headers map[string]string) OTLPOption {
+	return func(e *OTLPExporter) {
+		for k, v := range headers {
+			e.headers[k] = v
+		}
+	}
+}
+
+// WithOTLPClient sets the client used to reach the collector, e.g. to
+// configure TLS.
+func WithOTLPClient(c *http.Client) OTLPOption {
+	return func(e *OTLPExporter) {
+		e.client = c
+	}
+}
+
+// WithOTLPErrorHandler sets the function called when an export fails. Failed
+// exports are dropped by default.
+func WithOTLPErrorHandler(f func(error)) OTLPOption {
+	return func(e *OTLPExporter) {
+		e.onError = f
+	}
+}
+
+// OTLPLogs returns an exporter posting the events to the OTLP/HTTP logs
+// endpoint of a collector, e.g. http://localhost:4318/v1/logs. Register it
+// with WithNotifier.
+func OTLPLogs(endpoint string, opts ...OTLPOption) *OTLPExporter {
+	e := &OTLPExporter{
+		endpoint: endpoint,
+		client:   http.DefaultClient,
+		headers:  make(map[string]string),
+		resource: map[string]string{
+			"service.name": filepath.Base(os.Args[0]),
+		},
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
+}
+
+// Notify implements Notifier.
+func (e *OTLPExporter) Notify(ev Event) {
+	ctx, cancel := context.WithTimeout(context.Background(), otlpTimeout)
+	defer cancel()
+
+	if err := e.Export(ctx, ev); err != nil && e.onError != nil {
+		e.onError(err)
+	}
+}
+
+// Export posts the event to the collector.
+func (e *OTLPExporter) Export(ctx context.Context, ev Event) error {
+	body, err := json.Marshal(e.logsData(ev))
+	if err != nil {
+		return fmt.Errorf("otlp: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("otlp: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("otlp: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("otlp: %s: %s", e.endpoint, resp.Status)
+	}
+	return nil
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpAttr struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpRecord struct {
+	TimeUnixNano   string     `json:"timeUnixNano"`
+	SeverityNumber int        `json:"severityNumber"`
+	SeverityText   string     `json:"severityText"`
+	Body           otlpValue  `json:"body"`
+	Attributes     []otlpAttr `json:"attributes"`
+	TraceID        string     `json:"traceId,omitempty"`
+}
+
+type otlpScopeLogs struct {
+	Scope struct {
+		Name string `json:"name"`
+	} `json:"scope"`
+	LogRecords []otlpRecord `json:"logRecords"`
+}
+
+type otlpResourceLogs struct {
+	Resource struct {
+		Attributes []otlpAttr `json:"attributes"`
+	} `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpLogsData struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+// logsData returns the OTLP payload of the event.
+func (e *OTLPExporter) logsData(ev Event) otlpLogsData {
+	e.mu.Lock()
+	if ev.Build != nil && ev.Build.Version != "" {
+		if _, ok := e.resource["service.version"]; !ok {
+			e.resource["service.version"] = ev.Build.Version
+		}
+	}
+	resource := otlpAttrs(e.resource)
+	e.mu.Unlock()
+
+	attrs := map[string]string{
+		"event.name": "gum." + ev.Kind.String(),
+	}
+	if ev.Unit != "" {
+		attrs["gum.unit"] = ev.Unit
+	}
+	if ev.Err != nil {
+		attrs["exception.message"] = ev.Err.Error()
+	}
+	if len(ev.Stack) > 0 {
+		attrs["exception.stacktrace"] = string(ev.Stack)
+	}
+	if ev.Reason != "" {
+		attrs["gum.reason"] = ev.Reason
+	}
+	for k, v := range ev.Baggage {
+		attrs["gum.baggage."+k] = v
+	}
+
+	sev := ev.Severity
+	if sev < SeverityDebug || int(sev) >= len(otlpSeverities) {
+		sev = eventSeverity(ev)
+	}
+
+	record := otlpRecord{
+		TimeUnixNano:   strconv.FormatInt(ev.Time.UnixNano(), 10),
+		SeverityNumber: otlpSeverities[sev],
+		SeverityText:   sev.String(),
+		Body:           otlpValue{ev.String()},
+		Attributes:     otlpAttrs(attrs),
+		TraceID:        otlpTraceID(ev.TraceID),
+	}
+
+	scope := otlpScopeLogs{LogRecords: []otlpRecord{record}}
+	scope.Scope.Name = "git.blob42.xyz/blob42/gum"
+
+	var rl otlpResourceLogs
+	rl.Resource.Attributes = resource
+	rl.ScopeLogs = []otlpScopeLogs{scope}
+
+	return otlpLogsData{ResourceLogs: []otlpResourceLogs{rl}}
+}
+
+// otlpAttrs returns the attributes sorted by key.
+func otlpAttrs(m map[string]string) []otlpAttr {
+	attrs := make([]otlpAttr, 0, len(m))
+	for k, v := range m {
+		attrs = append(attrs, otlpAttr{k, otlpValue{v}})
+	}
+	sort.Slice(attrs, func(i, j int) bool {
+		return attrs[i].Key < attrs[j].Key
+	})
+	return attrs
+}
+
+// otlpTraceID pads the trace ID to the 16 bytes of an OpenTelemetry trace ID,
+// or returns an empty string if it is not hex encoded.
+func otlpTraceID(id string) string {
+	b, err := hex.DecodeString(id)
+	if err != nil || len(b) == 0 || len(b) > 16 {
+		return ""
+	}
+	return hex.EncodeToString(append(make([]byte, 16-len(b)), b...))
+}
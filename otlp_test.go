@@ -0,0 +1,100 @@
+package gum
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestOTLPLogs(t *testing.T) {
+	var mu sync.Mutex
+	var exports []otlpLogsData
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/logs" || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected request %s %s", r.URL.Path, r.Header.Get("Content-Type"))
+		}
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("missing authorization header")
+		}
+		var data otlpLogsData
+		if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+			t.Error(err)
+		}
+		mu.Lock()
+		exports = append(exports, data)
+		mu.Unlock()
+	}))
+	defer collector.Close()
+
+	exporter := OTLPLogs(collector.URL+"/v1/logs",
+		WithOTLPResource(map[string]string{"service.name": "api", "deployment.environment": "test"}),
+		WithOTLPHeaders(map[string]string{"Authorization": "Bearer token"}),
+	)
+	manager := NewManager(
+		WithBuildInfo(BuildInfo{Version: "1.2.3"}),
+		WithBaggage(map[string]string{"run": "42"}),
+		WithNotifier(exporter, SeverityInfo),
+	)
+	manager.AddUnit(&panicWorker{}, "")
+	manager.Run()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	var panicked *otlpRecord
+	for _, data := range exports {
+		rl := data.ResourceLogs[0]
+		resource := attrMap(rl.Resource.Attributes)
+		if resource["service.name"] != "api" || resource["service.version"] != "1.2.3" ||
+			resource["deployment.environment"] != "test" {
+			t.Errorf("unexpected resource %v", resource)
+		}
+
+		record := rl.ScopeLogs[0].LogRecords[0]
+		if attrMap(record.Attributes)["event.name"] == "gum.unit-panic" {
+			panicked = &record
+		}
+	}
+	if panicked == nil {
+		t.Fatalf("expected the panic to be exported, got %d exports", len(exports))
+	}
+
+	attrs := attrMap(panicked.Attributes)
+	if attrs["exception.message"] != "boom" || attrs["gum.baggage.run"] != "42" || attrs["gum.unit"] == "" {
+		t.Errorf("unexpected attributes %v", attrs)
+	}
+	if panicked.SeverityNumber != 17 || panicked.SeverityText != "error" {
+		t.Errorf("unexpected severity %d %s", panicked.SeverityNumber, panicked.SeverityText)
+	}
+	if len(panicked.TraceID) != 32 {
+		t.Errorf("unexpected trace ID %q", panicked.TraceID)
+	}
+}
+
+func TestOTLPExportError(t *testing.T) {
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer collector.Close()
+
+	var failed error
+	exporter := OTLPLogs(collector.URL, WithOTLPErrorHandler(func(err error) {
+		failed = err
+	}))
+	exporter.Notify(Event{Kind: EventUnitDone, Unit: "api", Err: errors.New("boom")})
+
+	if failed == nil {
+		t.Error("expected the export to fail")
+	}
+}
+
+func attrMap(attrs []otlpAttr) map[string]string {
+	m := make(map[string]string, len(attrs))
+	for _, a := range attrs {
+		m[a.Key] = a.Value.StringValue
+	}
+	return m
+}
@@ -0,0 +1,92 @@
+package gum
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// unitPanic is a failure reported by a unit with Panic.
+type unitPanic struct {
+	unit *WorkUnitManager
+	err  error
+}
+
+// unitPanic queues the failure of the unit and wakes up the manager, unless
+// the failure is contained, see WithFailurePolicy.
+func (m *Manager) unitPanic(w *WorkUnitManager, err error) {
+	if w.failurePolicy == FailureContain {
+		m.containFailure(w, err)
+		return
+	}
+
+	m.panicMu.Lock()
+	m.panicQueue = append(m.panicQueue, unitPanic{w, err})
+	m.panicMu.Unlock()
+
+	select {
+	case m.panicC <- struct{}{}:
+	default:
+	}
+}
+
+// handlePanics logs and records the queued unit failures in the shutdown
+// cause. It returns the handled failures, in the order they were reported.
+func (m *Manager) handlePanics() []unitPanic {
+	m.panicMu.Lock()
+	panics := m.panicQueue
+	m.panicQueue = nil
+	m.panicMu.Unlock()
+
+	for _, p := range panics {
+		m.failf(p.unit.name, p.err, nil, "Panicing for <%s> (trace %s)\n", p.unit, m.unitTrace(p.unit))
+		m.addErr(fmt.Errorf("%w <%s>: %w", ErrUnitPanic, p.unit, p.err))
+		m.emitUnit(EventUnitPanic, p.unit, p.err)
+	}
+
+	if m.historyPath != "" && len(panics) > 0 {
+		if err := m.saveHistory(); err != nil {
+			m.warnf("", "Could not save history: %s\n", err)
+		}
+	}
+
+	return panics
+}
+
+// PanicError is the error reported for a unit whose Run panicked. Use
+// errors.As on the shutdown cause or on EventUnitPanic errors to get the
+// stack trace of the panic.
+type PanicError struct {
+	Value any    // Value passed to panic
+	Stack []byte // Stack trace of the panicking goroutine
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error, e.g. a runtime error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// run runs the unit, converting a panic of its Run method to a call to
+// Panic, so other units still get a graceful shutdown. Tasks are done once
+// Run returned, see AsTask.
+func (w *WorkUnitManager) run() {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+
+		err := &PanicError{Value: r, Stack: debug.Stack()}
+		w.manager.failf(w.name, err, err.Stack, "<%s> panicked\n", w)
+		w.Panic(err)
+	}()
+
+	w.unit.Run(w)
+	if w.task {
+		w.Done()
+	}
+}
@@ -0,0 +1,51 @@
+package gum
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// crashWorker panics at runtime
+type crashWorker struct{}
+
+func (w *crashWorker) Run(um UnitManager) {
+	var counts map[string]int
+	counts["boom"]++
+}
+
+func TestRecoverUnitPanic(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&crashWorker{}, "")
+	manager.AddUnit(&stopWorker{}, "")
+
+	select {
+	case <-runAsync(manager):
+	case <-time.After(time.Second):
+		t.Fatal("manager did not quit after the unit panic")
+	}
+
+	if state := manager.Snapshot().Units[1].State; state != Stopped {
+		t.Fatalf("expected the other unit to be stopped gracefully, got %s", state)
+	}
+
+	err := manager.Err()
+	if !errors.Is(err, ErrUnitPanic) {
+		t.Fatalf("expected a unit panic, got %v", err)
+	}
+
+	var perr *PanicError
+	if !errors.As(err, &perr) {
+		t.Fatalf("expected a PanicError, got %v", err)
+	}
+	if !strings.Contains(string(perr.Stack), "crashWorker") {
+		t.Fatalf("expected the stack of the panic, got:\n%s", perr.Stack)
+	}
+
+	var rerr runtime.Error
+	if !errors.As(err, &rerr) {
+		t.Fatalf("expected the runtime error, got %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package gum
+
+import (
+	"fmt"
+	"time"
+)
+
+// Park tells the manager the unit is intentionally dormant until the given
+// time, or until woken with Manager.Wake if until is zero. The unit state is
+// Parked meanwhile so watchdogs and status don't mistake it for a stuck
+// unit. The returned channel is closed when the unit is woken, either at
+// until or by Wake, and the unit is Running again. A parked unit must still
+// select on ShouldStop.
+func (w *WorkUnitManager) Park(until time.Time) <-chan struct{} {
+	m := w.manager
+	wake := make(chan struct{})
+	if m.strict && w.done.Load() {
+		m.logf("strict: <%s> called Park after Done\n", w)
+	}
+
+	m.regMu.Lock()
+	if w.state != Running {
+		// Stopping units are not parked
+		m.regMu.Unlock()
+		close(wake)
+		return wake
+	}
+	w.state = Parked
+	w.parkedUntil = until
+	w.wake = wake
+	if !until.IsZero() {
+		w.parkTimer = m.clock.AfterFunc(until.Sub(m.clock.Now()), func() { m.wakeUnit(w, wake) })
+	}
+	m.touch()
+	m.regMu.Unlock()
+
+	m.emitUnit(EventUnitParked, w, nil)
+	return wake
+}
+
+// Wake wakes up the parked unit with the given name.
+func (m *Manager) Wake(name string) error {
+	m.regMu.RLock()
+	w, ok := m.workers[name]
+	var wake chan struct{}
+	if ok {
+		wake = w.wake
+	}
+	m.regMu.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("unknown unit <%s>", name)
+	}
+	if wake == nil || !m.wakeUnit(w, wake) {
+		return fmt.Errorf("unit <%s> is not parked", name)
+	}
+	return nil
+}
+
+// wakeUnit wakes up the unit parked with the given wake channel. It returns
+// false if the unit was already woken.
+func (m *Manager) wakeUnit(w *WorkUnitManager, wake chan struct{}) bool {
+	m.regMu.Lock()
+	if w.wake != wake {
+		m.regMu.Unlock()
+		return false
+	}
+	if w.parkTimer != nil {
+		w.parkTimer.Stop()
+		w.parkTimer = nil
+	}
+	w.wake = nil
+	w.parkedUntil = time.Time{}
+	if w.state == Parked {
+		w.state = Running
+	}
+	m.touch()
+	m.regMu.Unlock()
+
+	close(wake)
+	m.emitUnit(EventUnitWoken, w, nil)
+	return true
+}
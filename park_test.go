@@ -0,0 +1,89 @@
+package gum
+
+import (
+	"testing"
+	"time"
+)
+
+// parkWorker parks until woken
+type parkWorker struct {
+	until time.Time
+	woken chan bool
+}
+
+func (w *parkWorker) Run(um UnitManager) {
+	select {
+	case <-um.Park(w.until):
+		w.woken <- true
+		<-um.ShouldStop()
+	case <-um.ShouldStop():
+	}
+	um.Done()
+}
+
+func TestParkWake(t *testing.T) {
+	manager := NewManager()
+	w := &parkWorker{woken: make(chan bool, 1)}
+	manager.AddUnit(w, "")
+	name := manager.order[0].name
+
+	go manager.Run()
+	defer func() {
+		manager.Stop()
+		<-manager.Quit
+	}()
+
+	waitState(t, manager, 0, Parked)
+	if err := manager.Wake(name); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-w.woken:
+	case <-time.After(time.Second):
+		t.Fatal("unit was not woken")
+	}
+	waitState(t, manager, 0, Running)
+
+	if err := manager.Wake(name); err == nil {
+		t.Fatal("expected waking a running unit to fail")
+	}
+}
+
+func TestParkUntil(t *testing.T) {
+	manager := NewManager()
+	w := &parkWorker{until: time.Now().Add(20 * time.Millisecond), woken: make(chan bool, 1)}
+	manager.AddUnit(w, "")
+
+	go manager.Run()
+	defer func() {
+		manager.Stop()
+		<-manager.Quit
+	}()
+
+	waitState(t, manager, 0, Parked)
+	if until := manager.Snapshot().Units[0].ParkedUntil; !until.Equal(w.until) {
+		t.Fatalf("expected parked until %s, got %s", w.until, until)
+	}
+
+	select {
+	case <-w.woken:
+	case <-time.After(time.Second):
+		t.Fatal("unit was not woken")
+	}
+}
+
+func TestParkStop(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&parkWorker{woken: make(chan bool, 1)}, "")
+
+	go manager.Run()
+	waitState(t, manager, 0, Parked)
+
+	manager.Stop()
+	select {
+	case <-manager.Quit:
+	case <-time.After(time.Second):
+		t.Fatal("parked unit was not stopped")
+	}
+}
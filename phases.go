@@ -0,0 +1,132 @@
+package gum
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ShutdownPhase identifies a phase of the shutdown.
+type ShutdownPhase int
+
+const (
+	// PhaseQuiesce deregisters the process from service discovery, see
+	// WithRegistrar.
+	PhaseQuiesce ShutdownPhase = iota + 1
+
+	// PhaseStop asks the units to stop and waits for them to be done. Units
+	// still running at the end of the phase are abandoned.
+	PhaseStop
+
+	// PhaseFinalize runs the finalizers, see WithFinalizer.
+	PhaseFinalize
+)
+
+var shutdownPhaseNames = [...]string{
+	PhaseQuiesce:  "quiesce",
+	PhaseStop:     "stop",
+	PhaseFinalize: "finalize",
+}
+
+func (p ShutdownPhase) String() string {
+	if p < PhaseQuiesce || int(p) >= len(shutdownPhaseNames) {
+		return fmt.Sprintf("ShutdownPhase(%d)", int(p))
+	}
+	return shutdownPhaseNames[p]
+}
+
+// PhaseReport is the outcome of a shutdown phase.
+type PhaseReport struct {
+	Phase    ShutdownPhase
+	Duration time.Duration
+	TimedOut bool // The phase budget was exceeded
+}
+
+// Finalizer runs once all units are done or abandoned, e.g. to flush
+// telemetry. Its context is done at the end of the finalize phase budget.
+type Finalizer func(ctx context.Context) error
+
+// WithPhaseTimeout sets the budget of a shutdown phase, so a long drain of
+// the units can't eat the time left for the finalizers. Each phase is also
+// bounded by the whole shutdown budget, see WithShutdownTimeout. The default,
+// zero, only bounds the phase by the shutdown budget.
+func WithPhaseTimeout(phase ShutdownPhase, d time.Duration) Option {
+	return func(m *Manager) {
+		if phase < PhaseQuiesce || phase > PhaseFinalize {
+			m.invalid(fmt.Errorf("invalid shutdown phase %d", int(phase)))
+			return
+		}
+		if d < 0 {
+			m.invalid(fmt.Errorf("negative %s phase timeout: %s", phase, d))
+			return
+		}
+		m.phaseTimeouts[phase] = d
+	}
+}
+
+// WithFinalizer adds a finalizer run during the finalize phase of the
+// shutdown. Finalizers run in the order they were added, their errors are
+// joined to the shutdown cause.
+func WithFinalizer(f Finalizer) Option {
+	return func(m *Manager) {
+		if f == nil {
+			m.invalid(fmt.Errorf("nil finalizer"))
+			return
+		}
+		m.finalizers = append(m.finalizers, f)
+	}
+}
+
+// runPhase runs a shutdown phase within its budget and publishes its report
+// as an EventShutdownPhase event.
+func (m *Manager) runPhase(ctx context.Context, phase ShutdownPhase, f func(context.Context)) {
+	if d := m.phaseTimeouts[phase]; d > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d)
+		defer cancel()
+	}
+
+	start := m.clock.Now()
+	m.protect(phase.String()+" phase", func() { f(ctx) })
+
+	report := PhaseReport{
+		Phase:    phase,
+		Duration: m.clock.Now().Sub(start),
+		TimedOut: ctx.Err() != nil,
+	}
+
+	var err error
+	if report.TimedOut {
+		err = fmt.Errorf("%s phase timeout exceeded", phase)
+	}
+	m.regMu.Lock()
+	m.phases = append(m.phases, report)
+	m.regMu.Unlock()
+
+	if m.currentVerbosity() >= logVerbose {
+		m.logf("Shutdown %s phase took %s\n", phase, report.Duration)
+	}
+	m.emitEvent(Event{
+		Kind:    EventShutdownPhase,
+		Err:     err,
+		Latency: report.Duration,
+		Phase:   &report,
+	})
+}
+
+// finalize runs the finalizers. Finalizers are skipped once the phase budget
+// is exceeded.
+func (m *Manager) finalize(ctx context.Context) {
+	for i, f := range m.finalizers {
+		if ctx.Err() != nil {
+			m.warnf("", "finalize phase timeout exceeded, skipping %d finalizers\n",
+				len(m.finalizers)-i)
+			return
+		}
+
+		if err := f(ctx); err != nil {
+			m.warnf("", "finalizer: %s\n", err)
+			m.addErr(fmt.Errorf("finalizer: %w", err))
+		}
+	}
+}
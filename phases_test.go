@@ -0,0 +1,117 @@
+package gum
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestPhaseTimeout(t *testing.T) {
+	finalized := make(chan time.Duration, 1)
+	finalizer := func(ctx context.Context) error {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			finalized <- 0
+		} else {
+			finalized <- time.Until(deadline)
+		}
+		return nil
+	}
+
+	manager := NewManager(
+		WithShutdownTimeout(time.Second),
+		WithPhaseTimeout(PhaseStop, 50*time.Millisecond),
+		WithPhaseTimeout(PhaseFinalize, 200*time.Millisecond),
+		WithFinalizer(finalizer),
+	)
+	manager.ShutdownOn(os.Interrupt)
+	manager.AddUnit(&stuckWorker{}, "")
+	sub := manager.Subscribe()
+
+	go manager.Run()
+	manager.signalIn <- os.Interrupt
+
+	select {
+	case <-manager.Quit:
+	case <-time.After(time.Second):
+		t.Fatal("manager did not quit after stop phase timeout")
+	}
+	sub.Close()
+
+	// The stuck unit must not eat the budget of the finalizers
+	if remaining := <-finalized; remaining <= 100*time.Millisecond || remaining > 200*time.Millisecond {
+		t.Fatalf("unexpected finalize budget: %s", remaining)
+	}
+	if !errors.Is(manager.Err(), ErrForcedShutdown) {
+		t.Fatalf("expected forced shutdown, got %v", manager.Err())
+	}
+
+	var phases []PhaseReport
+	for ev := range sub.Events() {
+		if ev.Kind == EventShutdownPhase {
+			phases = append(phases, *ev.Phase)
+		}
+	}
+
+	want := []struct {
+		phase    ShutdownPhase
+		timedOut bool
+	}{
+		{PhaseQuiesce, false},
+		{PhaseStop, true},
+		{PhaseFinalize, false},
+	}
+	if len(phases) != len(want) {
+		t.Fatalf("expected %d phase reports, got %+v", len(want), phases)
+	}
+	for i, w := range want {
+		if phases[i].Phase != w.phase || phases[i].TimedOut != w.timedOut {
+			t.Fatalf("unexpected phase report %+v", phases[i])
+		}
+	}
+	if d := phases[1].Duration; d < 50*time.Millisecond {
+		t.Fatalf("stop phase ended early: %s", d)
+	}
+}
+
+func TestFinalizerError(t *testing.T) {
+	errFlush := errors.New("flush failed")
+
+	var ran []int
+	manager := NewManager(
+		WithFinalizer(func(context.Context) error {
+			ran = append(ran, 1)
+			return errFlush
+		}),
+		WithFinalizer(func(context.Context) error {
+			ran = append(ran, 2)
+			return nil
+		}),
+	)
+	manager.AddUnit(&stopWorker{}, "")
+
+	go manager.Run()
+	manager.Stop()
+	<-manager.Quit
+
+	if len(ran) != 2 || ran[0] != 1 || ran[1] != 2 {
+		t.Fatalf("expected finalizers to run in order, got %v", ran)
+	}
+	if !errors.Is(manager.Err(), errFlush) {
+		t.Fatalf("expected the finalizer error in the shutdown cause, got %v", manager.Err())
+	}
+}
+
+func TestPhaseTimeoutValidation(t *testing.T) {
+	for _, opt := range []Option{
+		WithPhaseTimeout(ShutdownPhase(0), time.Second),
+		WithPhaseTimeout(PhaseStop, -time.Second),
+		WithFinalizer(nil),
+	} {
+		if err := NewManager(opt).Validate(); err == nil {
+			t.Error("expected a validation error")
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package gum
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// pool is the unit and options of the replicas of a pool, see AddPool.
+type pool struct {
+	unit WorkUnit
+	opts []UnitOption
+}
+
+// AddPool registers replicas instances of the same unit, named name#0 to
+// name#<replicas-1>, e.g. stateless workers consuming a shared queue. The
+// unit must be safe to run concurrently. The replicas form a family: they are
+// listed with Family and operated as a set with RestartFamily and
+// RemoveFamily. The pool is resized at runtime with ResizePool.
+func (m *Manager) AddPool(unit WorkUnit, name string, replicas int, opts ...UnitOption) {
+	switch {
+	case unit == nil:
+		m.invalid(fmt.Errorf("nil unit for pool %q", name))
+		return
+	case name == "":
+		m.invalid(fmt.Errorf("pool without name"))
+		return
+	case replicas < 0:
+		m.invalid(fmt.Errorf("pool %q: negative replicas: %d", name, replicas))
+		return
+	}
+
+	m.poolMu.Lock()
+	_, dup := m.pools[name]
+	_, template := m.templates[name]
+	if dup || template {
+		m.poolMu.Unlock()
+		m.invalid(fmt.Errorf("duplicate unit family %q", name))
+		return
+	}
+	if m.pools == nil {
+		m.pools = make(map[string]pool)
+	}
+	m.pools[name] = pool{unit, slices.Clip(opts)}
+	m.poolMu.Unlock()
+
+	m.ResizePool(name, replicas)
+}
+
+// ResizePool scales the pool to the given number of replicas. New replicas
+// take the lowest free names and are started right away if the manager runs.
+// Extra replicas, the last ones first, are removed as with RemoveUnit. It
+// returns the errors of the replicas which could not be added or removed.
+func (m *Manager) ResizePool(name string, replicas int) error {
+	if replicas < 0 {
+		return fmt.Errorf("pool %q: negative replicas: %d", name, replicas)
+	}
+
+	m.poolMu.Lock()
+	defer m.poolMu.Unlock()
+
+	p, ok := m.pools[name]
+	if !ok {
+		return fmt.Errorf("no pool %q", name)
+	}
+
+	live := m.poolReplicas(name)
+	if len(live) > replicas {
+		m.logf("Scaling pool %s down to %d replicas\n", name, replicas)
+		var errs []error
+		for _, r := range live[replicas:] {
+			if err := m.RemoveUnit(r); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}
+	if len(live) == replicas {
+		return nil
+	}
+
+	m.logf("Scaling pool %s up to %d replicas\n", name, replicas)
+	var errs []error
+	added := 0
+	for i, missing := 0, replicas-len(live); missing > 0; i++ {
+		replica := name + "#" + strconv.Itoa(i)
+		m.regMu.RLock()
+		_, taken := m.workers[replica] // Live or still being removed
+		m.regMu.RUnlock()
+		if taken {
+			continue
+		}
+
+		missing--
+		if err := m.tryAddUnit(p.unit, name, append(slices.Clip(p.opts), WithName(replica), withFamily(name))...); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		added++
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("pool %q: %d of %d replicas added: %w", name, added, replicas-len(live), errors.Join(errs...))
+	}
+	return nil
+}
+
+// poolReplicas returns the names of the live replicas of the pool, by index.
+func (m *Manager) poolReplicas(name string) []string {
+	m.regMu.RLock()
+	defer m.regMu.RUnlock()
+
+	var replicas []string
+	for _, w := range m.order {
+		if w.family == name && m.workers[w.name] == w && !w.removed.Load() {
+			replicas = append(replicas, w.name)
+		}
+	}
+
+	index := func(replica string) int {
+		i, _ := strconv.Atoi(strings.TrimPrefix(replica, name+"#"))
+		return i
+	}
+	slices.SortFunc(replicas, func(a, b string) int { return index(a) - index(b) })
+	return replicas
+}
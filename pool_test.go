@@ -0,0 +1,91 @@
+package gum
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPool(t *testing.T) {
+	manager := NewManager()
+	manager.AddPool(&readyWorker{}, "worker", 2, WithRestart(RestartOnFailure))
+
+	sub := manager.Subscribe()
+	quit := runAsync(manager)
+	waitEvent(t, sub, EventStartupComplete)
+
+	// running waits for the pool to be made of the wanted running replicas
+	running := func(want ...string) {
+		t.Helper()
+
+		deadline := time.Now().Add(time.Second)
+		for {
+			var names []string
+			for _, u := range manager.Family("worker") {
+				if u.State != Running {
+					names = append(names, u.Name+" "+u.State.String())
+					continue
+				}
+				names = append(names, u.Name)
+			}
+			if slices.Equal(names, want) {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("expected replicas %v running, got %v", want, names)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	running("worker#0", "worker#1")
+
+	if err := manager.ResizePool("worker", 4); err != nil {
+		t.Fatalf("unexpected resize error: %v", err)
+	}
+	running("worker#0", "worker#1", "worker#2", "worker#3")
+
+	if err := manager.ResizePool("worker", 1); err != nil {
+		t.Fatalf("unexpected resize error: %v", err)
+	}
+	running("worker#0")
+
+	// Freed names are reused
+	if err := manager.ResizePool("worker", 2); err != nil {
+		t.Fatalf("unexpected resize error: %v", err)
+	}
+	running("worker#0", "worker#1")
+
+	if err := manager.ResizePool("other", 1); err == nil {
+		t.Fatal("expected an error resizing an unknown pool")
+	}
+	if err := manager.ResizePool("worker", -1); err == nil {
+		t.Fatal("expected an error resizing to negative replicas")
+	}
+
+	// Replicas which could not be added are reported
+	manager.Freeze("incident")
+	if err := manager.ResizePool("worker", 3); !errors.Is(err, ErrFrozen) {
+		t.Fatalf("expected the frozen topology to be reported, got %v", err)
+	}
+	manager.Unfreeze()
+	running("worker#0", "worker#1")
+
+	manager.Stop()
+	<-quit
+	if manager.Err() != nil {
+		t.Fatalf("unexpected shutdown cause: %v", manager.Err())
+	}
+	if err := manager.ResizePool("worker", 3); err == nil {
+		t.Fatal("expected an error scaling up once the manager quit")
+	}
+}
+
+func TestPoolValidate(t *testing.T) {
+	manager := NewManager()
+	manager.AddPool(&readyWorker{}, "worker", -1)
+	manager.AddPool(&readyWorker{}, "", 1)
+	if err := manager.Validate(); err == nil {
+		t.Fatal("expected invalid pools to be reported")
+	}
+}
@@ -0,0 +1,161 @@
+package gum
+
+import (
+	"math"
+	"runtime"
+	"runtime/metrics"
+	"time"
+)
+
+// Pressure holds the runtime pressure signals sampled by the manager over
+// the last sample interval, see WithSampleInterval. Background units can use
+// them to voluntarily slow down when the process is under load.
+type Pressure struct {
+	Time time.Time
+
+	// GCPause is the fraction of the CPU time spent in GC pauses.
+	GCPause float64
+
+	// CPU is the fraction of the available CPU (GOMAXPROCS) in use. The
+	// runtime only updates it at the end of GC cycles.
+	CPU float64
+
+	// SchedLatency is the 99th percentile of the time goroutines spent
+	// runnable before running. It grows when GOMAXPROCS is saturated.
+	SchedLatency time.Duration
+
+	Goroutines uint64
+	GOMAXPROCS int
+}
+
+const (
+	cpuTotalMetric     = "/cpu/classes/total:cpu-seconds"
+	cpuIdleMetric      = "/cpu/classes/idle:cpu-seconds"
+	gcPauseMetric      = "/cpu/classes/gc/pause:cpu-seconds"
+	schedLatencyMetric = "/sched/latencies:seconds"
+	goroutinesMetric   = "/sched/goroutines:goroutines"
+)
+
+// WithPressureHook registers a function called with the runtime pressure
+// after each sample, e.g. to autoscale worker pools.
+func WithPressureHook(hook func(Pressure)) Option {
+	return func(m *Manager) {
+		m.pressureHooks = append(m.pressureHooks, hook)
+	}
+}
+
+// WithRuntimeMetrics adds the last runtime pressure sampled by the manager
+// to its snapshots and metrics, see WriteMetrics.
+func WithRuntimeMetrics() Option {
+	return func(m *Manager) {
+		m.runtimeMetrics = true
+	}
+}
+
+// Pressure returns the last runtime pressure sampled by the manager. It is
+// the zero value until the first sample is taken.
+func (m *Manager) Pressure() Pressure {
+	if p := m.pressure.Load(); p != nil {
+		return *p
+	}
+	return Pressure{}
+}
+
+// Pressure returns the last runtime pressure sampled by the manager.
+func (w *WorkUnitManager) Pressure() Pressure {
+	return w.manager.Pressure()
+}
+
+// pressureSampler computes the pressure from the difference between two
+// samples of the runtime metrics.
+type pressureSampler struct {
+	samples []metrics.Sample
+	prev    []metrics.Sample
+}
+
+func newPressureSampler() *pressureSampler {
+	names := []string{
+		cpuTotalMetric,
+		cpuIdleMetric,
+		gcPauseMetric,
+		schedLatencyMetric,
+		goroutinesMetric,
+	}
+
+	s := &pressureSampler{
+		samples: make([]metrics.Sample, len(names)),
+		prev:    make([]metrics.Sample, len(names)),
+	}
+	for i, name := range names {
+		s.samples[i].Name = name
+		s.prev[i].Name = name
+	}
+	metrics.Read(s.prev)
+
+	return s
+}
+
+func (s *pressureSampler) sample() Pressure {
+	metrics.Read(s.samples)
+
+	p := Pressure{
+		Time:       time.Now(),
+		Goroutines: s.samples[4].Value.Uint64(),
+		GOMAXPROCS: runtime.GOMAXPROCS(0),
+	}
+
+	total := s.samples[0].Value.Float64() - s.prev[0].Value.Float64()
+	if total > 0 {
+		idle := s.samples[1].Value.Float64() - s.prev[1].Value.Float64()
+		pause := s.samples[2].Value.Float64() - s.prev[2].Value.Float64()
+		p.CPU = 1 - idle/total
+		p.GCPause = pause / total
+	}
+
+	p.SchedLatency = histogramPercentile(
+		s.prev[3].Value.Float64Histogram(),
+		s.samples[3].Value.Float64Histogram(),
+		0.99,
+	)
+
+	// Read reuses the histogram of the samples, swapping keeps the current
+	// one for the next sample.
+	s.prev, s.samples = s.samples, s.prev
+
+	return p
+}
+
+// histogramPercentile returns the percentile of the observations recorded
+// between two cumulative histograms, in seconds.
+func histogramPercentile(prev, cur *metrics.Float64Histogram, q float64) time.Duration {
+	var total uint64
+	counts := make([]uint64, len(cur.Counts))
+	for i := range cur.Counts {
+		counts[i] = cur.Counts[i]
+		if prev != nil && i < len(prev.Counts) {
+			counts[i] -= prev.Counts[i]
+		}
+		total += counts[i]
+	}
+	if total == 0 {
+		return 0
+	}
+
+	rank := uint64(math.Ceil(q * float64(total)))
+	var seen uint64
+	for i, c := range counts {
+		seen += c
+		if seen < rank {
+			continue
+		}
+
+		// Upper bound of the bucket, or its lower bound for the last one
+		bound := cur.Buckets[i+1]
+		if math.IsInf(bound, 1) {
+			bound = cur.Buckets[i]
+		}
+		return time.Duration(bound * float64(time.Second))
+	}
+
+	return 0
+}
@@ -0,0 +1,64 @@
+package gum
+
+import (
+	"math"
+	"os"
+	"runtime/metrics"
+	"testing"
+	"time"
+)
+
+func TestHistogramPercentile(t *testing.T) {
+	prev := &metrics.Float64Histogram{
+		Counts:  []uint64{5, 5, 0},
+		Buckets: []float64{0, 0.001, 0.01, math.Inf(1)},
+	}
+	cur := &metrics.Float64Histogram{
+		Counts:  []uint64{95, 6, 1},
+		Buckets: prev.Buckets,
+	}
+
+	// 90 observations in the first bucket, 1 in the second and 1 above
+	if p := histogramPercentile(prev, cur, 0.5); p != time.Millisecond {
+		t.Errorf("unexpected median %s", p)
+	}
+	if p := histogramPercentile(prev, cur, 0.99); p != 10*time.Millisecond {
+		t.Errorf("unexpected 99th percentile %s", p)
+	}
+	if p := histogramPercentile(cur, cur, 0.99); p != 0 {
+		t.Errorf("expected no observation, got %s", p)
+	}
+}
+
+func TestPressureHook(t *testing.T) {
+	pressure := make(chan Pressure, 1)
+	manager := NewManager(
+		WithSampleInterval(10*time.Millisecond),
+		WithPressureHook(func(p Pressure) {
+			select {
+			case pressure <- p:
+			default:
+			}
+		}),
+	)
+	manager.ShutdownOn(os.Interrupt)
+	manager.AddUnit(&stopWorker{}, "")
+
+	go manager.Run()
+	defer func() {
+		manager.signalIn <- os.Interrupt
+		<-manager.Quit
+	}()
+
+	select {
+	case p := <-pressure:
+		if p.GOMAXPROCS < 1 || p.Goroutines < 1 {
+			t.Fatalf("unexpected pressure %+v", p)
+		}
+		if manager.order[0].Pressure().Time.IsZero() {
+			t.Fatal("expected pressure to be available to units")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("pressure hook not called")
+	}
+}
@@ -0,0 +1,75 @@
+package gum
+
+import (
+	"fmt"
+	"time"
+)
+
+// Profile is a preset of manager options for an environment.
+type Profile int
+
+const (
+	// ProfileDev logs every event, uses short timeouts and reports misuses
+	// of the UnitManager API: Done called more than once, Ready or Park
+	// called after Done.
+	ProfileDev Profile = iota + 1
+
+	// ProfileProd only logs manager messages, gives units a long drain and
+	// exports the runtime metrics, see WithRuntimeMetrics.
+	ProfileProd
+
+	// ProfileTest does not handle OS signals, which are injected by tests,
+	// only logs manager messages, uses short timeouts and seeds the jitter
+	// so restart schedules are reproducible. Pair it with WithClock to drive
+	// the restarts and scheduled units with a fake clock.
+	ProfileTest
+)
+
+var profileNames = [...]string{
+	ProfileDev:  "dev",
+	ProfileProd: "prod",
+	ProfileTest: "test",
+}
+
+func (p Profile) String() string {
+	if p > 0 && int(p) < len(profileNames) {
+		return profileNames[p]
+	}
+	return fmt.Sprintf("Profile(%d)", int(p))
+}
+
+func parseProfile(s string) (Profile, error) {
+	for p, name := range profileNames {
+		if p > 0 && name == s {
+			return Profile(p), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown profile %q", s)
+}
+
+// WithProfile applies the options preset of the profile. Options passed
+// after it override the preset.
+func WithProfile(profile Profile) Option {
+	return func(m *Manager) {
+		switch profile {
+		case ProfileDev:
+			m.verbosity = logVerbose
+			m.strict = true
+			m.shutdownTimeout = 5 * time.Second
+
+		case ProfileProd:
+			m.verbosity = logQuiet
+			m.shutdownTimeout = time.Minute
+			m.runtimeMetrics = true
+
+		case ProfileTest:
+			m.verbosity = logQuiet
+			m.noSignals = true
+			m.shutdownTimeout = time.Second
+			WithSeed(1)(m)
+
+		default:
+			m.invalid(fmt.Errorf("unknown profile: %d", profile))
+		}
+	}
+}
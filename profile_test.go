@@ -0,0 +1,101 @@
+package gum
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProfiles(t *testing.T) {
+	tests := []struct {
+		profile   Profile
+		verbosity int
+		timeout   time.Duration
+	}{
+		{ProfileDev, logVerbose, 5 * time.Second},
+		{ProfileProd, logQuiet, time.Minute},
+		{ProfileTest, logQuiet, time.Second},
+	}
+
+	for _, tt := range tests {
+		manager := NewManager(WithProfile(tt.profile))
+		if manager.verbosity != tt.verbosity || manager.shutdownTimeout != tt.timeout {
+			t.Errorf("%s: unexpected verbosity %d and timeout %s", tt.profile, manager.verbosity, manager.shutdownTimeout)
+		}
+	}
+
+	// Options after the profile override the preset
+	manager := NewManager(WithProfile(ProfileProd), WithShutdownTimeout(time.Second))
+	if manager.shutdownTimeout != time.Second {
+		t.Errorf("expected option to override the profile, got %s", manager.shutdownTimeout)
+	}
+}
+
+func TestProdRuntimeMetrics(t *testing.T) {
+	manager := NewManager(WithProfile(ProfileProd), WithSampleInterval(time.Millisecond), WithSilent())
+	manager.AddUnit(&readyWorker{}, "")
+	quit := runAsync(manager)
+	defer func() {
+		manager.Stop()
+		<-quit
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for manager.Snapshot().Pressure == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the runtime pressure in the snapshot")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	var buf bytes.Buffer
+	if err := manager.WriteMetrics(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "\ngum_runtime_goroutines ") {
+		t.Fatalf("expected the runtime metrics, got:\n%s", buf.String())
+	}
+
+	if NewManager().Snapshot().Pressure != nil {
+		t.Fatal("expected no runtime metrics by default")
+	}
+}
+
+func TestProfileFromEnv(t *testing.T) {
+	t.Setenv("GUM_PROFILE", "test")
+
+	manager := NewManager(WithEnv(""))
+	if !manager.noSignals {
+		t.Fatal("expected signal handling to be disabled by the test profile")
+	}
+}
+
+func TestStrictMode(t *testing.T) {
+	var buf bytes.Buffer
+	manager := NewManager(WithProfile(ProfileDev), WithLogger(log.New(&buf, "", 0)))
+	manager.AddUnit(&stopWorker{}, "")
+
+	w := manager.order[0]
+	w.Done()
+	w.Done()
+	w.Ready()
+	w.Park(time.Time{})
+
+	for _, misuse := range []string{"called Done more than once", "called Ready after Done", "called Park after Done"} {
+		if !strings.Contains(buf.String(), misuse) {
+			t.Errorf("expected %q to be reported, got:\n%s", misuse, buf.String())
+		}
+	}
+}
+
+func TestQuietLogs(t *testing.T) {
+	var buf bytes.Buffer
+	manager := NewManager(WithProfile(ProfileProd), WithLogger(log.New(&buf, "", 0)))
+	manager.AddUnit(&stopWorker{}, "")
+
+	if strings.Contains(buf.String(), "Adding unit") {
+		t.Fatalf("expected unit lifecycle not to be logged, got:\n%s", buf.String())
+	}
+}
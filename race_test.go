@@ -0,0 +1,197 @@
+package gum
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// racingWorker calls Done as soon as go is closed, racing with the stop
+// requested by the shutdown. Done is called from several goroutines.
+type racingWorker struct {
+	start    chan struct{}
+	go_      chan struct{}
+	signaled atomic.Int32
+	onStop   atomic.Int32
+}
+
+func (w *racingWorker) Run(um UnitManager) {
+	um.OnStop(func() { w.onStop.Add(1) })
+	um.Ready()
+	w.start <- struct{}{}
+	<-w.go_
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			um.Done()
+		}()
+	}
+
+	select {
+	case <-um.ShouldStop():
+		w.signaled.Add(1)
+		um.Done()
+	case <-time.After(time.Millisecond):
+	}
+	wg.Wait()
+}
+
+// TestDoneRacingStop stresses units calling Done while the manager asks them
+// to stop: the shutdown must complete, each unit is drained once, and a unit
+// done first is never signaled nor has its OnStop callbacks run.
+func TestDoneRacingStop(t *testing.T) {
+	const rounds, units = 50, 16
+
+	for round := 0; round < rounds; round++ {
+		manager := NewManager(WithShutdownTimeout(5 * time.Second))
+		start := make(chan struct{}, units)
+		go_ := make(chan struct{})
+		workers := make([]*racingWorker, units)
+		for i := range workers {
+			workers[i] = &racingWorker{start: start, go_: go_}
+			manager.AddUnit(workers[i], "racer")
+		}
+
+		sub := manager.Subscribe(WithBufferSize(16 * units))
+		quit := runAsync(manager)
+		for range workers {
+			<-start
+		}
+
+		close(go_)
+		manager.Stop()
+		select {
+		case <-quit:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("round %d: shutdown stuck", round)
+		}
+		if err := manager.Err(); err != nil {
+			t.Fatalf("round %d: unexpected shutdown cause: %v", round, err)
+		}
+		sub.Close()
+
+		done := make(map[string]int)
+		stopping := make(map[string]int)
+		for ev := range sub.Events() {
+			switch ev.Kind {
+			case EventUnitDone:
+				done[ev.Unit]++
+			case EventUnitStopping:
+				stopping[ev.Unit]++
+			}
+		}
+
+		for i, w := range workers {
+			name := manager.order[i].name
+			if done[name] != 1 {
+				t.Fatalf("round %d: <%s> drained %d times", round, name, done[name])
+			}
+			if stopping[name] > 1 {
+				t.Fatalf("round %d: <%s> asked to stop %d times", round, name, stopping[name])
+			}
+			stopped := manager.order[i].Stopping()
+			if (stopping[name] == 1) != stopped {
+				t.Fatalf("round %d: <%s> stopping event %d, stopping %t", round, name, stopping[name], stopped)
+			}
+			if !stopped && (w.signaled.Load() != 0 || w.onStop.Load() != 0) {
+				t.Fatalf("round %d: <%s> done first but signaled to stop", round, name)
+			}
+			if w.onStop.Load() > 1 {
+				t.Fatalf("round %d: <%s> OnStop run %d times", round, name, w.onStop.Load())
+			}
+			if u, _ := manager.Status(name); u.State != Stopped {
+				t.Fatalf("round %d: <%s> left %s", round, name, u.State)
+			}
+		}
+	}
+}
+
+// TestDoneRacingRestart stresses units calling Done while being restarted:
+// each restart must start a single new instance.
+func TestDoneRacingRestart(t *testing.T) {
+	const rounds = 50
+
+	manager := NewManager()
+	start := make(chan struct{}, 1)
+	go_ := make(chan struct{})
+	close(go_)
+	manager.AddUnit(funcWorker(func(um UnitManager) {
+		um.Ready()
+		select {
+		case start <- struct{}{}:
+		case <-um.ShouldStop(): // Extra instance left once the rounds are over
+		}
+		select {
+		case <-um.ShouldStop():
+		case <-time.After(time.Duration(time.Now().UnixNano()%2) * time.Millisecond):
+		}
+		um.Done()
+	}), "", WithName("racer"), WithRestart(RestartAlways), WithRestartBackoff(time.Microsecond, time.Microsecond),
+		WithRestartIntensity(10*rounds, time.Minute))
+
+	quit := runAsync(manager)
+	for i := 0; i < rounds; i++ {
+		<-start
+		manager.RestartUnit("racer") // May lose to the restart after Done
+	}
+
+	manager.Stop()
+	select {
+	case <-quit:
+	case <-time.After(5 * time.Second):
+		t.Fatal("shutdown stuck")
+	}
+	if err := manager.Err(); err != nil {
+		t.Fatalf("unexpected shutdown cause: %v", err)
+	}
+
+	running := 0
+	for _, u := range manager.Snapshot().Units {
+		if u.State != Stopped {
+			running++
+		}
+	}
+	if running != 0 {
+		t.Fatalf("%d instances left running", running)
+	}
+}
+
+// TestStopAfterDone checks a stop requested once the unit is done is a
+// no-op, as when the unit wins the race with the shutdown.
+func TestStopAfterDone(t *testing.T) {
+	var onStop atomic.Int32
+	manager := NewManager()
+	manager.AddUnit(funcWorker(func(um UnitManager) {
+		um.OnStop(func() { onStop.Add(1) })
+		um.Ready()
+		um.Done()
+	}), "", WithName("done"))
+	manager.AddUnit(&readyWorker{}, "", WithName("other"))
+
+	sub := manager.Subscribe()
+	quit := runAsync(manager)
+	waitEvent(t, sub, EventStartupComplete)
+	waitState(t, manager, 0, Stopped)
+
+	w := manager.workers["done"]
+	manager.stopUnit(w)
+	if w.Stopping() || len(w.ShouldStop()) != 0 {
+		t.Fatal("expected the stop of a done unit to be a no-op")
+	}
+
+	manager.Stop()
+	<-quit
+	sub.Close()
+	for ev := range sub.Events() {
+		if ev.Kind == EventUnitStopping && ev.Unit == "done" {
+			t.Fatal("unexpected stopping event of a done unit")
+		}
+	}
+	if onStop.Load() != 0 {
+		t.Fatal("unexpected OnStop callback of a done unit")
+	}
+}
@@ -0,0 +1,92 @@
+package gum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// WithReadyTimeout fails the unit if it is not ready within d of its start,
+// see UnitManager.Ready. The failure is handled as a panic with
+// ErrReadyTimeout, subject to the start retry policy, see WithStartRetry.
+func WithReadyTimeout(d time.Duration) UnitOption {
+	return func(w *WorkUnitManager) {
+		if d <= 0 {
+			w.invalid(fmt.Errorf("invalid ready timeout %s", d))
+			return
+		}
+		w.readyTimeout = d
+	}
+}
+
+// WaitReady blocks until all the initial units are ready, done or disabled.
+// It returns ErrNotReady joined with the failure of the unit if a unit
+// failed instead, joined with the shutdown cause if the manager shuts down
+// first, or the context error. Units
+// added while running are not waited for.
+//
+//	go manager.Run()
+//	if err := manager.WaitReady(ctx); err != nil {
+//		t.Fatal(err)
+//	}
+func (m *Manager) WaitReady(ctx context.Context) error {
+	select {
+	case <-m.allReady:
+		return m.readyErr()
+	default:
+	}
+
+	select {
+	case <-m.allReady:
+		return m.readyErr()
+	case <-m.startStop:
+		return errors.Join(ErrNotReady, m.Err())
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// readyErr returns the failure of the first unit which failed instead of
+// being ready, and is not restarted.
+func (m *Manager) readyErr() error {
+	m.regMu.RLock()
+	defer m.regMu.RUnlock()
+
+	for _, w := range m.order {
+		if w.state == Failed && !w.restarting.Load() {
+			return errors.Join(ErrNotReady, fmt.Errorf("<%s>: %w", w.name, w.err))
+		}
+	}
+	return nil
+}
+
+// watchReady fails the unit if it is not ready within its ready timeout.
+func (m *Manager) watchReady(w *WorkUnitManager) {
+	timer := time.NewTimer(w.readyTimeout)
+	defer timer.Stop()
+
+	for {
+		m.regMu.Lock()
+		ready := w.ready
+		changed := m.changedC()
+		m.regMu.Unlock()
+
+		if ready || w.done.Load() || w.Stopping() {
+			return
+		}
+
+		select {
+		case <-changed:
+		case <-timer.C:
+			m.regMu.RLock()
+			ready = w.ready
+			m.regMu.RUnlock()
+			if !ready && !w.Stopping() {
+				m.warnf(w.name, "<%s> not ready within %s\n", w, w.readyTimeout)
+				w.Panic(fmt.Errorf("%w: not ready within %s", ErrReadyTimeout, w.readyTimeout))
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package gum
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWaitReady(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&depWorker{name: "slow", delay: 20 * time.Millisecond, log: &teardownLog{}}, "")
+	go manager.Run()
+	defer func() {
+		manager.Stop()
+		<-manager.Quit
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := manager.WaitReady(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !manager.Snapshot().Units[0].Ready {
+		t.Fatal("expected the unit to be ready")
+	}
+}
+
+func TestWaitReadyTimeout(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&stopWorker{}, "", WithReadyTimeout(10*time.Millisecond))
+	go manager.Run()
+
+	err := manager.WaitReady(context.Background())
+	if !errors.Is(err, ErrNotReady) || !errors.Is(err, ErrReadyTimeout) {
+		t.Fatalf("expected the unit to fail to be ready, got %v", err)
+	}
+	<-manager.Quit
+}
+
+func TestWaitReadyContext(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&stopWorker{}, "")
+	go manager.Run()
+	defer func() {
+		manager.Stop()
+		<-manager.Quit
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := manager.WaitReady(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the context deadline, got %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package gum
+
+import (
+	"errors"
+	"fmt"
+)
+
+// StopReason tells why the manager stopped, see Manager.StopReason.
+type StopReason int
+
+const (
+	// ReasonNone is the reason of a manager which did not stop yet.
+	ReasonNone StopReason = iota
+	// ReasonSignal is a shutdown signal, see ShutdownOn.
+	ReasonSignal
+	// ReasonShutdownCall is a call to Stop or Shutdown.
+	ReasonShutdownCall
+	// ReasonPanic is a unit whose Run panicked, see PanicError.
+	ReasonPanic
+	// ReasonUnitError is a unit which called Panic or failed for good.
+	ReasonUnitError
+	// ReasonStartup is a startup failure: invalid configuration, missing
+	// resources or startup timeout.
+	ReasonStartup
+)
+
+var stopReasonNames = [...]string{"none", "signal", "shutdown-call", "panic", "unit-error", "startup"}
+
+func (r StopReason) String() string {
+	if r < 0 || int(r) >= len(stopReasonNames) {
+		return fmt.Sprintf("StopReason(%d)", int(r))
+	}
+	return stopReasonNames[r]
+}
+
+// StopReason returns why the manager stopped, ReasonNone while it runs. It
+// complements the shutdown cause returned by Run and Wait, which is nil for
+// both a signal and a call to Stop.
+func (m *Manager) StopReason() StopReason {
+	return StopReason(m.reason.Load())
+}
+
+// setReason records the reason of the shutdown, the first one wins.
+func (m *Manager) setReason(r StopReason) {
+	m.reason.CompareAndSwap(int32(ReasonNone), int32(r))
+}
+
+// panicReason returns ReasonPanic if one of the failures is a panic of Run,
+// ReasonUnitError otherwise.
+func panicReason(panics []unitPanic) StopReason {
+	for _, p := range panics {
+		var pe *PanicError
+		if errors.As(p.err, &pe) {
+			return ReasonPanic
+		}
+	}
+	return ReasonUnitError
+}
+
+// WithReasonExitCode sets the process exit code returned by
+// Manager.ExitCode when the manager stopped for the reason. It takes
+// precedence over the codes matched on the shutdown cause, see WithExitCode.
+func WithReasonExitCode(reason StopReason, code int) Option {
+	return func(m *Manager) {
+		if reason <= ReasonNone || int(reason) >= len(stopReasonNames) {
+			m.invalid(fmt.Errorf("exit code %d for unknown stop reason: %d", code, reason))
+			return
+		}
+		if m.reasonCodes == nil {
+			m.reasonCodes = make(map[StopReason]int)
+		}
+		m.reasonCodes[reason] = code
+	}
+}
+
+// RunAndExit runs the manager and terminates the process with its exit
+// code once it quit, see Manager.ExitCode.
+func RunAndExit(m *Manager) {
+	m.Run()
+	code := m.ExitCode()
+	m.logf("Exiting with code %d (%s)\n", code, m.StopReason())
+	exit(code)
+}
@@ -0,0 +1,76 @@
+package gum
+
+import (
+	"syscall"
+	"testing"
+)
+
+func TestStopReason(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		unit   WorkUnit
+		stop   func(t *testing.T, m *Manager)
+		reason StopReason
+		// Failing units may stop the manager before the startup completes
+		started bool
+	}{
+		{"signal", &readyWorker{}, func(t *testing.T, m *Manager) {
+			if m.StopReason() != ReasonNone {
+				t.Fatalf("unexpected reason %s before the shutdown", m.StopReason())
+			}
+			m.signalIn <- syscall.SIGTERM
+		}, ReasonSignal, true},
+		{"stop", &readyWorker{}, func(_ *testing.T, m *Manager) { m.Stop() }, ReasonShutdownCall, true},
+		{"unit error", &panicWorker{}, func(*testing.T, *Manager) {}, ReasonUnitError, false},
+		{"panic", funcWorker(func(UnitManager) { panic("boom") }), func(*testing.T, *Manager) {}, ReasonPanic, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := NewManager()
+			manager.ShutdownOn(syscall.SIGTERM)
+			manager.AddUnit(tt.unit, "")
+
+			sub := manager.Subscribe()
+			quit := runAsync(manager)
+			if tt.started {
+				waitEvent(t, sub, EventStartupComplete)
+			}
+			tt.stop(t, manager)
+			<-quit
+
+			if manager.StopReason() != tt.reason {
+				t.Fatalf("expected reason %s, got %s", tt.reason, manager.StopReason())
+			}
+		})
+	}
+
+	manager := NewManager(WithStartupTimeout(-1))
+	manager.Run()
+	if manager.StopReason() != ReasonStartup {
+		t.Fatalf("expected reason %s, got %s", ReasonStartup, manager.StopReason())
+	}
+}
+
+func TestRunAndExit(t *testing.T) {
+	var code int
+	exit = func(c int) { code = c }
+	defer func() { exit = osExit }()
+
+	manager := NewManager(WithReasonExitCode(ReasonUnitError, 70))
+	manager.AddUnit(&panicWorker{}, "")
+	RunAndExit(manager)
+	if code != 70 {
+		t.Fatalf("expected exit code 70, got %d", code)
+	}
+
+	// Without a reason code, the code matches the cause
+	manager = NewManager()
+	manager.AddUnit(&panicWorker{}, "")
+	RunAndExit(manager)
+	if code != ExitPanic {
+		t.Fatalf("expected exit code %d, got %d", ExitPanic, code)
+	}
+
+	if err := NewManager(WithReasonExitCode(ReasonNone, 1)).Validate(); err == nil {
+		t.Fatal("expected an exit code for no reason to be reported")
+	}
+}
@@ -0,0 +1,106 @@
+package gum
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultMaxUnavailable is the default number of units recycled at the same
+// time.
+const DefaultMaxUnavailable = 1
+
+// WithRecycle periodically replaces the unit by a fresh instance returned by
+// newUnit, to combat slow leaks in long-lived units. The unit is recycled
+// every interval plus a random jitter, so units added together don't recycle
+// at the same time. Recycling uses SwapUnit: the new instance is started and
+// the old one is stopped once the new one is ready. A new instance which is
+// not ready within the interval is stopped and recycling is retried later.
+func WithRecycle(every, jitter time.Duration, newUnit func() WorkUnit) UnitOption {
+	return func(w *WorkUnitManager) {
+		if every <= 0 {
+			w.invalid(fmt.Errorf("invalid recycle interval %s", every))
+			return
+		}
+		if jitter < 0 {
+			w.invalid(fmt.Errorf("negative recycle jitter %s", jitter))
+			return
+		}
+		if newUnit == nil {
+			w.invalid(fmt.Errorf("nil recycle unit constructor"))
+			return
+		}
+		w.recycleEvery = every
+		w.recycleJitter = jitter
+		w.recycleNew = newUnit
+	}
+}
+
+// WithMaxUnavailable bounds the number of units being recycled at the same
+// time. It defaults to DefaultMaxUnavailable.
+func WithMaxUnavailable(n int) Option {
+	return func(m *Manager) {
+		if n < 1 {
+			m.invalid(fmt.Errorf("invalid max unavailable %d", n))
+			return
+		}
+		m.maxUnavailable = n
+	}
+}
+
+// recycle replaces the unit once its recycle interval elapsed. It returns
+// once the unit was replaced, the new unit recycling itself, or when the unit
+// or the manager is stopping.
+func (m *Manager) recycle(w *WorkUnitManager) {
+	for {
+		d := w.recycleEvery + m.jitter(w.recycleJitter)
+
+		timer := time.NewTimer(d)
+		select {
+		case <-timer.C:
+		case <-m.startStop:
+			timer.Stop()
+			return
+		}
+
+		if w.Stopping() || w.done.Load() {
+			return
+		}
+
+		trace := m.newTraceID()
+		m.setTrace(w, trace)
+
+		decision := m.restartDecision(w)
+		if decision.Veto {
+			m.unitLogf(w.name, "Recycling <%s> vetoed: %s (trace %s)\n", w, decision.Reason, trace)
+			continue
+		}
+		if decision.Delay > 0 {
+			m.unitLogf(w.name, "Recycling <%s> delayed by %s: %s (trace %s)\n", w, decision.Delay, decision.Reason, trace)
+			timer := time.NewTimer(decision.Delay)
+			select {
+			case <-timer.C:
+			case <-m.startStop:
+				timer.Stop()
+				return
+			}
+		}
+
+		select {
+		case m.recycleSem <- struct{}{}:
+		case <-m.startStop:
+			return
+		}
+
+		m.unitLogf(w.name, "Recycling <%s> (trace %s)\n", w, trace)
+		ctx, cancel := context.WithTimeout(context.Background(), w.recycleEvery)
+		_, err := m.swapUnit(ctx, w.name, trace, w.recycleNew())
+		cancel()
+		<-m.recycleSem
+
+		if err == nil {
+			return
+		}
+		m.warnf(w.name, "Could not recycle <%s>: %s\n", w, err)
+	}
+}
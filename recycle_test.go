@@ -0,0 +1,62 @@
+package gum
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecycle(t *testing.T) {
+	manager := NewManager()
+	newUnit := func() WorkUnit { return &readyWorker{} }
+	manager.AddUnit(newUnit(), "", WithRecycle(20*time.Millisecond, 0, newUnit))
+	sub := manager.Subscribe()
+
+	go manager.Run()
+
+	// The unit and its replacements are recycled in turn
+	for started := 0; started < 3; {
+		select {
+		case ev := <-sub.Events():
+			if ev.Kind == EventUnitStarted {
+				started++
+			}
+		case <-time.After(time.Second):
+			t.Fatal("unit was not recycled")
+		}
+	}
+
+	manager.Stop()
+	<-manager.Quit
+
+	for _, u := range manager.Snapshot().Units {
+		if u.State != Stopped {
+			t.Fatalf("expected all instances to be stopped, got %s for <%s>", u.State, u.Name)
+		}
+	}
+}
+
+func TestRecycleNotReady(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&readyWorker{}, "", WithRecycle(10*time.Millisecond, 0, func() WorkUnit {
+		return &blockedWorker{}
+	}))
+
+	go manager.Run()
+	time.Sleep(50 * time.Millisecond)
+
+	if state := manager.Snapshot().Units[0].State; state != Running {
+		t.Fatalf("expected the unit to keep running, got %s", state)
+	}
+
+	manager.Stop()
+	<-manager.Quit
+}
+
+func TestRecycleInvalid(t *testing.T) {
+	manager := NewManager(WithMaxUnavailable(0))
+	manager.AddUnit(&readyWorker{}, "", WithRecycle(0, 0, nil))
+
+	if err := manager.Validate(); err == nil {
+		t.Fatal("expected invalid recycle options")
+	}
+}
@@ -0,0 +1,109 @@
+package gum
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultReloadTimeout is the default time given to units to reload, see
+// WithReloadTimeout.
+const DefaultReloadTimeout = 30 * time.Second
+
+// Reloader is implemented by units able to reload their configuration
+// without being restarted, see ReloadOn.
+type Reloader interface {
+	Reload() error
+}
+
+// ReloadOn registers signals triggering a reload instead of a shutdown, e.g.
+// SIGHUP: the running units implementing Reloader are reloaded, see Reload.
+func (m *Manager) ReloadOn(sig ...os.Signal) {
+	for _, s := range sig {
+		m.logf("Registering reload signal: %s\n", s)
+		m.notify(s)
+	}
+
+	m.reloadSigs = append(m.reloadSigs, sig...)
+}
+
+// WithReloadTimeout sets the time given to each unit to reload. A unit
+// exceeding it is left reloading on its own and reported as failed with
+// ErrReloadTimeout.
+func WithReloadTimeout(d time.Duration) Option {
+	return func(m *Manager) {
+		if d <= 0 {
+			m.invalid(fmt.Errorf("invalid reload timeout: %s", d))
+			return
+		}
+		m.reloadTimeout = d
+	}
+}
+
+// Reload calls Reload concurrently on the running units implementing
+// Reloader and waits for them within the reload timeout. Each reload is
+// published as an EventUnitReloaded event carrying its error, if any, and
+// the errors are returned joined. A failed reload is not a unit failure: the
+// unit keeps running with its previous configuration.
+func (m *Manager) Reload() error {
+	m.reloadMu.Lock()
+	defer m.reloadMu.Unlock()
+
+	m.regMu.RLock()
+	var units []*WorkUnitManager
+	for _, w := range m.order {
+		if _, ok := w.unit.(Reloader); ok && w.started.Load() && !w.done.Load() && !w.Stopping() {
+			units = append(units, w)
+		}
+	}
+	m.regMu.RUnlock()
+
+	m.logf("reloading %d units ...\n", len(units))
+
+	errs := make([]error, len(units))
+	var wg sync.WaitGroup
+	for i, w := range units {
+		wg.Add(1)
+		go func(i int, w *WorkUnitManager) {
+			defer wg.Done()
+			errs[i] = m.reloadUnit(w)
+		}(i, w)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// reloadUnit reloads the unit within the reload timeout.
+func (m *Manager) reloadUnit(w *WorkUnitManager) error {
+	done := make(chan error, 1) // Left running on timeout
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("reload panic: %v", r)
+			}
+		}()
+		done <- w.unit.(Reloader).Reload()
+	}()
+
+	timer := time.NewTimer(m.reloadTimeout)
+	defer timer.Stop()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-timer.C:
+		err = fmt.Errorf("%w: did not reload within %s", ErrReloadTimeout, m.reloadTimeout)
+	}
+
+	if err != nil {
+		m.warnf(w.name, "<%s> failed to reload\n", w)
+		err = fmt.Errorf("reload <%s>: %w", w.name, err)
+	} else {
+		m.unitLogf(w.name, "<%s> reloaded\n", w)
+	}
+	m.emitEvent(Event{Kind: EventUnitReloaded, Unit: w.name, Err: err})
+	return err
+}
@@ -0,0 +1,130 @@
+package gum
+
+import (
+	"errors"
+	"log"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+type reloadWorker struct {
+	readyWorker
+	reloads atomic.Int32
+	err     error
+	block   chan struct{}
+}
+
+func (w *reloadWorker) Reload() error {
+	w.reloads.Add(1)
+	if w.block != nil {
+		<-w.block
+	}
+	return w.err
+}
+
+func TestReloadOn(t *testing.T) {
+	ok := &reloadWorker{}
+	failing := &reloadWorker{err: errors.New("bad config")}
+	manager := NewManager()
+	manager.ReloadOn(syscall.SIGHUP)
+	manager.AddUnit(ok, "", WithName("ok"))
+	manager.AddUnit(failing, "", WithName("failing"))
+	manager.AddUnit(&readyWorker{}, "", WithName("plain"))
+
+	sub := manager.Subscribe()
+	quit := runAsync(manager)
+	waitEvent(t, sub, EventStartupComplete)
+
+	manager.signalIn <- syscall.SIGHUP
+	reloaded := map[string]error{}
+	for len(reloaded) < 2 {
+		ev := waitEvent(t, sub, EventUnitReloaded)
+		reloaded[ev.Unit] = ev.Err
+	}
+	if reloaded["ok"] != nil || reloaded["failing"] == nil {
+		t.Fatalf("unexpected reloads %v", reloaded)
+	}
+	if ok.reloads.Load() != 1 || failing.reloads.Load() != 1 {
+		t.Fatalf("expected a single reload of each unit")
+	}
+	if u, _ := manager.Status("failing"); u.State != Running {
+		t.Fatalf("expected the unit failing to reload to keep running, got %s", u.State)
+	}
+
+	manager.Stop()
+	<-quit
+	if manager.Err() != nil {
+		t.Fatalf("unexpected shutdown cause: %v", manager.Err())
+	}
+}
+
+func TestReloadTimeout(t *testing.T) {
+	stuck := &reloadWorker{block: make(chan struct{})}
+	defer close(stuck.block)
+
+	manager := NewManager(WithReloadTimeout(10 * time.Millisecond))
+	manager.AddUnit(stuck, "", WithName("stuck"))
+
+	sub := manager.Subscribe()
+	quit := runAsync(manager)
+	waitEvent(t, sub, EventStartupComplete)
+
+	if err := manager.Reload(); !errors.Is(err, ErrReloadTimeout) {
+		t.Fatalf("expected a reload timeout, got %v", err)
+	}
+
+	manager.Stop()
+	<-quit
+
+	if err := NewManager(WithReloadTimeout(0)).Validate(); err == nil {
+		t.Fatal("expected an invalid reload timeout to be reported")
+	}
+}
+
+func TestReloadWhileAdding(t *testing.T) {
+	// Slow logs widen the window between the registration and the start
+	slow := funcWriter(func(p []byte) (int, error) {
+		time.Sleep(50 * time.Microsecond)
+		return len(p), nil
+	})
+	manager := NewManager(WithLogger(log.New(slow, "", 0)))
+	manager.AddUnit(&reloadWorker{}, "reload")
+
+	sub := manager.Subscribe()
+	quit := runAsync(manager)
+	waitEvent(t, sub, EventStartupComplete)
+	sub.Close()
+
+	// Units started at runtime race with the reloads, see go test -race
+	added := make(chan struct{})
+	go func() {
+		defer close(added)
+		for i := 0; i < 30; i++ {
+			manager.AddUnit(&reloadWorker{}, "reload")
+		}
+	}()
+	for reloading := true; reloading; {
+		select {
+		case <-added:
+			reloading = false
+		default:
+		}
+		if err := manager.Reload(); err != nil {
+			t.Fatalf("unexpected reload error: %v", err)
+		}
+	}
+
+	manager.Stop()
+	<-quit
+	if manager.Err() != nil {
+		t.Fatalf("unexpected shutdown cause: %v", manager.Err())
+	}
+}
+
+type funcWriter func(p []byte) (int, error)
+
+func (f funcWriter) Write(p []byte) (int, error) {
+	return f(p)
+}
@@ -0,0 +1,201 @@
+package gum
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultPollInterval is the default interval at which a Remote polls the
+// status of the remote manager.
+const DefaultPollInterval = time.Second
+
+// remoteMaxFailures is the number of consecutive failed polls after which the
+// remote manager is considered lost.
+const remoteMaxFailures = 3
+
+// Remote is a unit supervising the manager of another process through the
+// HTTP control protocol of its ControlHandler, gum has no gRPC dependency.
+// It is ready once the remote manager is reachable, mirrors its status in
+// the Remotes of the local Snapshot, and stops the remote manager when asked
+// to stop. Losing the remote manager is reported with Panic.
+type Remote struct {
+	url      string
+	client   *http.Client
+	interval time.Duration
+
+	mu   sync.Mutex
+	snap Snapshot
+	ok   bool // Got a snapshot
+}
+
+// RemoteOption configures a Remote.
+type RemoteOption func(*Remote)
+
+// WithPollInterval sets the interval at which the remote status is polled.
+func WithPollInterval(d time.Duration) RemoteOption {
+	return func(r *Remote) {
+		r.interval = d
+	}
+}
+
+// WithHTTPClient sets the client used to reach the remote manager, e.g. to
+// configure TLS.
+func WithHTTPClient(c *http.Client) RemoteOption {
+	return func(r *Remote) {
+		r.client = c
+	}
+}
+
+// RemoteManager returns a unit supervising the remote manager whose
+// ControlHandler is served at url.
+func RemoteManager(url string, opts ...RemoteOption) *Remote {
+	r := &Remote{
+		url:      strings.TrimSuffix(url, "/"),
+		client:   http.DefaultClient,
+		interval: DefaultPollInterval,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Describe implements Describer.
+func (r *Remote) Describe() string {
+	return "remote manager at " + r.url
+}
+
+// Run implements WorkUnit.
+func (r *Remote) Run(um UnitManager) {
+	timer := time.NewTimer(0) // Poll right away
+	defer timer.Stop()
+
+	failures := 0
+	for {
+		select {
+		case <-timer.C:
+			ctx, cancel := context.WithTimeout(context.Background(), r.interval)
+			_, err := r.poll(ctx)
+			cancel()
+			timer.Reset(r.interval)
+
+			if err != nil {
+				failures++
+				if failures >= remoteMaxFailures {
+					um.Panic(fmt.Errorf("lost remote manager at %s: %w", r.url, err))
+					return
+				}
+				continue
+			}
+			failures = 0
+			um.Ready()
+
+		case <-um.ShouldStop():
+			if err := r.stop(um.ShutdownContext(), um.ShutdownMode()); err != nil {
+				um.Panic(fmt.Errorf("stopping remote manager at %s: %w", r.url, err))
+				return
+			}
+			um.Done()
+			return
+		}
+	}
+}
+
+// RemoteSnapshot returns the last status of the remote manager, ok is false
+// until the remote manager was reached.
+func (r *Remote) RemoteSnapshot() (snap Snapshot, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.snap, r.ok
+}
+
+// poll fetches the remote status.
+func (r *Remote) poll(ctx context.Context) (Snapshot, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url+"/status", nil)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Snapshot{}, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	var ws wireSnapshot
+	if err := json.NewDecoder(resp.Body).Decode(&ws); err != nil {
+		return Snapshot{}, err
+	}
+	snap, err := ws.snapshot()
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	r.mu.Lock()
+	r.snap, r.ok = snap, true
+	r.mu.Unlock()
+
+	return snap, nil
+}
+
+// stop stops the remote manager and, on graceful shutdowns, waits for its
+// units to be stopped or for the remote manager to be gone.
+func (r *Remote) stop(ctx context.Context, mode ShutdownMode) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url+"/stop", nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	if mode == Immediate {
+		return nil
+	}
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		snap, err := r.poll(ctx)
+		if err != nil || remoteStopped(snap) {
+			// The remote manager exits once stopped
+			return nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil // Past the shutdown budget, the unit is abandoned
+		}
+	}
+}
+
+// remoteStopped tells if all the units of the remote manager are done.
+func remoteStopped(snap Snapshot) bool {
+	for _, u := range snap.Units {
+		if u.State != Stopped && u.State != Failed && u.State != Disabled {
+			return false
+		}
+	}
+	return true
+}
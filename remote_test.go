@@ -0,0 +1,70 @@
+package gum
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRemoteManager(t *testing.T) {
+	child := NewManager()
+	child.AddUnit(&readyWorker{}, "")
+	srv := httptest.NewServer(child.ControlHandler())
+	defer srv.Close()
+	go child.Run()
+
+	parent := NewManager()
+	parent.AddUnit(RemoteManager(srv.URL, WithPollInterval(5*time.Millisecond)), "child")
+	go parent.Run()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		snap := parent.Snapshot()
+		remote, ok := snap.Remotes[parent.order[0].name]
+		if snap.Units[0].Ready && ok && len(remote.Units) == 1 && remote.Units[0].State == Running {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("remote status not aggregated: %+v", snap)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// Shutdown is propagated to the child
+	parent.Stop()
+	<-parent.Quit
+
+	select {
+	case <-child.Quit:
+	case <-time.After(time.Second):
+		t.Fatal("child manager was not stopped")
+	}
+	if err := parent.Err(); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+}
+
+func TestRemoteManagerLost(t *testing.T) {
+	srv := httptest.NewServer(nil)
+	url := srv.URL
+	srv.Close()
+
+	parent := NewManager()
+	parent.AddUnit(RemoteManager(url, WithPollInterval(time.Millisecond)), "child")
+
+	done := make(chan bool)
+	go func() {
+		parent.Run()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("lost remote manager was not reported")
+	}
+	if !errors.Is(parent.Err(), ErrUnitPanic) {
+		t.Fatalf("expected a unit panic, got %v", parent.Err())
+	}
+}
@@ -0,0 +1,70 @@
+package gum
+
+import "fmt"
+
+// WithEventHistory keeps the last n published events, replayed to the
+// subscribers created with WithReplay.
+func WithEventHistory(n int) Option {
+	return func(m *Manager) {
+		if n < 0 {
+			m.invalid(fmt.Errorf("negative event history: %d", n))
+			return
+		}
+		m.events.history = make([]Event, n)
+	}
+}
+
+// WithReplay makes the subscriber receive a summary of the manager before the
+// live events: an EventReplay event carrying the status of the manager and
+// all its units, followed by the recent events kept with WithEventHistory,
+// flagged as Replayed. Late subscribers such as dashboards and controllers
+// don't need a separate call to bootstrap their state. The buffer of the
+// subscription is grown to fit the replay.
+//
+// The status is taken right before the recent events are replayed: it may
+// already reflect some of them, applying them again must be harmless.
+func WithReplay() SubscribeOption {
+	return func(s *Subscription) {
+		s.replay = true
+	}
+}
+
+// record appends the event to the history. It is called with mu held for
+// reading, subscribe holds it for writing while replaying the history.
+func (b *eventBus) record(ev Event) {
+	if len(b.history) == 0 {
+		return
+	}
+
+	b.historyMu.Lock()
+	b.history[b.next] = ev
+	b.next = (b.next + 1) % len(b.history)
+	b.full = b.full || b.next == 0
+	b.historyMu.Unlock()
+}
+
+// recent returns the history, oldest first.
+func (b *eventBus) recent() []Event {
+	b.historyMu.Lock()
+	defer b.historyMu.Unlock()
+
+	if !b.full {
+		return append([]Event(nil), b.history[:b.next]...)
+	}
+	return append(append([]Event(nil), b.history[b.next:]...), b.history[:b.next]...)
+}
+
+// replayEvent returns the EventReplay event carrying the status of the
+// manager.
+func (m *Manager) replayEvent() Event {
+	snap := m.Snapshot()
+	ev := Event{
+		Kind:     EventReplay,
+		Time:     m.clock.Now(),
+		Uptime:   snap.Uptime,
+		Snapshot: &snap,
+		Baggage:  m.baggage,
+	}
+	ev.Severity = eventSeverity(ev)
+	return ev
+}
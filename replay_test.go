@@ -0,0 +1,66 @@
+package gum
+
+import "testing"
+
+func TestReplay(t *testing.T) {
+	manager := NewManager(WithEventHistory(4))
+	manager.AddUnit(&readyWorker{}, "", WithName("a"))
+	manager.AddUnit(&readyWorker{}, "", WithName("b"))
+
+	sub := manager.Subscribe()
+	quit := runAsync(manager)
+	waitEvent(t, sub, EventStartupComplete)
+
+	late := manager.Subscribe(WithReplay(), WithBufferSize(1))
+	ev := <-late.Events()
+	if ev.Kind != EventReplay || ev.Snapshot == nil || len(ev.Snapshot.Units) != 2 {
+		t.Fatalf("expected the status first, got %v", ev)
+	}
+	if u := ev.Snapshot.Units[1]; u.Name != "b" || u.State != Running {
+		t.Fatalf("unexpected unit status %+v", u)
+	}
+
+	// The last 4 events, ending with the startup completion
+	var replayed []Event
+	for i := 0; i < 4; i++ {
+		replayed = append(replayed, <-late.Events())
+	}
+	for _, ev := range replayed {
+		if !ev.Replayed {
+			t.Fatalf("expected %v to be flagged as replayed", ev)
+		}
+	}
+	if last := replayed[len(replayed)-1]; last.Kind != EventStartupComplete {
+		t.Fatalf("expected the startup completion last, got %v", last)
+	}
+
+	// Followed by the live events
+	manager.Stop()
+	if ev := <-late.Events(); ev.Kind != EventShutdown || ev.Replayed {
+		t.Fatalf("expected the live shutdown event, got %v", ev)
+	}
+	<-quit
+
+	// Without history, only the status is replayed
+	manager = NewManager()
+	manager.AddUnit(&readyWorker{}, "")
+	sub = manager.Subscribe()
+	quit = runAsync(manager)
+	waitEvent(t, sub, EventStartupComplete)
+
+	late = manager.Subscribe(WithReplay())
+	if ev := <-late.Events(); ev.Kind != EventReplay {
+		t.Fatalf("expected the status first, got %v", ev)
+	}
+	manager.Stop()
+	if ev := <-late.Events(); ev.Kind != EventShutdown {
+		t.Fatalf("expected the live shutdown event, got %v", ev)
+	}
+	<-quit
+}
+
+func TestEventHistoryValidate(t *testing.T) {
+	if err := NewManager(WithEventHistory(-1)).Validate(); err == nil {
+		t.Fatal("expected a negative event history to be reported")
+	}
+}
@@ -0,0 +1,140 @@
+package gum
+
+import (
+	"fmt"
+	"time"
+)
+
+// StartupReport describes how the units were brought up by Run. It is
+// published with the EventStartupComplete event once all units are ready,
+// done or disabled, and is available from then on in Snapshot.
+//
+// When the startup timeout elapses first, see WithStartupTimeout, the report
+// is published with the startup error and TimedOut is set.
+type StartupReport struct {
+	Time     time.Time     // Startup completion
+	Duration time.Duration // From Run to the startup completion
+	Units    []StartupUnit // In start order
+
+	TimedOut bool
+	Blocking string // First unit in registration order not ready on timeout
+}
+
+// StartupUnit describes the startup of a unit.
+type StartupUnit struct {
+	Name  string
+	Order int // Registration order
+
+	// Skipped units were not started, e.g. disabled by a FlagProvider.
+	Skipped bool
+
+	// StartedAfter is the time from Run to the unit being started, including
+	// the wait for a startup slot, see WithStartupConcurrency.
+	StartedAfter time.Duration
+
+	// ReadyAfter is the time from the unit being started to it calling
+	// Ready. It is zero for units which were done without being ready.
+	ReadyAfter time.Duration
+}
+
+// reportStartup publishes the startup report once all units are ready. It
+// gives up when the manager shuts down first, and aborts the startup when the
+// startup timeout elapses first.
+func (m *Manager) reportStartup() {
+	defer close(m.reportDone)
+
+	var timeout <-chan time.Time
+	if m.startupTimeout > 0 {
+		timer := time.NewTimer(m.startupTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case <-m.allReady:
+	case <-timeout:
+		m.abortStartup()
+		return
+	case <-m.startStop:
+		return
+	}
+
+	m.regMu.Lock()
+	report := m.buildStartupReport()
+	m.startup = report
+	m.touch()
+	m.regMu.Unlock()
+
+	m.lifecycle.CompareAndSwap(lifecycleStartup, lifecycleRunning)
+	m.logf("Startup complete in %s\n", report.Duration)
+	m.emitEvent(Event{Kind: EventStartupComplete, Startup: report})
+}
+
+// abortStartup publishes the report of a timed out startup and shuts down the
+// manager, stopping the started units in reverse order.
+func (m *Manager) abortStartup() {
+	m.regMu.Lock()
+	report := m.buildStartupReport()
+	report.TimedOut = true
+	for _, w := range m.order {
+		if !w.settled.Load() {
+			report.Blocking = w.name
+			break
+		}
+	}
+	m.startup = report
+	m.touch()
+	m.regMu.Unlock()
+
+	err := fmt.Errorf("%w: %w after %s, blocked by <%s>",
+		ErrStartup, ErrStartupTimeout, m.startupTimeout, report.Blocking)
+	m.warnf("", "%s\n", err)
+	m.addErr(err)
+	m.emitEvent(Event{Kind: EventStartupComplete, Startup: report, Err: err})
+
+	m.rollingBack.Store(true)
+	close(m.abortC)
+}
+
+// rollbackNext asks the first unit of the rollback queue still running to
+// stop, and returns the queue starting at this unit.
+func (m *Manager) rollbackNext(queue []*WorkUnitManager) []*WorkUnitManager {
+	for len(queue) > 0 {
+		w := queue[0]
+		if !w.done.Load() {
+			m.unitLogf(w.name, "rolling back <%s>\n", w)
+			m.stopUnit(w)
+			return queue
+		}
+		queue = queue[1:]
+	}
+	return nil
+}
+
+// buildStartupReport returns the startup report as of now. regMu must be held.
+func (m *Manager) buildStartupReport() *StartupReport {
+	now := m.clock.Now()
+	report := &StartupReport{
+		Time:     now,
+		Duration: now.Sub(m.startedAt),
+		Units:    make([]StartupUnit, 0, len(m.order)),
+	}
+
+	var skipped []StartupUnit
+	for i, w := range m.order {
+		u := StartupUnit{Name: w.name, Order: i}
+
+		switch {
+		case w.startedAt.IsZero():
+			u.Skipped = true
+			skipped = append(skipped, u)
+			continue
+		case !w.readyAt.IsZero():
+			u.ReadyAfter = w.readyAt.Sub(w.startedAt)
+		}
+		u.StartedAfter = w.startedAt.Sub(m.startedAt)
+		report.Units = append(report.Units, u)
+	}
+	report.Units = append(report.Units, skipped...)
+	return report
+}
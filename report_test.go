@@ -0,0 +1,117 @@
+package gum
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// lateReadyWorker takes some time to be ready
+type lateReadyWorker struct{}
+
+func (w *lateReadyWorker) Run(um UnitManager) {
+	time.Sleep(10 * time.Millisecond)
+	um.Ready()
+	<-um.ShouldStop()
+	um.Done()
+}
+
+func TestStartupReport(t *testing.T) {
+	flags := FlagFunc(func(unit UnitInfo) bool { return unit.Labels["skip"] == "" })
+	manager := NewManager(WithFlagProvider(flags))
+	manager.AddUnit(&readyWorker{}, "", WithLabels(map[string]string{"skip": "yes"}))
+	manager.AddUnit(&lateReadyWorker{}, "")
+	manager.AddUnit(&readyWorker{}, "")
+	sub := manager.Subscribe()
+
+	if manager.Snapshot().Startup != nil {
+		t.Fatal("expected no startup report before Run")
+	}
+
+	go manager.Run()
+	defer func() {
+		manager.Stop()
+		<-manager.Quit
+	}()
+
+	var report *StartupReport
+	for report == nil {
+		select {
+		case ev := <-sub.Events():
+			if ev.Kind == EventStartupComplete {
+				report = ev.Startup
+			}
+		case <-time.After(time.Second):
+			t.Fatal("startup report not published")
+		}
+	}
+
+	if manager.Snapshot().Startup != report {
+		t.Fatal("expected the startup report in the snapshot")
+	}
+	if len(report.Units) != 3 {
+		t.Fatalf("expected 3 units in report, got %+v", report.Units)
+	}
+
+	// Started units come first in start order, then skipped ones
+	late, ready, skipped := report.Units[0], report.Units[1], report.Units[2]
+	if late.Order != 1 || ready.Order != 2 || skipped.Order != 0 || !skipped.Skipped {
+		t.Fatalf("unexpected order %+v", report.Units)
+	}
+	if late.ReadyAfter < 10*time.Millisecond {
+		t.Errorf("expected readiness wait of at least 10ms, got %s", late.ReadyAfter)
+	}
+	if report.Duration < late.ReadyAfter {
+		t.Errorf("expected startup duration %s to include the readiness wait", report.Duration)
+	}
+}
+
+// stopOrderWorker records when it is stopped, and is ready unless blocked
+type stopOrderWorker struct {
+	log     *teardownLog
+	name    string
+	blocked bool
+}
+
+func (w *stopOrderWorker) Run(um UnitManager) {
+	if !w.blocked {
+		um.Ready()
+	}
+	<-um.ShouldStop()
+	w.log.add(w.name)
+	um.Done()
+}
+
+func TestStartupTimeout(t *testing.T) {
+	l := &teardownLog{}
+	manager := NewManager(WithStartupTimeout(20 * time.Millisecond))
+	manager.AddUnit(&stopOrderWorker{log: l, name: "db"}, "", WithName("db"))
+	manager.AddUnit(&stopOrderWorker{log: l, name: "cache", blocked: true}, "", WithName("cache"))
+	manager.AddUnit(&stopOrderWorker{log: l, name: "api"}, "", WithName("api"))
+
+	select {
+	case <-runAsync(manager):
+	case <-time.After(time.Second):
+		t.Fatal("manager did not quit after the startup timeout")
+	}
+
+	err := manager.Err()
+	if !errors.Is(err, ErrStartup) || !errors.Is(err, ErrStartupTimeout) {
+		t.Fatalf("expected a startup timeout, got %v", err)
+	}
+
+	report := manager.Snapshot().Startup
+	if report == nil || !report.TimedOut || report.Blocking != "cache" {
+		t.Fatalf("expected the report to identify the blocking unit, got %+v", report)
+	}
+
+	want := []string{"api", "cache", "db"}
+	if len(l.order) != len(want) {
+		t.Fatalf("expected rollback %v, got %v", want, l.order)
+	}
+	for i := range want {
+		if l.order[i] != want[i] {
+			t.Fatalf("expected rollback %v, got %v", want, l.order)
+		}
+	}
+}
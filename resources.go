@@ -0,0 +1,61 @@
+package gum
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// WithFDBudget declares the number of file descriptors the unit needs at
+// most: listeners, connections, open files. Before starting the units, the
+// manager verifies the file descriptor limit of the process can accommodate
+// them and fails the startup with ErrResources otherwise, rather than units
+// failing later with "too many open files" under load. Units added while
+// the manager is running are not added if they don't fit.
+func WithFDBudget(n int) UnitOption {
+	return func(w *WorkUnitManager) {
+		if n <= 0 {
+			w.invalid(fmt.Errorf("invalid file descriptor budget: %d", n))
+			return
+		}
+		w.fdBudget = n
+	}
+}
+
+// checkResources verifies the file descriptors left to the process can
+// accommodate the budgets of the units. The check is skipped on platforms
+// without file descriptor limit.
+func (m *Manager) checkResources(units []*WorkUnitManager) error {
+	need := 0
+	var needs []string
+	for _, w := range units {
+		if w.fdBudget > 0 {
+			need += w.fdBudget
+			needs = append(needs, fmt.Sprintf("<%s> %d", w.name, w.fdBudget))
+		}
+	}
+	if need == 0 {
+		return nil
+	}
+
+	limit, ok := fdLimit()
+	if !ok {
+		return nil
+	}
+	open := openFDs()
+	if uint64(need+open) <= limit {
+		return nil
+	}
+	return fmt.Errorf("%w: units need %d file descriptors, %d left of the limit of %d (%s)",
+		ErrResources, need, max(int(limit)-open, 0), limit, strings.Join(needs, ", "))
+}
+
+// openFDs returns the number of file descriptors open by the process, 0 if
+// unknown.
+func openFDs() int {
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		return 0
+	}
+	return len(entries)
+}
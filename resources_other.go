@@ -0,0 +1,8 @@
+//go:build !unix
+
+package gum
+
+// fdLimit reports no file descriptor limit.
+func fdLimit() (uint64, bool) {
+	return 0, false
+}
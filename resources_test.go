@@ -0,0 +1,40 @@
+package gum
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFDBudget(t *testing.T) {
+	limit, ok := fdLimit()
+	if !ok || limit > 1<<20 {
+		t.Skip("no file descriptor limit to exceed")
+	}
+
+	manager := NewManager()
+	manager.AddUnit(&stopWorker{}, "", WithName("proxy"), WithFDBudget(int(limit)))
+	manager.AddUnit(&stopWorker{}, "", WithName("cache"), WithFDBudget(8))
+	manager.Run()
+
+	err := manager.Err()
+	if !errors.Is(err, ErrStartup) || !errors.Is(err, ErrResources) {
+		t.Fatalf("expected the startup to fail, got %v", err)
+	}
+	if state := manager.Snapshot().Units[0].State; state != Starting {
+		t.Fatalf("expected the units not to be started, got %s", state)
+	}
+}
+
+func TestFDBudgetFits(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&stopWorker{}, "", WithFDBudget(8))
+	if err := manager.checkResources(manager.order); err != nil {
+		t.Fatal(err)
+	}
+
+	invalid := NewManager()
+	invalid.AddUnit(&stopWorker{}, "", WithFDBudget(0))
+	if invalid.Validate() == nil {
+		t.Fatal("expected a zero budget to be invalid")
+	}
+}
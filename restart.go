@@ -0,0 +1,422 @@
+package gum
+
+import (
+	"fmt"
+	"time"
+)
+
+// RestartPolicy defines whether a unit is restarted once it failed or is
+// done, see WithRestart.
+type RestartPolicy int
+
+const (
+	// RestartNever never restarts the unit: a failure shuts the manager
+	// down, see the panic policy (default).
+	RestartNever RestartPolicy = iota
+
+	// RestartOnFailure restarts the unit when it panics.
+	RestartOnFailure
+
+	// RestartAlways restarts the unit when it panics or when it is done
+	// without having been asked to stop.
+	RestartAlways
+)
+
+var restartPolicyNames = [...]string{
+	RestartNever:     "never",
+	RestartOnFailure: "on-failure",
+	RestartAlways:    "always",
+}
+
+func (p RestartPolicy) String() string {
+	if p >= 0 && int(p) < len(restartPolicyNames) {
+		return restartPolicyNames[p]
+	}
+	return fmt.Sprintf("RestartPolicy(%d)", int(p))
+}
+
+func parseRestartPolicy(s string) (RestartPolicy, error) {
+	for p, name := range restartPolicyNames {
+		if name == s {
+			return RestartPolicy(p), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown restart policy %q", s)
+}
+
+const (
+	// DefaultRestartBackoff is the default delay before the first restart of
+	// a unit. It doubles with each restart within the restart period.
+	DefaultRestartBackoff = 100 * time.Millisecond
+
+	// DefaultMaxRestartBackoff caps the delay between restarts.
+	DefaultMaxRestartBackoff = 30 * time.Second
+
+	// DefaultRestartIntensity is the default number of restarts allowed
+	// within DefaultRestartPeriod.
+	DefaultRestartIntensity = 5
+	DefaultRestartPeriod    = 30 * time.Second
+)
+
+// WithRestart sets the restart policy of the unit. A restarted unit is a new
+// instance of the unit, running the same WorkUnit with the same options.
+// Restarts are delayed by a jittered exponential backoff, see
+// WithRestartBackoff, and bounded by the restart intensity, see
+// WithRestartIntensity. The restart hooks are consulted before each restart
+// and may delay or veto it.
+func WithRestart(policy RestartPolicy) UnitOption {
+	return func(w *WorkUnitManager) {
+		if policy < RestartNever || policy > RestartAlways {
+			w.invalid(fmt.Errorf("invalid restart policy %d", int(policy)))
+			return
+		}
+		w.restartPolicy = policy
+	}
+}
+
+// WithRestartBackoff sets the delay before the first restart of the unit,
+// doubled with each restart within the restart period up to max. Half of the
+// delay is random so units failing together don't restart together.
+func WithRestartBackoff(initial, max time.Duration) UnitOption {
+	return func(w *WorkUnitManager) {
+		if initial <= 0 || max < initial {
+			w.invalid(fmt.Errorf("invalid restart backoff from %s to %s", initial, max))
+			return
+		}
+		w.restartBackoff = initial
+		w.restartMaxBackoff = max
+	}
+}
+
+// WithRestartIntensity allows at most max restarts of the unit within
+// period. Past it the manager gives up on the unit and escalates: the
+// failure is handled as a panic with ErrRestartIntensity, shutting the
+// manager down.
+func WithRestartIntensity(max int, period time.Duration) UnitOption {
+	return func(w *WorkUnitManager) {
+		if max < 0 || period <= 0 {
+			w.invalid(fmt.Errorf("invalid restart intensity: %d restarts within %s", max, period))
+			return
+		}
+		w.restartIntensity = max
+		w.restartPeriod = period
+	}
+}
+
+// WithStartRetry retries the start of the unit when it fails before being
+// ready, at most attempts times, independently of the restart policy which
+// then only applies to failures once the unit is ready. E.g. a unit retried
+// five times on start but never restarted at runtime:
+//
+//	manager.AddUnit(db, "db", gum.WithStartRetry(5, time.Second))
+//
+// Retries are delayed by a jittered exponential backoff from backoff up to
+// the maximum restart backoff, see WithRestartBackoff. The startup completion
+// waits for the retried unit. Past the last attempt the failure is handled as
+// a panic with ErrStartRetries.
+func WithStartRetry(attempts int, backoff time.Duration) UnitOption {
+	return func(w *WorkUnitManager) {
+		if attempts < 0 || backoff <= 0 {
+			w.invalid(fmt.Errorf("invalid start retry: %d attempts from %s", attempts, backoff))
+			return
+		}
+		w.startRetry = true
+		w.startRetries = attempts
+		w.startBackoff = backoff
+	}
+}
+
+// restartUnit applies the restart policy of the unit once it failed with
+// cause, or is done if cause is nil. It reports whether a restart was
+// scheduled, and returns ErrRestartIntensity when the restart intensity is
+// exceeded and the failure must be escalated. The policy is applied once per
+// unit instance.
+func (m *Manager) restartUnit(w *WorkUnitManager, cause error) (bool, error) {
+	if w.startRetry && cause != nil && !m.unitReady(w) {
+		return m.retryStart(w, cause)
+	}
+
+	policy := m.restartPolicy(w)
+	switch {
+	case policy == RestartNever:
+		return false, nil
+	case policy == RestartOnFailure && cause == nil:
+		return false, nil
+	case w.Stopping(), w.removed.Load():
+		return false, nil
+	}
+
+	select {
+	case <-m.startStop:
+		return false, nil
+	default:
+	}
+
+	if !w.restartChecked.CompareAndSwap(false, true) {
+		return false, nil
+	}
+
+	// Count the restarts of the lineage within the restart period
+	now := m.clock.Now()
+	m.regMu.Lock()
+	times := m.restartTimes[w.lineage]
+	i := 0
+	for i < len(times) && now.Sub(times[i]) > w.restartPeriod {
+		i++
+	}
+	times = times[i:]
+	if len(times) >= w.restartIntensity {
+		m.restartTimes[w.lineage] = times
+		m.regMu.Unlock()
+		return false, fmt.Errorf("%w: %d restarts within %s", ErrRestartIntensity, len(times), w.restartPeriod)
+	}
+	times = append(times, now)
+	m.restartTimes[w.lineage] = times
+	m.regMu.Unlock()
+
+	if cause == nil {
+		m.setTrace(w, m.newTraceID())
+	}
+	trace := m.unitTrace(w)
+
+	decision := m.restartDecision(w)
+	if decision.Veto {
+		m.unitLogf(w.name, "Restart of <%s> vetoed: %s (trace %s)\n", w, decision.Reason, trace)
+		return false, nil
+	}
+
+	backoff := w.restartBackoff
+	for n := 1; n < len(times) && backoff < w.restartMaxBackoff; n++ {
+		backoff *= 2
+	}
+	backoff = min(backoff, w.restartMaxBackoff)
+	delay := backoff/2 + m.jitter(backoff/2) + decision.Delay
+
+	w.restarting.Store(true)
+	m.unitLogf(w.name, "Scheduling restart of <%s> in %s, restart %d within %s (trace %s)\n", w, delay, len(times), w.restartPeriod, trace)
+	m.emitEvent(Event{
+		Kind:    EventUnitRestart,
+		Unit:    w.name,
+		Err:     cause,
+		Restart: &RestartDecision{Delay: delay, Reason: decision.Reason},
+		TraceID: trace,
+	})
+
+	go m.protect("restart", func() { m.restart(w, delay) })
+	if cause != nil {
+		m.restartSiblings(w, delay)
+	}
+	return true, nil
+}
+
+// retryStart applies the start retry policy of the unit once it failed
+// before being ready.
+func (m *Manager) retryStart(w *WorkUnitManager, cause error) (bool, error) {
+	if w.Stopping() {
+		return false, nil
+	}
+
+	select {
+	case <-m.startStop:
+		return false, nil
+	default:
+	}
+
+	if !w.restartChecked.CompareAndSwap(false, true) {
+		return false, nil
+	}
+
+	if w.startAttempt >= w.startRetries {
+		if w.startRetries == 0 {
+			return false, nil
+		}
+		return false, fmt.Errorf("%w: %d attempts", ErrStartRetries, w.startAttempt+1)
+	}
+
+	trace := m.unitTrace(w)
+	decision := m.restartDecision(w)
+	if decision.Veto {
+		m.unitLogf(w.name, "Start retry of <%s> vetoed: %s (trace %s)\n", w, decision.Reason, trace)
+		return false, nil
+	}
+
+	backoff := w.startBackoff
+	for n := 0; n < w.startAttempt && backoff < w.restartMaxBackoff; n++ {
+		backoff *= 2
+	}
+	backoff = min(backoff, w.restartMaxBackoff)
+	delay := backoff/2 + m.jitter(backoff/2) + decision.Delay
+
+	w.retryingStart = true
+	w.restarting.Store(true)
+	m.unitLogf(w.name, "Retrying start of <%s> in %s, attempt %d of %d (trace %s)\n", w, delay, w.startAttempt+1, w.startRetries, trace)
+	m.emitEvent(Event{
+		Kind:    EventUnitRestart,
+		Unit:    w.name,
+		Err:     cause,
+		Restart: &RestartDecision{Delay: delay, Reason: decision.Reason},
+		TraceID: trace,
+	})
+
+	go m.protect("restart", func() { m.restart(w, delay) })
+	return true, nil
+}
+
+// unitReady reports whether the unit called Ready.
+func (m *Manager) unitReady(w *WorkUnitManager) bool {
+	m.regMu.RLock()
+	defer m.regMu.RUnlock()
+	return w.ready
+}
+
+// RestartUnit stops the named unit and starts a new instance of it once it is
+// done, with the same WorkUnit and options, whatever its restart policy. The
+// restart is published as an EventUnitRestart event.
+func (m *Manager) RestartUnit(name string) error {
+	_, err := m.requestRestart(name)
+	return err
+}
+
+// Restart is like RestartUnit but blocks until the unit is done and its new
+// instance is started, e.g. for tooling restarting a unit after rotating its
+// credentials.
+func (m *Manager) Restart(name string) error {
+	w, err := m.requestRestart(name)
+	if err != nil {
+		return err
+	}
+
+	for {
+		m.regMu.Lock()
+		changed := m.changedC()
+		replaced, registered := w.replacedBy != nil, m.workers[name] == w
+		m.regMu.Unlock()
+
+		if replaced {
+			// The new instance is started along with its registration
+			m.startMu.Lock()
+			m.startMu.Unlock()
+			return nil
+		}
+		if !registered {
+			return fmt.Errorf("can't restart <%s>: unit removed", name)
+		}
+
+		select {
+		case <-changed:
+		case <-m.startStop:
+			return fmt.Errorf("can't restart <%s>: manager shutting down", name)
+		}
+	}
+}
+
+// requestRestart schedules the restart of the named unit and returns its
+// current instance.
+func (m *Manager) requestRestart(name string) (*WorkUnitManager, error) {
+	m.startMu.Lock()
+	m.regMu.RLock()
+	w, ok := m.workers[name]
+	m.regMu.RUnlock()
+	started := ok && w.started.Load()
+	m.startMu.Unlock()
+
+	switch {
+	case !ok:
+		return nil, fmt.Errorf("can't restart <%s>: unknown unit", name)
+	case !started:
+		return nil, fmt.Errorf("can't restart <%s>: unit not started", name)
+	case w.removed.Load():
+		return nil, fmt.Errorf("can't restart <%s>: unit removed", name)
+	case !w.restartChecked.CompareAndSwap(false, true):
+		return nil, fmt.Errorf("can't restart <%s>: already restarting", name)
+	}
+
+	m.restartWith(w, m.newTraceID(), "requested", 0)
+	return w, nil
+}
+
+// restartWith stops the unit and starts a new instance of it once it is done
+// and the delay elapsed. The restart is published as an EventUnitRestart
+// event with the reason.
+func (m *Manager) restartWith(w *WorkUnitManager, trace, reason string, delay time.Duration) {
+	m.setTrace(w, trace)
+	w.restarting.Store(true)
+	m.unitLogf(w.name, "Restart of <%s> %s (trace %s)\n", w, reason, trace)
+	m.emitEvent(Event{
+		Kind:    EventUnitRestart,
+		Unit:    w.name,
+		Restart: &RestartDecision{Delay: delay, Reason: reason},
+		TraceID: trace,
+	})
+
+	start := m.clock.Now()
+	m.stopUnit(w)
+	go m.protect("restart", func() {
+		if m.waitDone(w) {
+			m.restart(w, delay-m.clock.Now().Sub(start))
+		}
+	})
+}
+
+// waitDone blocks until the unit is done. It reports false if the manager
+// shuts down first.
+func (m *Manager) waitDone(w *WorkUnitManager) bool {
+	for {
+		m.regMu.Lock()
+		changed := m.changedC()
+		m.regMu.Unlock()
+
+		if w.done.Load() {
+			return true
+		}
+
+		select {
+		case <-changed:
+		case <-m.startStop:
+			return false
+		}
+	}
+}
+
+// restart starts a new instance of the unit once the delay elapsed, unless
+// the manager is shutting down first.
+func (m *Manager) restart(w *WorkUnitManager, delay time.Duration) {
+	due := make(chan struct{})
+	timer := m.clock.AfterFunc(delay, func() { close(due) })
+	select {
+	case <-due:
+	case <-m.startStop:
+		timer.Stop()
+		return
+	}
+
+	// Wait for the initial units to be started
+	select {
+	case <-m.startDone:
+	case <-m.startStop:
+		return
+	}
+
+	m.startMu.Lock()
+	defer m.startMu.Unlock()
+
+	select {
+	case <-m.startStop:
+		return
+	default:
+	}
+
+	r := m.newUnitID(w.unit, w.base, m.replacedID(w), w.opts...)
+	if w.retryingStart {
+		// The startup completion waits for the retried unit instead
+		r.startAttempt = w.startAttempt + 1
+		r.settled.Store(w.settled.Load())
+	} else {
+		r.settled.Store(true) // The startup completion only waits for the initial units
+	}
+	r.traceID = m.unitTrace(w)
+
+	m.unitLogf(w.name, "Restarting <%s> as <%s> (trace %s)\n", w, r, r.traceID)
+	m.replaceUnit(w, r)
+	m.startUnit(r)
+}
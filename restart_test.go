@@ -0,0 +1,327 @@
+package gum
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var errFlaky = errors.New("flaky")
+
+// flakyWorker fails its first runs, then runs until stopped
+type flakyWorker struct {
+	runs     atomic.Int32
+	failures int32
+}
+
+func (w *flakyWorker) Run(um UnitManager) {
+	if w.runs.Add(1) <= w.failures {
+		um.Panic(errFlaky)
+		return
+	}
+	um.Ready()
+	<-um.ShouldStop()
+	um.Done()
+}
+
+// waitRestarted waits for unit i to be running once restarted the given
+// number of times.
+func waitRestarted(t *testing.T, manager *Manager, i, restarts int) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		units := manager.Snapshot().Units
+		if i < len(units) && units[i].State == Running && units[i].Restarts >= restarts {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("expected unit %d to be running after %d restarts, got %+v", i, restarts, manager.Snapshot().Units)
+}
+
+func TestRestartOnFailure(t *testing.T) {
+	unit := &flakyWorker{failures: 2}
+	manager := NewManager()
+	manager.AddUnit(unit, "", WithRestart(RestartOnFailure), WithRestartBackoff(time.Millisecond, 2*time.Millisecond))
+	sub := manager.Subscribe()
+
+	go manager.Run()
+
+	ev := waitEvent(t, sub, EventUnitRestart)
+	if !errors.Is(ev.Err, errFlaky) || ev.Restart == nil || ev.Restart.Delay > time.Millisecond {
+		t.Fatalf("unexpected restart event %+v", ev)
+	}
+	waitRestarted(t, manager, 0, 2)
+
+	manager.Stop()
+	<-manager.Quit
+
+	if manager.Err() != nil {
+		t.Fatalf("expected the failures to be recovered by restarts, got %v", manager.Err())
+	}
+	if runs := unit.runs.Load(); runs != 3 {
+		t.Fatalf("expected 3 runs, got %d", runs)
+	}
+}
+
+func TestRestartIntensity(t *testing.T) {
+	unit := &flakyWorker{failures: 100}
+	manager := NewManager()
+	manager.AddUnit(unit, "",
+		WithRestart(RestartOnFailure),
+		WithRestartBackoff(time.Millisecond, 4*time.Millisecond),
+		WithRestartIntensity(3, time.Minute))
+	sub := manager.Subscribe()
+
+	select {
+	case <-runAsync(manager):
+	case <-time.After(time.Second):
+		t.Fatal("manager did not give up on the unit")
+	}
+
+	err := manager.Err()
+	if !errors.Is(err, ErrUnitPanic) || !errors.Is(err, ErrRestartIntensity) || !errors.Is(err, errFlaky) {
+		t.Fatalf("expected the failure to be escalated, got %v", err)
+	}
+	if runs := unit.runs.Load(); runs != 4 {
+		t.Fatalf("expected 4 runs, got %d", runs)
+	}
+
+	// The backoff doubles with each restart
+	var delays []time.Duration
+	for ev := range sub.Events() {
+		if ev.Kind == EventUnitRestart {
+			delays = append(delays, ev.Restart.Delay)
+		}
+		if ev.Kind == EventManagerQuit {
+			break
+		}
+	}
+	if len(delays) != 3 || delays[1] < time.Millisecond || delays[2] < 2*time.Millisecond {
+		t.Fatalf("unexpected restart delays %v", delays)
+	}
+}
+
+// exitCounter is done right away and counts its runs
+type exitCounter struct{ runs atomic.Int32 }
+
+func (w *exitCounter) Run(um UnitManager) {
+	w.runs.Add(1)
+	um.Done()
+}
+
+func TestRestartAlways(t *testing.T) {
+	unit := &exitCounter{}
+	manager := NewManager()
+	manager.AddUnit(unit, "",
+		WithRestart(RestartAlways),
+		WithRestartBackoff(time.Millisecond, time.Millisecond),
+		WithRestartIntensity(2, time.Minute))
+
+	select {
+	case <-runAsync(manager):
+	case <-time.After(time.Second):
+		t.Fatal("manager did not give up on the unit")
+	}
+
+	if !errors.Is(manager.Err(), ErrRestartIntensity) {
+		t.Fatalf("expected the restart intensity to be exceeded, got %v", manager.Err())
+	}
+	if runs := unit.runs.Load(); runs != 3 {
+		t.Fatalf("expected 3 runs, got %d", runs)
+	}
+}
+
+func TestRestartStoppedUnit(t *testing.T) {
+	unit := &flakyWorker{}
+	manager := NewManager()
+	manager.AddUnit(unit, "", WithRestart(RestartAlways))
+
+	go manager.Run()
+	waitState(t, manager, 0, Running)
+	manager.Stop()
+	<-manager.Quit
+
+	if runs := unit.runs.Load(); runs != 1 || len(manager.Snapshot().Units) != 1 {
+		t.Fatalf("expected a stopped unit not to be restarted, got %d runs", runs)
+	}
+}
+
+func TestStartRetry(t *testing.T) {
+	unit := &flakyWorker{failures: 2}
+	manager := NewManager()
+	manager.AddUnit(unit, "", WithStartRetry(3, time.Millisecond))
+	sub := manager.Subscribe()
+
+	go manager.Run()
+	waitEvent(t, sub, EventStartupComplete)
+
+	// The startup completion waited for the retried unit
+	if runs := unit.runs.Load(); runs != 3 {
+		t.Fatalf("expected the startup to complete after 3 runs, got %d", runs)
+	}
+
+	manager.Stop()
+	<-manager.Quit
+	if manager.Err() != nil {
+		t.Fatalf("expected the start failures to be retried, got %v", manager.Err())
+	}
+}
+
+func TestStartRetriesExhausted(t *testing.T) {
+	unit := &flakyWorker{failures: 100}
+	manager := NewManager()
+	manager.AddUnit(unit, "",
+		WithStartRetry(2, time.Millisecond),
+		WithRestart(RestartAlways))
+
+	select {
+	case <-runAsync(manager):
+	case <-time.After(time.Second):
+		t.Fatal("manager did not give up on the unit")
+	}
+
+	err := manager.Err()
+	if !errors.Is(err, ErrUnitPanic) || !errors.Is(err, ErrStartRetries) || !errors.Is(err, errFlaky) {
+		t.Fatalf("expected the start failure to be escalated, got %v", err)
+	}
+	if runs := unit.runs.Load(); runs != 3 {
+		t.Fatalf("expected 3 runs, got %d", runs)
+	}
+}
+
+// runtimeFailure fails once ready
+type runtimeFailure struct{ runs atomic.Int32 }
+
+func (w *runtimeFailure) Run(um UnitManager) {
+	w.runs.Add(1)
+	um.Ready()
+	um.Panic(errFlaky)
+}
+
+func TestStartRetryNotAtRuntime(t *testing.T) {
+	unit := &runtimeFailure{}
+	manager := NewManager()
+	manager.AddUnit(unit, "", WithStartRetry(5, time.Millisecond))
+
+	select {
+	case <-runAsync(manager):
+	case <-time.After(time.Second):
+		t.Fatal("manager did not shut down on the runtime failure")
+	}
+
+	if !errors.Is(manager.Err(), ErrUnitPanic) || errors.Is(manager.Err(), ErrStartRetries) {
+		t.Fatalf("expected the runtime failure to shut the manager down, got %v", manager.Err())
+	}
+	if runs := unit.runs.Load(); runs != 1 {
+		t.Fatalf("expected a single run, got %d", runs)
+	}
+}
+
+func TestRestartUnit(t *testing.T) {
+	unit := &flakyWorker{}
+	manager := NewManager()
+	manager.AddUnit(unit, "", WithName("worker"))
+
+	if err := manager.RestartUnit("worker"); err == nil {
+		t.Fatal("expected an error restarting a unit not started")
+	}
+
+	sub := manager.Subscribe()
+	quit := runAsync(manager)
+	waitEvent(t, sub, EventUnitReady)
+
+	if err := manager.RestartUnit("worker"); err != nil {
+		t.Fatalf("unexpected restart error: %v", err)
+	}
+	if ev := waitEvent(t, sub, EventUnitRestart); ev.Restart == nil || ev.Restart.Reason != "requested" {
+		t.Fatalf("unexpected restart event %+v", ev)
+	}
+	waitEvent(t, sub, EventUnitReady) // From the new instance Run
+
+	if u, _ := manager.Status("worker"); u.Restarts != 1 || unit.runs.Load() != 2 {
+		t.Fatalf("expected a second run, got %d runs and status %+v", unit.runs.Load(), u)
+	}
+	if err := manager.RestartUnit("unknown"); err == nil {
+		t.Fatal("expected an error restarting an unknown unit")
+	}
+
+	manager.Stop()
+	<-quit
+	if manager.Err() != nil {
+		t.Fatalf("unexpected shutdown cause: %v", manager.Err())
+	}
+}
+
+func TestRestartBlocking(t *testing.T) {
+	// Signaled from Run, the state is set before the unit runs
+	runs := make(chan struct{}, 2)
+	manager := NewManager()
+	manager.AddUnit(funcWorker(func(um UnitManager) {
+		runs <- struct{}{}
+		um.Ready()
+		<-um.ShouldStop()
+		um.Done()
+	}), "", WithName("poller"))
+
+	quit := runAsync(manager)
+	waitRun(t, runs)
+
+	if err := manager.Restart("poller"); err != nil {
+		t.Fatalf("unexpected restart error: %v", err)
+	}
+	if u, _ := manager.Status("poller"); u.Restarts != 1 {
+		t.Fatalf("expected the new instance to be started once Restart returned, got %+v", u)
+	}
+	waitRun(t, runs)
+	if err := manager.Restart("unknown"); err == nil {
+		t.Fatal("expected an error restarting an unknown unit")
+	}
+
+	manager.Stop()
+	<-quit
+	if err := manager.Restart("poller"); err == nil {
+		t.Fatal("expected an error restarting a unit once the manager quit")
+	}
+}
+
+func waitRun(t *testing.T, runs <-chan struct{}) {
+	t.Helper()
+
+	select {
+	case <-runs:
+	case <-time.After(time.Second):
+		t.Fatal("expected the unit to run")
+	}
+}
+
+func TestRestartRegistry(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(funcWorker(func(um UnitManager) { um.Done() }), "",
+		WithRestart(RestartAlways),
+		WithRestartBackoff(time.Millisecond, time.Millisecond),
+		WithRestartIntensity(100, time.Minute))
+	manager.AddUnit(&readyWorker{}, "", WithName("server"))
+
+	quit := runAsync(manager)
+	deadline := time.Now().Add(time.Second)
+	for manager.Snapshot().Units[0].Restarts < 20 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 20 restarts, got %+v", manager.Snapshot().Units[0])
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// Each instance replaces the previous one
+	manager.regMu.RLock()
+	order, workers := len(manager.order), len(manager.workers)
+	manager.regMu.RUnlock()
+	if units := manager.Snapshot().Units; order != 2 || workers != 2 || len(units) != 2 || units[1].Name != "server" {
+		t.Fatalf("expected the registry not to grow with restarts, got %d units, %d workers and %+v", order, workers, units)
+	}
+
+	manager.Stop()
+	<-quit
+}
@@ -0,0 +1,75 @@
+package gum
+
+import (
+	"time"
+)
+
+// RestartDecision is the decision of a RestartHook.
+type RestartDecision struct {
+	Veto   bool          // Skip this restart
+	Delay  time.Duration // Delay the restart
+	Reason string
+}
+
+// RestartHook is consulted before an automatic restart of a unit, such as a
+// recycling, see WithRecycle, or a restart, see WithRestart. It can delay or veto the restart, e.g. during
+// an incident freeze.
+type RestartHook func(unit UnitInfo) RestartDecision
+
+// WithRestartHook adds a hook consulted before the automatic restart of any
+// unit.
+func WithRestartHook(hook RestartHook) Option {
+	return func(m *Manager) {
+		m.restartHooks = append(m.restartHooks, hook)
+	}
+}
+
+// WithUnitRestartHook adds a hook consulted before the automatic restart of
+// the unit. Unit hooks are consulted before the manager ones.
+func WithUnitRestartHook(hook RestartHook) UnitOption {
+	return func(w *WorkUnitManager) {
+		w.restartHooks = append(w.restartHooks, hook)
+	}
+}
+
+// restartDecision consults the restart hooks of the unit and of the manager.
+// Any veto vetoes the restart and the longest delay is applied. Every
+// decision is published as an EventRestartDecision event.
+func (m *Manager) restartDecision(w *WorkUnitManager) RestartDecision {
+	var decision RestartDecision
+
+	hooks := append(append([]RestartHook(nil), w.restartHooks...), m.restartHooks...)
+	if len(hooks) == 0 {
+		return decision
+	}
+
+	info := w.Info()
+	for _, hook := range hooks {
+		hook := hook // Left running on timeout
+		var d RestartDecision
+		if !m.callHook("restart hook", w.name, func() { d = hook(info) }) {
+			continue
+		}
+		if !d.Veto && d.Delay <= 0 {
+			continue
+		}
+
+		if d.Veto && !decision.Veto {
+			decision.Veto, decision.Reason = true, d.Reason
+		}
+		if d.Delay > decision.Delay {
+			decision.Delay = d.Delay
+			if !decision.Veto {
+				decision.Reason = d.Reason
+			}
+		}
+	}
+
+	m.emitEvent(Event{
+		Kind:    EventRestartDecision,
+		Unit:    w.name,
+		Restart: &decision,
+		TraceID: m.unitTrace(w),
+	})
+	return decision
+}
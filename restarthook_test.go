@@ -0,0 +1,62 @@
+package gum
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRestartHookVeto(t *testing.T) {
+	var consulted atomic.Int32
+	freeze := func(unit UnitInfo) RestartDecision {
+		consulted.Add(1)
+		return RestartDecision{Veto: true, Reason: "incident freeze"}
+	}
+
+	manager := NewManager(WithRestartHook(freeze))
+	newUnit := func() WorkUnit { return &readyWorker{} }
+	manager.AddUnit(newUnit(), "", WithRecycle(5*time.Millisecond, 0, newUnit))
+	sub := manager.Subscribe()
+
+	go manager.Run()
+
+	var decision *RestartDecision
+	for decision == nil {
+		select {
+		case ev := <-sub.Events():
+			if ev.Kind == EventRestartDecision {
+				decision = ev.Restart
+			}
+		case <-time.After(time.Second):
+			t.Fatal("restart decision not published")
+		}
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	manager.Stop()
+	<-manager.Quit
+
+	if !decision.Veto || decision.Reason != "incident freeze" {
+		t.Fatalf("unexpected decision %+v", decision)
+	}
+	if consulted.Load() < 2 {
+		t.Fatalf("expected the hook to be consulted on every recycling, got %d", consulted.Load())
+	}
+	if n := len(manager.Snapshot().Units); n != 1 {
+		t.Fatalf("expected the unit not to be recycled, got %d units", n)
+	}
+}
+
+func TestRestartDecision(t *testing.T) {
+	manager := NewManager(WithRestartHook(func(UnitInfo) RestartDecision {
+		return RestartDecision{Delay: time.Second, Reason: "global"}
+	}))
+	manager.AddUnit(&readyWorker{}, "", WithUnitRestartHook(func(UnitInfo) RestartDecision {
+		return RestartDecision{Delay: time.Minute, Reason: "unit"}
+	}))
+
+	d := manager.restartDecision(manager.order[0])
+	if d.Veto || d.Delay != time.Minute || d.Reason != "unit" {
+		t.Fatalf("expected the longest delay to win, got %+v", d)
+	}
+}
@@ -0,0 +1,188 @@
+package gum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Schedule returns the next activation time after t, see ScheduledUnit. The
+// zero time means no more activation.
+type Schedule interface {
+	Next(t time.Time) time.Time
+}
+
+// Every returns a schedule activating every d, e.g. every minute from the
+// start of the unit.
+func Every(d time.Duration) Schedule {
+	return every(d)
+}
+
+type every time.Duration
+
+func (e every) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(e))
+}
+
+// OverlapPolicy defines what a ScheduledUnit does with the activations due
+// while a run is still in progress.
+type OverlapPolicy int
+
+const (
+	// OverlapSkip drops the activations due during a run (default).
+	OverlapSkip OverlapPolicy = iota
+
+	// OverlapQueue runs once more right after a run during which
+	// activations were due, however many.
+	OverlapQueue
+)
+
+var overlapNames = [...]string{"skip", "queue"}
+
+func (p OverlapPolicy) String() string {
+	if p < 0 || int(p) >= len(overlapNames) {
+		return fmt.Sprintf("OverlapPolicy(%d)", int(p))
+	}
+	return overlapNames[p]
+}
+
+// ScheduledUnit is a unit running a function on a schedule, e.g. housekeeping
+// tasks. Runs never overlap: the activations due while a run is in progress
+// are handled according to Overlap. The context given to the function is
+// cancelled when the unit is asked to stop, and the unit is done once the
+// current run, if any, returned.
+//
+// A run returning an error fails the unit, as with FuncUnit: use a restart
+// policy to keep the schedule going, see WithRestart.
+type ScheduledUnit struct {
+	Schedule Schedule
+	Func     func(ctx context.Context) error
+	Overlap  OverlapPolicy
+}
+
+// Run runs the function on the schedule until the unit is asked to stop.
+// Activations are timed by the timer wheel of the manager, shared by its
+// scheduled units, so they can be late by up to the wheel resolution, 10ms.
+func (s *ScheduledUnit) Run(um UnitManager) {
+	um.Ready()
+	ctx := um.Context()
+
+	next := s.Schedule.Next(now(um))
+	for {
+		var timer interface{ Stop() bool }
+		var due chan struct{}
+		if !next.IsZero() {
+			due = make(chan struct{})
+			timer = afterFunc(um, next.Sub(now(um)), func() { close(due) })
+		}
+		select {
+		case <-due:
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			um.Done()
+			return
+		}
+
+		for run := true; run; {
+			if err := s.Func(ctx); err != nil && !(um.Stopping() && errors.Is(err, context.Canceled)) {
+				um.Panic(err)
+				return
+			}
+
+			// Activations due during the run
+			t := now(um)
+			missed := 0
+			for next = s.Schedule.Next(next); !next.IsZero() && !next.After(t); next = s.Schedule.Next(next) {
+				missed++
+			}
+			run = missed > 0 && s.Overlap == OverlapQueue && ctx.Err() == nil
+			if missed > 0 && !run {
+				um.Logger().Debug("skipped overlapping runs", "count", missed)
+			}
+		}
+	}
+}
+
+// scheduleTick is the resolution of the timer wheel of the scheduled units.
+const scheduleTick = 10 * time.Millisecond
+
+// now returns the time of the clock of the manager of the unit.
+func now(um UnitManager) time.Time {
+	if w, ok := um.(*WorkUnitManager); ok {
+		return w.manager.clock.Now()
+	}
+	return time.Now()
+}
+
+// afterFunc calls f once d has elapsed, on the timer wheel of the manager of
+// the unit or on its clock if set with WithClock, or on a timer of its own
+// for units run outside of a manager.
+func afterFunc(um UnitManager, d time.Duration, f func()) interface{ Stop() bool } {
+	w, ok := um.(*WorkUnitManager)
+	switch {
+	case !ok:
+		return time.AfterFunc(d, f)
+	case w.manager.customClock():
+		return w.manager.clock.AfterFunc(d, f)
+	}
+	return w.manager.timers().AfterFunc(d, f)
+}
+
+// timers returns the timer wheel of the manager, started on first use and
+// stopped once the manager quit.
+func (m *Manager) timers() *timerWheel {
+	m.wheelMu.Lock()
+	defer m.wheelMu.Unlock()
+
+	if m.wheel == nil {
+		// 64 slots on 4 levels cover 46h before re-cascading
+		m.wheel = newTimerWheel(scheduleTick, 64, 4)
+		if !m.wheelStopped {
+			m.wheel.Start()
+		}
+	}
+	return m.wheel
+}
+
+// stopTimers stops the timer wheel of the manager.
+func (m *Manager) stopTimers() {
+	m.wheelMu.Lock()
+	defer m.wheelMu.Unlock()
+
+	m.wheelStopped = true
+	if m.wheel != nil {
+		m.wheel.Stop()
+	}
+}
+
+// AddPeriodic registers a unit running fn every interval, see ScheduledUnit.
+// The name is used as is, as with WithName.
+func (m *Manager) AddPeriodic(name string, interval time.Duration, fn func(ctx context.Context) error, opts ...UnitOption) {
+	switch {
+	case fn == nil:
+		m.invalid(fmt.Errorf("nil func %q", name))
+		return
+	case interval <= 0:
+		m.invalid(fmt.Errorf("periodic unit %q: invalid interval %s", name, interval))
+		return
+	}
+	m.AddUnit(&ScheduledUnit{Schedule: Every(interval), Func: fn}, "", append([]UnitOption{WithName(name)}, opts...)...)
+}
+
+// AddCron registers a unit running fn on the cron schedule spec, see
+// ParseCron and ScheduledUnit. The name is used as is, as with WithName.
+func (m *Manager) AddCron(name, spec string, fn func(ctx context.Context) error, opts ...UnitOption) {
+	if fn == nil {
+		m.invalid(fmt.Errorf("nil func %q", name))
+		return
+	}
+	schedule, err := ParseCron(spec)
+	if err != nil {
+		m.invalid(fmt.Errorf("cron unit %q: %w", name, err))
+		return
+	}
+	m.AddUnit(&ScheduledUnit{Schedule: schedule, Func: fn}, "", append([]UnitOption{WithName(name)}, opts...)...)
+}
@@ -0,0 +1,121 @@
+package gum
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAddPeriodic(t *testing.T) {
+	var runs atomic.Int32
+	stopped := make(chan struct{})
+	manager := NewManager()
+	manager.AddPeriodic("housekeeping", time.Millisecond, func(ctx context.Context) error {
+		if runs.Add(1) == 3 {
+			<-ctx.Done() // Stopped during a run
+			close(stopped)
+			return ctx.Err()
+		}
+		return nil
+	})
+
+	sub := manager.Subscribe()
+	quit := runAsync(manager)
+	waitEvent(t, sub, EventStartupComplete)
+
+	deadline := time.Now().Add(time.Second)
+	for runs.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	manager.Stop()
+	<-quit
+	<-stopped
+
+	if runs.Load() != 3 {
+		t.Fatalf("expected 3 runs, got %d", runs.Load())
+	}
+	if manager.Err() != nil {
+		t.Fatalf("unexpected shutdown cause: %v", manager.Err())
+	}
+
+	// Timed by the wheel of the manager, stopped once it quit
+	select {
+	case <-manager.timers().stop:
+	default:
+		t.Fatal("expected the timer wheel to be stopped")
+	}
+}
+
+// scheduleFunc adapts a function to a Schedule.
+type scheduleFunc func(t time.Time) time.Time
+
+func (f scheduleFunc) Next(t time.Time) time.Time { return f(t) }
+
+func TestScheduledUnitOverlap(t *testing.T) {
+	for _, tt := range []struct {
+		policy OverlapPolicy
+		runs   int32
+	}{
+		{OverlapSkip, 1},
+		{OverlapQueue, 2},
+	} {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			// Two activations 10ms apart, then none for an hour
+			var first time.Time
+			schedule := scheduleFunc(func(t time.Time) time.Time {
+				switch {
+				case first.IsZero():
+					first = t.Add(10 * time.Millisecond)
+					return first
+				case t.Before(first.Add(10 * time.Millisecond)):
+					return first.Add(10 * time.Millisecond)
+				}
+				return t.Add(time.Hour)
+			})
+
+			var runs atomic.Int32
+			unit := &ScheduledUnit{
+				Schedule: schedule,
+				Overlap:  tt.policy,
+				Func: func(ctx context.Context) error {
+					if runs.Add(1) == 1 {
+						time.Sleep(20 * time.Millisecond) // Overlaps the second activation
+					}
+					return nil
+				},
+			}
+			manager := NewManager()
+			manager.AddUnit(unit, "", WithName("slow"))
+
+			quit := runAsync(manager)
+			time.Sleep(50 * time.Millisecond)
+			manager.Stop()
+			<-quit
+
+			if runs.Load() != tt.runs {
+				t.Fatalf("expected %d runs, got %d", tt.runs, runs.Load())
+			}
+		})
+	}
+}
+
+func TestScheduledUnitFailure(t *testing.T) {
+	manager := NewManager()
+	manager.AddPeriodic("failing", time.Millisecond, func(context.Context) error {
+		return errors.New("purge failed")
+	})
+	manager.Run()
+
+	if !errors.Is(manager.Err(), ErrUnitPanic) {
+		t.Fatalf("expected the failed run to fail the unit, got %v", manager.Err())
+	}
+
+	manager = NewManager()
+	manager.AddPeriodic("none", 0, func(context.Context) error { return nil })
+	manager.AddCron("bad", "* * *", func(context.Context) error { return nil })
+	if err := manager.Validate(); err == nil {
+		t.Fatal("expected invalid schedules to be reported")
+	}
+}
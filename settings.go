@@ -0,0 +1,206 @@
+package gum
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Settings tunable at runtime with Tune, by name.
+const (
+	SettingShutdownTimeout = "shutdown_timeout" // Duration, e.g. 30s
+	SettingLogSeverity     = "log_severity"     // debug, info, warn, error, critical or none
+	SettingStrategy        = "strategy"         // none, one-for-one, one-for-all or rest-for-one
+	SettingLogVerbosity    = "log_verbosity"    // quiet, normal or verbose
+)
+
+// SettingChange is the change of a setting of EventSettingChanged events.
+type SettingChange struct {
+	Name string
+	Old  string
+	New  string
+}
+
+// setting gets and sets a tunable setting, holding settingsMu.
+type setting struct {
+	get func(m *Manager) string
+	set func(m *Manager, value string) error
+}
+
+var settings = map[string]setting{
+	SettingShutdownTimeout: {
+		get: func(m *Manager) string {
+			return m.shutdownTimeout.String()
+		},
+		set: func(m *Manager, value string) error {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return err
+			}
+			if d < 0 {
+				return fmt.Errorf("negative shutdown timeout: %s", d)
+			}
+			m.shutdownTimeout = d
+			return nil
+		},
+	},
+	SettingLogSeverity: {
+		get: func(m *Manager) string {
+			if m.logSeverity == 0 {
+				return "none"
+			}
+			return m.logSeverity.String()
+		},
+		set: func(m *Manager, value string) error {
+			if value == "none" {
+				m.logSeverity = 0
+				return nil
+			}
+			s, err := parseSeverity(value)
+			if err != nil {
+				return err
+			}
+			m.logSeverity = s
+			return nil
+		},
+	},
+	SettingLogVerbosity: {
+		get: func(m *Manager) string {
+			return verbosityNames[m.verbosity]
+		},
+		set: func(m *Manager, value string) error {
+			v, err := parseVerbosity(value)
+			if err != nil {
+				return err
+			}
+			m.verbosity = v
+			return nil
+		},
+	},
+	SettingStrategy: {
+		get: func(m *Manager) string {
+			return m.strategy.String()
+		},
+		set: func(m *Manager, value string) error {
+			s, err := parseStrategy(value)
+			if err != nil {
+				return err
+			}
+			m.strategy = s
+			return nil
+		},
+	},
+}
+
+// Settings returns the current value of the settings tunable at runtime, by
+// name.
+func (m *Manager) Settings() map[string]string {
+	m.settingsMu.RLock()
+	defer m.settingsMu.RUnlock()
+
+	values := make(map[string]string, len(settings))
+	for name, s := range settings {
+		values[name] = s.get(m)
+	}
+	return values
+}
+
+// Tune changes a setting while the manager runs, e.g. to raise the shutdown
+// timeout of a draining service without restarting it. The value is parsed
+// and validated as the matching option, see the Setting constants. A change
+// is logged and published as an EventSettingChanged event for audit.
+func (m *Manager) Tune(name, value string) error {
+	s, ok := settings[name]
+	if !ok {
+		return fmt.Errorf("can't tune %s: unknown setting", name)
+	}
+
+	m.settingsMu.Lock()
+	old := s.get(m)
+	err := s.set(m, value)
+	current := s.get(m)
+	m.settingsMu.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("can't tune %s: %w", name, err)
+	}
+	if current == old {
+		return nil
+	}
+
+	m.logf("setting %s changed from %s to %s\n", name, old, current)
+	m.emitEvent(Event{
+		Kind:    EventSettingChanged,
+		Setting: &SettingChange{Name: name, Old: old, New: current},
+	})
+	return nil
+}
+
+func parseSeverity(s string) (Severity, error) {
+	for sev, name := range severityNames {
+		if name != "" && name == s {
+			return Severity(sev), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown severity %q", s)
+}
+
+func parseStrategy(s string) (Strategy, error) {
+	for st, name := range strategyNames {
+		if name == s {
+			return Strategy(st), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown supervision strategy %q", s)
+}
+
+// currentShutdownTimeout returns the shutdown timeout, which may be tuned
+// while the manager runs.
+func (m *Manager) currentShutdownTimeout() time.Duration {
+	m.settingsMu.RLock()
+	defer m.settingsMu.RUnlock()
+	return m.shutdownTimeout
+}
+
+// currentLogSeverity returns the log severity, which may be tuned while the
+// manager runs.
+func (m *Manager) currentLogSeverity() Severity {
+	m.settingsMu.RLock()
+	defer m.settingsMu.RUnlock()
+	return m.logSeverity
+}
+
+// currentStrategy returns the supervision strategy, which may be tuned while
+// the manager runs.
+func (m *Manager) currentStrategy() Strategy {
+	m.settingsMu.RLock()
+	defer m.settingsMu.RUnlock()
+	return m.strategy
+}
+
+// tuneAll validates all the values before tuning the settings, so an invalid
+// request changes nothing.
+func (m *Manager) tuneAll(values map[string]string) error {
+	var errs []error
+	for name, value := range values {
+		s, ok := settings[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("can't tune %s: unknown setting", name))
+			continue
+		}
+		var scratch Manager
+		if err := s.set(&scratch, value); err != nil {
+			errs = append(errs, fmt.Errorf("can't tune %s: %w", name, err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	for name, value := range values {
+		if err := m.Tune(name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package gum
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTune(t *testing.T) {
+	manager := NewManager(WithShutdownTimeout(time.Second))
+	sub := manager.Subscribe()
+
+	if err := manager.Tune(SettingShutdownTimeout, "30s"); err != nil {
+		t.Fatalf("unexpected tune error: %v", err)
+	}
+	ev := waitEvent(t, sub, EventSettingChanged)
+	if ev.Setting == nil || *ev.Setting != (SettingChange{SettingShutdownTimeout, "1s", "30s"}) {
+		t.Fatalf("unexpected setting change %+v", ev.Setting)
+	}
+	if manager.currentShutdownTimeout() != 30*time.Second {
+		t.Fatalf("unexpected shutdown timeout %s", manager.currentShutdownTimeout())
+	}
+
+	if err := manager.Tune(SettingStrategy, "one-for-all"); err != nil || manager.currentStrategy() != OneForAll {
+		t.Fatalf("unexpected strategy %s: %v", manager.currentStrategy(), err)
+	}
+	if err := manager.Tune(SettingLogSeverity, "warn"); err != nil || manager.currentLogSeverity() != SeverityWarn {
+		t.Fatalf("unexpected log severity %s: %v", manager.currentLogSeverity(), err)
+	}
+
+	for _, tt := range []struct{ name, value string }{
+		{SettingShutdownTimeout, "-1s"},
+		{SettingShutdownTimeout, "soon"},
+		{SettingLogSeverity, "loud"},
+		{SettingStrategy, "all-for-one"},
+		{"workers", "10"},
+	} {
+		if err := manager.Tune(tt.name, tt.value); err == nil {
+			t.Errorf("expected an error tuning %s to %s", tt.name, tt.value)
+		}
+	}
+
+	want := map[string]string{
+		SettingShutdownTimeout: "30s",
+		SettingLogSeverity:     "warn",
+		SettingStrategy:        "one-for-all",
+	}
+	got := manager.Settings()
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("unexpected %s %q, want %q", name, got[name], value)
+		}
+	}
+}
+
+func TestControlSettings(t *testing.T) {
+	manager := NewManager()
+	srv := httptest.NewServer(manager.ControlHandler())
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/settings", "application/json",
+		strings.NewReader(`{"shutdown_timeout": "5s", "strategy": "bogus"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest || manager.currentShutdownTimeout() != 0 {
+		t.Fatalf("expected the invalid request to change nothing, got %s", resp.Status)
+	}
+
+	resp, err = http.Post(srv.URL+"/settings", "application/json",
+		strings.NewReader(`{"shutdown_timeout": "5s", "strategy": "one-for-one"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || manager.currentShutdownTimeout() != 5*time.Second ||
+		manager.currentStrategy() != OneForOne {
+		t.Fatalf("unexpected settings %v, %s", manager.Settings(), resp.Status)
+	}
+}
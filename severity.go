@@ -0,0 +1,199 @@
+package gum
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Severity ranks events, so pager-worthy events can be told apart from
+// routine ones.
+type Severity int
+
+const (
+	SeverityDebug Severity = iota + 1
+	SeverityInfo
+	SeverityWarn
+	SeverityError
+	SeverityCritical
+)
+
+var severityNames = [...]string{
+	SeverityDebug:    "debug",
+	SeverityInfo:     "info",
+	SeverityWarn:     "warn",
+	SeverityError:    "error",
+	SeverityCritical: "critical",
+}
+
+func (s Severity) String() string {
+	if s < SeverityDebug || int(s) >= len(severityNames) {
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+	return severityNames[s]
+}
+
+// kindSeverities is the severity of events without error by kind.
+var kindSeverities = [...]Severity{
+	EventManagerStarted:  SeverityInfo,
+	EventUnitStarted:     SeverityInfo,
+	EventUnitStopping:    SeverityDebug,
+	EventUnitDone:        SeverityInfo,
+	EventUnitPanic:       SeverityError,
+	EventUnitAbandoned:   SeverityError,
+	EventShutdown:        SeverityInfo,
+	EventManagerQuit:     SeverityInfo,
+	EventUnitReady:       SeverityDebug,
+	EventBudgetExceeded:  SeverityWarn,
+	EventMemoryExceeded:  SeverityWarn,
+	EventRegistered:      SeverityInfo,
+	EventDeregistered:    SeverityInfo,
+	EventStartupComplete: SeverityInfo,
+	EventUnitParked:      SeverityDebug,
+	EventUnitWoken:       SeverityDebug,
+	EventRestartDecision: SeverityInfo,
+	EventInternalError:   SeverityCritical,
+	EventShutdownPhase:   SeverityDebug,
+	EventNoUnits:         SeverityWarn,
+	EventUnitRestart:     SeverityWarn,
+	EventHookTimeout:     SeverityError,
+	EventFrozen:          SeverityWarn,
+	EventUnfrozen:        SeverityInfo,
+	EventChangeBlocked:   SeverityWarn,
+	EventSettingChanged:  SeverityInfo,
+	EventUnitReloaded:    SeverityInfo,
+	EventReplay:          SeverityInfo,
+}
+
+// eventSeverity returns the severity of the event. Events carrying an error
+// are at least warnings, and a manager quitting with an error is critical.
+func eventSeverity(ev Event) Severity {
+	s := SeverityInfo
+	if int(ev.Kind) < len(kindSeverities) {
+		s = kindSeverities[ev.Kind]
+	}
+
+	if ev.Err == nil {
+		return s
+	}
+	if ev.Kind == EventManagerQuit {
+		return SeverityCritical
+	}
+	return max(s, SeverityWarn)
+}
+
+// WithMinSeverity only delivers the events at or above the given severity
+// to the subscriber.
+func WithMinSeverity(min Severity) SubscribeOption {
+	return func(s *Subscription) {
+		s.minSeverity = min
+	}
+}
+
+// WithLogSeverity logs the events at or above the given severity whatever
+// the verbosity. The verbose profile logs every event.
+func WithLogSeverity(min Severity) Option {
+	return func(m *Manager) {
+		if min < SeverityDebug || min > SeverityCritical {
+			m.invalid(fmt.Errorf("invalid log severity %d", int(min)))
+			return
+		}
+		m.logSeverity = min
+	}
+}
+
+// Notifier delivers events to an external system such as a pager.
+type Notifier interface {
+	Notify(ev Event)
+}
+
+// NotifierFunc adapts a function to the Notifier interface.
+type NotifierFunc func(ev Event)
+
+// Notify calls f.
+func (f NotifierFunc) Notify(ev Event) {
+	f(ev)
+}
+
+type notifier struct {
+	n   Notifier
+	min Severity
+}
+
+// WithNotifier delivers the events at or above the given severity to the
+// notifier. Events are delivered in order on a goroutine of the notifier,
+// the manager waits for pending events to be delivered before quitting.
+func WithNotifier(n Notifier, min Severity) Option {
+	return func(m *Manager) {
+		if n == nil {
+			m.invalid(fmt.Errorf("nil notifier"))
+			return
+		}
+		m.notifiers = append(m.notifiers, notifier{n, min})
+	}
+}
+
+// startNotifiers subscribes the notifiers to the events.
+func (m *Manager) startNotifiers() {
+	for _, n := range m.notifiers {
+		m.startNotifier(n.n, m.Subscribe(WithMinSeverity(n.min), WithBufferSize(256)), nil)
+	}
+}
+
+// startNotifier delivers the events of the subscription of the given kinds, every
+// event if none, to the notifier on its own goroutine. The manager waits for
+// the pending events to be delivered before quitting.
+func (m *Manager) startNotifier(n Notifier, sub *Subscription, kinds []EventKind) {
+	m.notifyMu.Lock()
+	defer m.notifyMu.Unlock()
+	if !m.notifyStopped {
+		m.notifySubs = append(m.notifySubs, sub)
+		m.notifyWG.Add(1)
+	}
+
+	go func(tracked bool) {
+		if tracked {
+			defer m.notifyWG.Done()
+		}
+		for ev := range sub.Events() {
+			if len(kinds) == 0 || slices.Contains(kinds, ev.Kind) {
+				m.deliver(n, ev)
+			}
+		}
+	}(!m.notifyStopped)
+}
+
+// deliver delivers the event to the notifier within the hook timeout. A
+// panic is only logged: an internal error event would be notified in turn,
+// as would the timeout of a hook timeout event.
+func (m *Manager) deliver(n Notifier, ev Event) {
+	delivered := m.withinHookTimeout(func() {
+		defer func() {
+			if r := recover(); r != nil {
+				m.warnf("", "notifier panic on %s: %v\n", ev, r)
+			}
+		}()
+		n.Notify(ev)
+	})
+	if delivered {
+		return
+	}
+
+	if ev.Kind == EventHookTimeout {
+		m.warnf("", "notifier did not return within %s on %s\n", m.hookTimeout, ev)
+		return
+	}
+	m.hookTimedOut("notifier", "")
+}
+
+// stopNotifiers waits for the pending events to be delivered.
+func (m *Manager) stopNotifiers() {
+	m.notifyMu.Lock()
+	subs := m.notifySubs
+	m.notifyStopped = true
+	m.notifyMu.Unlock()
+
+	for _, sub := range subs {
+		sub.Close()
+	}
+	m.notifyWG.Wait()
+}
@@ -0,0 +1,77 @@
+package gum
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestEventSeverity(t *testing.T) {
+	for _, tc := range []struct {
+		ev   Event
+		want Severity
+	}{
+		{Event{Kind: EventUnitStarted}, SeverityInfo},
+		{Event{Kind: EventUnitReady}, SeverityDebug},
+		{Event{Kind: EventUnitPanic, Err: errors.New("boom")}, SeverityError},
+		{Event{Kind: EventRegistered, Err: errors.New("unreachable")}, SeverityWarn},
+		{Event{Kind: EventManagerQuit}, SeverityInfo},
+		{Event{Kind: EventManagerQuit, Err: ErrForcedShutdown}, SeverityCritical},
+		{Event{Kind: EventInternalError}, SeverityCritical},
+	} {
+		if got := eventSeverity(tc.ev); got != tc.want {
+			t.Errorf("%s: expected severity %s, got %s", tc.ev, tc.want, got)
+		}
+	}
+
+	for k := range eventKindNames {
+		if eventSeverity(Event{Kind: EventKind(k)}) < SeverityDebug {
+			t.Errorf("no severity for %s", EventKind(k))
+		}
+	}
+}
+
+func TestSeverityFilters(t *testing.T) {
+	var mu sync.Mutex
+	var notified []EventKind
+	notifier := NotifierFunc(func(ev Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		notified = append(notified, ev.Kind)
+	})
+
+	var logs bytes.Buffer
+	manager := NewManager(
+		WithLogger(log.New(&logs, "", 0)),
+		WithLogSeverity(SeverityWarn),
+		WithNotifier(notifier, SeverityError),
+	)
+	manager.AddUnit(&panicWorker{}, "")
+	sub := manager.Subscribe(WithMinSeverity(SeverityInfo))
+
+	manager.Run()
+	sub.Close()
+
+	for ev := range sub.Events() {
+		if ev.Severity < SeverityInfo {
+			t.Errorf("unexpected %s event %s", ev.Severity, ev)
+		}
+	}
+
+	// The notifier must be done once the manager quit
+	mu.Lock()
+	if len(notified) != 2 || notified[0] != EventUnitPanic || notified[1] != EventManagerQuit {
+		t.Errorf("unexpected notified events %v", notified)
+	}
+	mu.Unlock()
+
+	if !strings.Contains(logs.String(), "event: unit-panic") {
+		t.Errorf("expected the panic event to be logged:\n%s", logs.String())
+	}
+	if strings.Contains(logs.String(), "event: unit-started") {
+		t.Errorf("expected routine events not to be logged:\n%s", logs.String())
+	}
+}
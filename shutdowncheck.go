@@ -0,0 +1,210 @@
+package gum
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultShutdownCheckTimeout bounds the startup and the shutdown of the
+// program run by a ShutdownCheck.
+const DefaultShutdownCheckTimeout = 30 * time.Second
+
+// ShutdownCheck runs a program built with gum as a black box: it starts the
+// program, sends it a real signal once it is ready and reports how it shut
+// down, so a release can be gated on its graceful shutdown, e.g. from a test:
+//
+//	check := gum.ShutdownCheck{Cmd: exec.Command("./server"), Ready: "listening"}
+//	res, err := check.Run()
+//	if err != nil || res.ExitCode != 0 || res.Duration > 10*time.Second {
+//		t.Fatalf("bad shutdown: %v\n%s", err, res.Output)
+//	}
+//	for _, u := range res.Delayed(time.Second) {
+//		t.Errorf("<%s> took %s to stop", u.Name, u.Latency)
+//	}
+//
+// The per-unit report is only available when the program reads its settings
+// from the environment, see WithEnv and EnvShutdownReport.
+type ShutdownCheck struct {
+	// Cmd is the program to run, not started yet. Its output is captured.
+	Cmd *exec.Cmd
+
+	// Ready is a marker printed by the program once ready. The signal is
+	// sent as soon as the program is started if empty.
+	Ready string
+
+	// Signal is the signal sent to the program, os.Interrupt by default.
+	Signal os.Signal
+
+	// Timeout bounds the startup and the shutdown of the program, each.
+	// The program is killed past it. DefaultShutdownCheckTimeout if zero.
+	Timeout time.Duration
+
+	// EnvPrefix is the prefix given to WithEnv by the program,
+	// DefaultEnvPrefix if empty.
+	EnvPrefix string
+}
+
+// ShutdownResult is the outcome of a ShutdownCheck.
+type ShutdownResult struct {
+	ExitCode int
+	Killed   bool          // The program did not exit within the timeout
+	Duration time.Duration // From the signal to the exit
+	Output   string        // Standard and error output
+
+	// Report is the report written by the program, nil if it did not
+	// write one.
+	Report *ShutdownReport
+}
+
+// Contains reports whether the output of the program contains the marker.
+func (r *ShutdownResult) Contains(marker string) bool {
+	return strings.Contains(r.Output, marker)
+}
+
+// Delayed returns the units which took more than d to stop, including the
+// abandoned ones, slowest first.
+func (r *ShutdownResult) Delayed(d time.Duration) []ShutdownUnit {
+	if r.Report == nil {
+		return nil
+	}
+
+	var delayed []ShutdownUnit
+	for _, u := range r.Report.Units {
+		if u.Latency > d || u.Abandoned {
+			delayed = append(delayed, u)
+		}
+	}
+	sort.SliceStable(delayed, func(i, j int) bool {
+		return delayed[i].Latency > delayed[j].Latency
+	})
+	return delayed
+}
+
+// Run runs the check. It returns an error if the program could not be
+// started or was not ready within the timeout, along with the result so far.
+func (c *ShutdownCheck) Run() (*ShutdownResult, error) {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultShutdownCheckTimeout
+	}
+	sig := c.Signal
+	if sig == nil {
+		sig = os.Interrupt
+	}
+	prefix := c.EnvPrefix
+	if prefix == "" {
+		prefix = DefaultEnvPrefix
+	}
+
+	dir, err := os.MkdirTemp("", "gum-shutdown-check")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	reportPath := filepath.Join(dir, "report.json")
+
+	out := &syncBuffer{}
+	cmd := c.Cmd
+	cmd.Stdout, cmd.Stderr = out, out
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
+	cmd.Env = append(cmd.Env, prefix+"_"+EnvShutdownReport+"="+reportPath)
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	exited := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		close(exited)
+	}()
+
+	res := &ShutdownResult{}
+	result := func() *ShutdownResult {
+		res.ExitCode = cmd.ProcessState.ExitCode()
+		res.Output = out.String()
+		if data, err := os.ReadFile(reportPath); err == nil {
+			var report ShutdownReport
+			if json.Unmarshal(data, &report) == nil {
+				res.Report = &report
+			}
+		}
+		return res
+	}
+
+	if err := waitMarker(out, c.Ready, exited, timeout); err != nil {
+		cmd.Process.Kill()
+		<-exited
+		return result(), err
+	}
+
+	signaled := time.Now()
+	if err := cmd.Process.Signal(sig); err != nil {
+		cmd.Process.Kill()
+		<-exited
+		return result(), err
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-exited:
+	case <-timer.C:
+		res.Killed = true
+		cmd.Process.Kill()
+		<-exited
+	}
+	res.Duration = time.Since(signaled)
+	return result(), nil
+}
+
+// waitMarker waits for the marker to be printed.
+func waitMarker(out *syncBuffer, marker string, exited <-chan struct{}, timeout time.Duration) error {
+	if marker == "" {
+		return nil
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(5 * time.Millisecond)
+	defer ticker.Stop()
+
+	for !strings.Contains(out.String(), marker) {
+		select {
+		case <-ticker.C:
+		case <-exited:
+			return errors.New("program exited before being ready")
+		case <-deadline.C:
+			return fmt.Errorf("program not ready within %s", timeout)
+		}
+	}
+	return nil
+}
+
+// syncBuffer is a buffer written by the program output copiers.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
@@ -0,0 +1,74 @@
+package gum
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+// drainWorker is ready once started and takes some time to drain
+type drainWorker struct{}
+
+func (w *drainWorker) Run(um UnitManager) {
+	um.Ready()
+	<-um.ShouldStop()
+	time.Sleep(20 * time.Millisecond)
+	um.Done()
+}
+
+// TestShutdownCheckProgram is the program checked by TestShutdownCheck, run
+// in a child process.
+func TestShutdownCheckProgram(t *testing.T) {
+	if os.Getenv("GUM_SHUTDOWN_CHECK_PROGRAM") == "" {
+		t.Skip("only run by TestShutdownCheck")
+	}
+
+	manager := NewManager(WithEnv(""))
+	manager.ShutdownOn(os.Interrupt)
+	manager.AddUnit(&readyWorker{}, "", WithName("fast"))
+	manager.AddUnit(&drainWorker{}, "", WithName("slow"))
+
+	sub := manager.Subscribe()
+	go func() {
+		waitEvent(t, sub, EventStartupComplete)
+		fmt.Println("program ready")
+	}()
+
+	manager.Run()
+	os.Exit(manager.ExitCode())
+}
+
+func TestShutdownCheck(t *testing.T) {
+	cmd := exec.Command(os.Args[0], "-test.run=^TestShutdownCheckProgram$")
+	cmd.Env = append(os.Environ(), "GUM_SHUTDOWN_CHECK_PROGRAM=1")
+
+	check := ShutdownCheck{Cmd: cmd, Ready: "program ready", Timeout: 5 * time.Second}
+	res, err := check.Run()
+	if err != nil {
+		t.Fatalf("check failed: %v\n%s", err, res.Output)
+	}
+
+	if res.ExitCode != 0 || res.Killed {
+		t.Fatalf("expected a clean exit, got code %d\n%s", res.ExitCode, res.Output)
+	}
+	if !res.Contains("All workers have shutdown") {
+		t.Fatalf("expected the shutdown log marker\n%s", res.Output)
+	}
+	if res.Report == nil || len(res.Report.Units) != 2 {
+		t.Fatalf("expected a report of the 2 units, got %+v", res.Report)
+	}
+
+	delayed := res.Delayed(10 * time.Millisecond)
+	if len(delayed) != 1 || delayed[0].Name != "slow" {
+		t.Fatalf("expected the slow unit to delay the shutdown, got %+v", delayed)
+	}
+}
+
+func TestShutdownCheckNotReady(t *testing.T) {
+	check := ShutdownCheck{Cmd: exec.Command(os.Args[0], "-test.run=^$"), Ready: "never printed"}
+	if _, err := check.Run(); err == nil {
+		t.Fatal("expected an error for a program exiting before being ready")
+	}
+}
@@ -0,0 +1,70 @@
+package gum
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ShutdownReport describes how the manager shut down. It is written as JSON
+// at the end of the shutdown, see WithShutdownReport, for tools checking the
+// shutdown of a program from the outside such as ShutdownCheck.
+type ShutdownReport struct {
+	Duration time.Duration  `json:"duration"` // Of the shutdown phases
+	Err      string         `json:"err,omitempty"`
+	ExitCode int            `json:"exit_code"`
+	Units    []ShutdownUnit `json:"units"` // Units asked to stop, in registration order
+}
+
+// ShutdownUnit describes the stop of a unit.
+type ShutdownUnit struct {
+	Name      string        `json:"name"`
+	Latency   time.Duration `json:"latency"` // From the stop request to Done, or to the report if abandoned
+	Abandoned bool          `json:"abandoned,omitempty"`
+}
+
+// WithShutdownReport writes the ShutdownReport to path at the end of the
+// shutdown. The file is replaced atomically.
+func WithShutdownReport(path string) Option {
+	return func(m *Manager) {
+		m.shutdownReport = path
+	}
+}
+
+// writeShutdownReport writes the shutdown report, if enabled.
+func (m *Manager) writeShutdownReport() {
+	if m.shutdownReport == "" {
+		return
+	}
+
+	now := m.clock.Now()
+	report := ShutdownReport{ExitCode: m.ExitCode(), Units: []ShutdownUnit{}}
+	if err := m.Err(); err != nil {
+		report.Err = err.Error()
+	}
+
+	m.regMu.RLock()
+	if !m.shutdownAt.IsZero() {
+		report.Duration = now.Sub(m.shutdownAt)
+	}
+	for _, w := range m.order {
+		if w.stopAt.IsZero() {
+			continue
+		}
+
+		u := ShutdownUnit{Name: w.name, Latency: w.stopLatency()}
+		if !w.done.Load() {
+			u.Abandoned = true
+			u.Latency = now.Sub(w.stopAt)
+		}
+		report.Units = append(report.Units, u)
+	}
+	m.regMu.RUnlock()
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err == nil {
+		err = writeFileAtomic(m.shutdownReport, data)
+	}
+	if err != nil {
+		m.warnf("", "Could not write the shutdown report: %s\n", err)
+	}
+}
@@ -0,0 +1,149 @@
+package gum
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+)
+
+// ShutdownOn registers signals triggering a graceful shutdown: the manager
+// waits for all units to be done.
+func (m *Manager) ShutdownOn(sig ...os.Signal) {
+	for _, s := range sig {
+		m.logf("Registering shutdown signal: %s\n", s)
+		m.notify(s)
+	}
+
+	m.shutdownSigs = append(m.shutdownSigs, sig...)
+}
+
+// ImmediateShutdownOn registers signals triggering an immediate shutdown:
+// units are notified with the Immediate mode and the manager quits without
+// waiting for them.
+func (m *Manager) ImmediateShutdownOn(sig ...os.Signal) {
+	for _, s := range sig {
+		m.logf("Registering immediate shutdown signal: %s\n", s)
+		m.notify(s)
+	}
+
+	m.immediateSigs = append(m.immediateSigs, sig...)
+}
+
+// WithForceExit exits the process with code when a second shutdown signal
+// is received during the graceful shutdown, as operators expect from hitting
+// Ctrl-C twice: the units still running are abandoned and listed in the log,
+// and the finalizers are skipped. Without it the manager abandons the units
+// and quits with ErrForcedShutdown, see ExitForced.
+func WithForceExit(code int) Option {
+	return func(m *Manager) {
+		m.forceExit = true
+		m.forceExitCode = code
+	}
+}
+
+// signalMode returns the shutdown mode registered for the signal.
+func (m *Manager) signalMode(sig os.Signal) (ShutdownMode, bool) {
+	switch {
+	case in(m.immediateSigs, sig):
+		return Immediate, true
+	case in(m.shutdownSigs, sig):
+		return Graceful, true
+	}
+	return Graceful, false
+}
+
+type signalHook struct {
+	sig  os.Signal
+	hook func()
+}
+
+// HandleSignal registers a handler called on its own goroutine whenever the
+// signal is received, e.g. to dump the status of the units on SIGUSR1:
+//
+//	m.HandleSignal(syscall.SIGUSR1, func() { m.DumpStatus(os.Stderr) })
+//
+// Handlers are called in addition to the shutdown or reload registered for
+// the signal, within the hook timeout and protected against panics, see
+// WithHookTimeout.
+func (m *Manager) HandleSignal(sig os.Signal, handler func()) {
+	if handler == nil {
+		m.invalid(fmt.Errorf("nil handler for signal %s", sig))
+		return
+	}
+
+	m.logf("Registering signal handler: %s\n", sig)
+
+	m.mu.Lock()
+	m.signalHooks = append(m.signalHooks, signalHook{sig, handler})
+	m.mu.Unlock()
+
+	m.notify(sig)
+}
+
+type signalSub struct {
+	sigs []os.Signal
+	c    chan os.Signal
+}
+
+// Signals subscribes the unit to the given OS signals which are delivered on
+// the returned channel. The manager is the only one calling signal.Notify,
+// units should use this method instead of competing with the manager.
+// Like with signal.Notify, signals are dropped if the channel is not ready.
+func (w *WorkUnitManager) Signals(sig ...os.Signal) <-chan os.Signal {
+	return w.manager.subscribeSignals(sig...)
+}
+
+func (m *Manager) subscribeSignals(sig ...os.Signal) <-chan os.Signal {
+	c := make(chan os.Signal, 1)
+
+	m.mu.Lock()
+	m.signalSubs = append(m.signalSubs, signalSub{sigs: sig, c: c})
+	m.mu.Unlock()
+
+	m.notify(sig...)
+
+	return c
+}
+
+// notify relays the signals to the manager, unless signal handling is
+// disabled by the test profile.
+func (m *Manager) notify(sig ...os.Signal) {
+	if m.noSignals {
+		return
+	}
+	signal.Notify(m.signalIn, sig...)
+}
+
+// dispatchSignal forwards the signal to subscribed units and calls its
+// handlers.
+func (m *Manager) dispatchSignal(sig os.Signal) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, h := range m.signalHooks {
+		if h.sig == sig {
+			go m.callHook("signal handler", "", h.hook)
+		}
+	}
+
+	for _, sub := range m.signalSubs {
+		if !in(sub.sigs, sig) {
+			continue
+		}
+
+		select {
+		case sub.c <- sig:
+		default:
+		}
+	}
+}
+
+// Test if signal is in array
+func in(arr []os.Signal, sig os.Signal) bool {
+	for _, s := range arr {
+		if s == sig {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package gum
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// signalWorker forwards the signals it subscribed to
+type signalWorker struct {
+	subscribed chan bool
+	received   chan os.Signal
+}
+
+func (w *signalWorker) Run(um UnitManager) {
+	sigs := um.Signals(syscall.SIGUSR1)
+	w.subscribed <- true
+
+	for {
+		select {
+		case sig := <-sigs:
+			w.received <- sig
+		case <-um.ShouldStop():
+			um.Done()
+			return
+		}
+	}
+}
+
+func TestUnitSignals(t *testing.T) {
+	manager := NewManager()
+	manager.ShutdownOn(os.Interrupt)
+
+	worker := &signalWorker{
+		subscribed: make(chan bool),
+		received:   make(chan os.Signal, 1),
+	}
+	manager.AddUnit(worker, "")
+
+	go manager.Run()
+	<-worker.subscribed
+
+	manager.signalIn <- syscall.SIGUSR1
+
+	select {
+	case sig := <-worker.received:
+		if sig != syscall.SIGUSR1 {
+			t.Fatalf("unexpected signal %s", sig)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("unit did not receive subscribed signal")
+	}
+
+	manager.signalIn <- os.Interrupt
+	<-manager.Quit
+}
+
+func TestHandleSignal(t *testing.T) {
+	manager := NewManager()
+	manager.ShutdownOn(os.Interrupt)
+	manager.AddUnit(&readyWorker{}, "", WithName("api"))
+
+	var dump bytes.Buffer
+	dumped := make(chan struct{})
+	manager.HandleSignal(syscall.SIGUSR1, func() {
+		manager.DumpStatus(&dump)
+		close(dumped)
+	})
+	interrupted := make(chan struct{})
+	manager.HandleSignal(os.Interrupt, func() { close(interrupted) })
+
+	sub := manager.Subscribe()
+	go manager.Run()
+	waitEvent(t, sub, EventStartupComplete)
+
+	manager.signalIn <- syscall.SIGUSR1
+	select {
+	case <-dumped:
+	case <-time.After(time.Second):
+		t.Fatal("signal handler not called")
+	}
+	if !strings.Contains(dump.String(), "UNIT") || !strings.Contains(dump.String(), "api") {
+		t.Fatalf("unexpected status dump:\n%s", dump.String())
+	}
+
+	// Handlers don't replace the shutdown of the signal
+	manager.signalIn <- os.Interrupt
+	<-manager.Quit
+	select {
+	case <-interrupted:
+	case <-time.After(time.Second):
+		t.Fatal("shutdown signal handler not called")
+	}
+
+	invalid := NewManager()
+	invalid.HandleSignal(syscall.SIGUSR2, nil)
+	if err := invalid.Validate(); err == nil {
+		t.Fatal("expected a nil handler to be reported")
+	}
+}
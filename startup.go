@@ -0,0 +1,123 @@
+package gum
+
+// Ready notifies the manager that the unit is initialized. It releases the
+// startup slot held by the unit, see WithStartupConcurrency. It can be called
+// more than once.
+func (w *WorkUnitManager) Ready() {
+	m := w.manager
+	if m.strict && w.done.Load() {
+		m.logf("strict: <%s> called Ready after Done\n", w)
+	}
+
+	m.regMu.Lock()
+	if w.ready {
+		m.regMu.Unlock()
+		return
+	}
+	w.ready = true
+	w.readyAt = m.clock.Now()
+	if w.readyC != nil {
+		close(w.readyC)
+	}
+	m.touch()
+	m.regMu.Unlock()
+
+	m.releaseSlot(w)
+	m.unitSettled(w)
+	m.emitUnit(EventUnitReady, w, nil)
+}
+
+// startUnits starts the initial units in dependency order, each once its
+// dependencies are ready. With a startup concurrency limit, each unit holds a
+// slot until it is ready or done. A unit whose failure to wait on its
+// dependencies is contained is skipped, see WithFailurePolicy.
+func (m *Manager) startUnits(units []*WorkUnitManager) {
+	defer close(m.startDone)
+
+	for _, w := range units {
+		if !m.unitEnabled(w) {
+			continue
+		}
+		if !m.waitDeps(w) {
+			if m.depsContained(w) {
+				m.unitSettled(w)
+				continue
+			}
+			return
+		}
+
+		if m.startSem != nil {
+			select {
+			case m.startSem <- struct{}{}:
+				w.slotHeld.Store(true)
+			case <-m.startStop:
+				return
+			}
+		}
+
+		m.startMu.Lock()
+		m.startUnit(w)
+		m.startMu.Unlock()
+	}
+}
+
+// depsContained reports whether the unit failed waiting on its dependencies
+// with a contained failure, while the manager is not stopping.
+func (m *Manager) depsContained(w *WorkUnitManager) bool {
+	select {
+	case <-m.startStop:
+		return false
+	default:
+	}
+
+	m.regMu.RLock()
+	defer m.regMu.RUnlock()
+	return w.failurePolicy == FailureContain && w.state == Failed
+}
+
+// startUnit launches the unit. It must be called with startMu held.
+func (m *Manager) startUnit(w *WorkUnitManager) {
+	if w.description != "" {
+		m.unitLogf(w.name, "Starting <%s>: %s\n", w, w.description)
+	} else {
+		m.unitLogf(w.name, "Starting <%s>\n", w)
+	}
+	w.started.Store(true)
+	m.idle.Store(false)
+	m.setState(w, Running, nil)
+	m.clockIn(w)
+	go w.run()
+	m.emitUnit(EventUnitStarted, w, nil)
+
+	if w.readyTimeout > 0 {
+		go m.protect("ready timeout", func() { m.watchReady(w) })
+	}
+	if w.recycleEvery > 0 {
+		go m.protect("recycling", func() { m.recycle(w) })
+	}
+}
+
+// unitSettled counts the unit as ready, done, disabled or failed for good for
+// the startup completion. It is a no-op past the first call for a unit.
+func (m *Manager) unitSettled(w *WorkUnitManager) {
+	if !w.settled.CompareAndSwap(false, true) {
+		return
+	}
+	if m.readyPending.Add(-1) == 0 {
+		m.allReadyOnce.Do(func() { close(m.allReady) })
+	}
+}
+
+// stopStarting interrupts startUnits and the startup report and waits for
+// them to return. Units not started yet are never started.
+func (m *Manager) stopStarting() {
+	m.startStopOnce.Do(func() { close(m.startStop) })
+	<-m.startDone
+	<-m.reportDone
+}
+
+func (m *Manager) releaseSlot(w *WorkUnitManager) {
+	if w.slotHeld.CompareAndSwap(true, false) {
+		<-m.startSem
+	}
+}
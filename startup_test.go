@@ -0,0 +1,84 @@
+package gum
+
+import (
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// slowStartWorker takes some time to initialize before being ready
+type slowStartWorker struct {
+	starting *atomic.Int32
+	max      *atomic.Int32
+}
+
+func (w *slowStartWorker) Run(um UnitManager) {
+	n := w.starting.Add(1)
+	for {
+		max := w.max.Load()
+		if n <= max || w.max.CompareAndSwap(max, n) {
+			break
+		}
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	w.starting.Add(-1)
+	um.Ready()
+
+	<-um.ShouldStop()
+	um.Done()
+}
+
+func TestStartupConcurrency(t *testing.T) {
+	manager := NewManager(WithStartupConcurrency(2))
+	manager.ShutdownOn(os.Interrupt)
+
+	var starting, max atomic.Int32
+	for i := 0; i < 6; i++ {
+		manager.AddUnit(&slowStartWorker{&starting, &max}, "")
+	}
+
+	sub := manager.Subscribe()
+	go manager.Run()
+
+	for ready := 0; ready < 6; {
+		select {
+		case ev := <-sub.Events():
+			if ev.Kind == EventUnitReady {
+				ready++
+			}
+		case <-time.After(time.Second):
+			t.Fatal("units did not get ready")
+		}
+	}
+
+	manager.signalIn <- os.Interrupt
+	<-manager.Quit
+
+	if max.Load() != 2 {
+		t.Fatalf("expected at most 2 units starting at once, got %d", max.Load())
+	}
+}
+
+func TestShutdownDuringStartup(t *testing.T) {
+	manager := NewManager(WithStartupConcurrency(1))
+	manager.ShutdownOn(os.Interrupt)
+
+	// The first unit never gets ready, the second is never started
+	manager.AddUnit(&stopWorker{}, "")
+	manager.AddUnit(&stopWorker{}, "")
+
+	go manager.Run()
+	manager.signalIn <- os.Interrupt
+
+	select {
+	case <-manager.Quit:
+	case <-time.After(time.Second):
+		t.Fatal("manager did not quit during startup")
+	}
+
+	if manager.order[1].started.Load() {
+		t.Fatal("expected second unit not to be started")
+	}
+}
@@ -0,0 +1,329 @@
+package gum
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// UnitState is the lifecycle state of a unit.
+type UnitState int
+
+const (
+	// Starting units are registered but not launched yet.
+	Starting UnitState = iota
+
+	// Running units have been launched.
+	Running
+
+	// Stopping units have been asked to stop.
+	Stopping
+
+	// Stopped units are done.
+	Stopped
+
+	// Failed units called Panic.
+	Failed
+
+	// Disabled units are not started, see WithFlagProvider.
+	Disabled
+
+	// Parked units are running but intentionally dormant, see Park.
+	Parked
+)
+
+var unitStateNames = [...]string{
+	Starting: "starting",
+	Running:  "running",
+	Stopping: "stopping",
+	Stopped:  "stopped",
+	Failed:   "failed",
+	Disabled: "disabled",
+	Parked:   "parked",
+}
+
+func (s UnitState) String() string {
+	if s >= 0 && int(s) < len(unitStateNames) {
+		return unitStateNames[s]
+	}
+	return fmt.Sprintf("UnitState(%d)", int(s))
+}
+
+func parseUnitState(s string) (UnitState, error) {
+	for state, name := range unitStateNames {
+		if name == s {
+			return UnitState(state), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown unit state %q", s)
+}
+
+// UnitStatus is the status of a unit at the time of a Snapshot. Timestamps
+// are wall clock times for display, durations are computed from the
+// monotonic clock and are not affected by clock adjustments.
+type UnitStatus struct {
+	Name        string
+	Description string
+	Labels      map[string]string // Must not be modified
+	Family      string            // Template family, see InstantiateTemplate
+	State       UnitState
+	Ready       bool
+	StartedAt   time.Time
+	StoppedAt   time.Time
+	Err         error // Last error of the unit
+	Panics      int
+	Restarts    int // Earlier instances of the unit, see WithRestart
+
+	// Uptime is the time the unit has been running, up to when it stopped.
+	Uptime time.Duration
+
+	// StopLatency is the time the unit took to be done once asked to stop.
+	StopLatency time.Duration
+
+	// ParkedUntil is when a parked unit is woken, zero if it is parked until
+	// woken with Wake or if it is not parked.
+	ParkedUntil time.Time
+
+	// StopLatencies is the distribution of the stop latency of the unit and
+	// of the previous instances it replaced, see SwapUnit and WithRecycle.
+	StopLatencies Percentiles
+}
+
+// Snapshot is a point-in-time view of all units. Version is incremented on
+// every change of the registry or of a unit state.
+type Snapshot struct {
+	Version uint64
+	Time    time.Time
+	Uptime  time.Duration // Manager uptime
+	Units   []UnitStatus
+
+	// Build is the build metadata, see WithBuildInfo.
+	Build *BuildInfo
+
+	// Startup is the startup report, nil until the startup is complete.
+	Startup *StartupReport
+
+	// Shutdown are the reports of the shutdown phases run so far.
+	Shutdown []PhaseReport
+
+	// Pressure is the last runtime pressure sampled, with
+	// WithRuntimeMetrics once the first sample is taken.
+	Pressure *Pressure
+
+	// EventsDropped are the events dropped by the subscribers whose buffer
+	// was full, by overflow policy, see Subscription.Dropped.
+	EventsDropped map[string]uint64
+
+	// Remotes are the last known status of the managers supervised with
+	// RemoteManager, by unit name.
+	Remotes map[string]Snapshot
+
+	// Subtrees are the status of the child managers run with Subtree, by
+	// unit name.
+	Subtrees map[string]Snapshot
+}
+
+// Snapshot returns a consistent view of the registered units, in
+// registration order. It is safe to call concurrently with any other
+// manager operation.
+func (m *Manager) Snapshot() Snapshot {
+	m.regMu.RLock()
+	defer m.regMu.RUnlock()
+
+	now := m.clock.Now()
+	snap := Snapshot{
+		Version:  m.version,
+		Time:     now,
+		Uptime:   m.uptime(now),
+		Units:    make([]UnitStatus, len(m.order)),
+		Startup:  m.startup,
+		Build:    m.build,
+		Shutdown: append([]PhaseReport(nil), m.phases...),
+
+		EventsDropped: m.events.droppedEvents(),
+	}
+	if m.runtimeMetrics {
+		snap.Pressure = m.pressure.Load()
+	}
+
+	for i, w := range m.order {
+		snap.Units[i] = w.status(now)
+
+		if r, ok := w.unit.(*Remote); ok {
+			if remote, ok := r.RemoteSnapshot(); ok {
+				if snap.Remotes == nil {
+					snap.Remotes = make(map[string]Snapshot)
+				}
+				snap.Remotes[w.name] = remote
+			}
+		}
+		if s, ok := w.unit.(*SubtreeUnit); ok && w.started.Load() && !w.done.Load() {
+			if child := s.Manager(); child != nil {
+				if snap.Subtrees == nil {
+					snap.Subtrees = make(map[string]Snapshot)
+				}
+				snap.Subtrees[w.name] = child.Snapshot()
+			}
+		}
+	}
+
+	return snap
+}
+
+// Units returns the status of the registered units in registration order,
+// keeping only the latest instance of units replaced under the same name.
+func (m *Manager) Units() []UnitStatus {
+	m.regMu.RLock()
+	defer m.regMu.RUnlock()
+
+	now := m.clock.Now()
+	units := make([]UnitStatus, len(m.order))
+	for i, w := range m.order {
+		units[i] = w.status(now)
+	}
+	return latestUnits(units)
+}
+
+// DumpStatus writes the status of the units as a table, e.g. to stderr from
+// a signal handler, see HandleSignal. The units of running subtrees are
+// listed under their unit, as subtree/unit.
+func (m *Manager) DumpStatus(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "UNIT\tSTATE\tREADY\tUPTIME\tRESTARTS\tERROR\n")
+	m.dumpUnits(tw, "")
+	return tw.Flush()
+}
+
+// dumpUnits writes the status rows of the units, their names prefixed.
+func (m *Manager) dumpUnits(w io.Writer, prefix string) {
+	for _, u := range m.Units() {
+		var err string
+		if u.Err != nil {
+			err = strconv.Quote(u.Err.Error())
+		}
+		fmt.Fprintf(w, "%s\t%s\t%t\t%s\t%d\t%s\n",
+			prefix+u.Name, u.State, u.Ready, u.Uptime.Round(time.Millisecond), u.Restarts, err)
+
+		if child := m.subtree(u.Name); child != nil {
+			child.dumpUnits(w, prefix+u.Name+"/")
+		}
+	}
+}
+
+// subtree returns the child manager run by the named unit, nil if it is not
+// a running subtree.
+func (m *Manager) subtree(name string) *Manager {
+	m.regMu.RLock()
+	defer m.regMu.RUnlock()
+
+	w, ok := m.workers[name]
+	if !ok || !w.started.Load() || w.done.Load() {
+		return nil
+	}
+	if s, ok := w.unit.(*SubtreeUnit); ok {
+		return s.Manager()
+	}
+	return nil
+}
+
+// Status returns the status of the unit registered under name, and whether
+// it exists.
+func (m *Manager) Status(name string) (UnitStatus, bool) {
+	m.regMu.RLock()
+	defer m.regMu.RUnlock()
+
+	w, ok := m.workers[name]
+	if !ok {
+		return UnitStatus{}, false
+	}
+	return w.status(m.clock.Now()), true
+}
+
+// status returns the status of the unit. It must be called with the
+// manager's regMu held.
+func (w *WorkUnitManager) status(now time.Time) UnitStatus {
+	return UnitStatus{
+		Name:        w.name,
+		Description: w.description,
+		Labels:      w.info.Labels,
+		Family:      w.family,
+		State:       w.state,
+		Ready:       w.ready,
+		StartedAt:   w.startedAt,
+		StoppedAt:   w.stoppedAt,
+		Err:         w.err,
+		Panics:      w.panics.total,
+		Restarts:    w.restarts,
+		Uptime:      w.uptime(now),
+		StopLatency: w.stopLatency(),
+		ParkedUntil: w.parkedUntil,
+
+		StopLatencies: w.stopLatencies.percentiles(),
+	}
+}
+
+// setState changes the state of the unit. Failed units keep their state and
+// stopped units can't be stopping again.
+func (m *Manager) setState(w *WorkUnitManager, state UnitState, err error) {
+	m.regMu.Lock()
+	defer m.regMu.Unlock()
+
+	if w.state == Failed || (w.state == Stopped && state == Stopping) {
+		return
+	}
+
+	w.state = state
+	switch state {
+	case Running:
+		w.startedAt = m.clock.Now()
+	case Stopping:
+		w.stopAt = m.clock.Now()
+	case Stopped, Failed:
+		w.stoppedAt = m.clock.Now()
+		if !w.stopAt.IsZero() {
+			w.stopLatencies.record(w.stoppedAt.Sub(w.stopAt))
+		}
+	}
+	if err != nil {
+		w.err = err
+	}
+	m.touch()
+}
+
+// uptime returns the manager uptime at the given time.
+func (m *Manager) uptime(now time.Time) time.Duration {
+	if m.startedAt.IsZero() {
+		return 0
+	}
+	return now.Sub(m.startedAt)
+}
+
+// uptime returns the time the unit has been running at the given time. It
+// must be called with the manager's regMu held.
+func (w *WorkUnitManager) uptime(now time.Time) time.Duration {
+	switch {
+	case w.startedAt.IsZero():
+		return 0
+	case !w.stoppedAt.IsZero():
+		return w.stoppedAt.Sub(w.startedAt)
+	}
+	return now.Sub(w.startedAt)
+}
+
+// stopLatency returns the time the unit took to be done once asked to stop.
+// It must be called with the manager's regMu held.
+func (w *WorkUnitManager) stopLatency() time.Duration {
+	if w.stopAt.IsZero() || w.stoppedAt.IsZero() {
+		return 0
+	}
+	return w.stoppedAt.Sub(w.stopAt)
+}
+
+func (m *Manager) unitStopLatency(w *WorkUnitManager) time.Duration {
+	m.regMu.RLock()
+	defer m.regMu.RUnlock()
+
+	return w.stopLatency()
+}
@@ -0,0 +1,154 @@
+package gum
+
+import (
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+// describedWorker is a stopWorker with a description
+type describedWorker struct{ stopWorker }
+
+func (w *describedWorker) Describe() string { return "does nothing" }
+
+func TestSnapshot(t *testing.T) {
+	manager := NewManager()
+	manager.ShutdownOn(os.Interrupt)
+	manager.AddUnit(&describedWorker{}, "")
+
+	snap := manager.Snapshot()
+	if len(snap.Units) != 1 || snap.Units[0].State != Starting {
+		t.Fatalf("expected one starting unit, got %+v", snap.Units)
+	}
+	if snap.Units[0].Description != "does nothing" {
+		t.Fatalf("unexpected description %q", snap.Units[0].Description)
+	}
+
+	sub := manager.Subscribe()
+	go manager.Run()
+	for ev := range sub.Events() {
+		if ev.Kind == EventUnitStarted {
+			break
+		}
+	}
+
+	running := manager.Snapshot()
+	if running.Units[0].State != Running || running.Units[0].StartedAt.IsZero() {
+		t.Fatalf("expected running unit, got %+v", running.Units[0])
+	}
+	if running.Version <= snap.Version {
+		t.Fatalf("expected version to increase, got %d then %d", snap.Version, running.Version)
+	}
+
+	manager.signalIn <- os.Interrupt
+	<-manager.Quit
+
+	if state := manager.Snapshot().Units[0].State; state != Stopped {
+		t.Fatalf("expected stopped unit, got %s", state)
+	}
+}
+
+func TestSnapshotFailed(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&panicWorker{}, "")
+	manager.Run()
+
+	status := manager.Snapshot().Units[0]
+	if status.State != Failed || status.Err == nil || status.Err.Error() != "boom" {
+		t.Fatalf("expected failed unit, got %+v", status)
+	}
+}
+
+func TestSnapshotUnderChurn(t *testing.T) {
+	manager := NewManager()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			manager.AddUnit(&stopWorker{}, "churn")
+		}
+	}()
+
+	var last Snapshot
+	for i := 0; i < 1000; i++ {
+		snap := manager.Snapshot()
+		if snap.Version < last.Version {
+			t.Fatalf("version went backward: %d then %d", last.Version, snap.Version)
+		}
+		if uint64(len(snap.Units)) != snap.Version {
+			t.Fatalf("torn snapshot: %d units at version %d", len(snap.Units), snap.Version)
+		}
+		last = snap
+	}
+	wg.Wait()
+
+}
+
+// slowStopWorker takes some time to drain once stopped
+type slowStopWorker struct{}
+
+func (w *slowStopWorker) Run(um UnitManager) {
+	<-um.ShouldStop()
+	time.Sleep(20 * time.Millisecond)
+	um.Done()
+}
+
+func TestStopLatency(t *testing.T) {
+	manager := NewManager()
+	manager.ShutdownOn(os.Interrupt)
+	manager.AddUnit(&slowStopWorker{}, "")
+	sub := manager.Subscribe()
+
+	go manager.Run()
+	manager.signalIn <- os.Interrupt
+	<-manager.Quit
+	sub.Close()
+
+	for ev := range sub.Events() {
+		if ev.Kind == EventUnitDone && ev.Latency < 20*time.Millisecond {
+			t.Fatalf("unexpected stop latency in event: %s", ev.Latency)
+		}
+		if ev.Kind == EventManagerQuit && ev.Uptime < 20*time.Millisecond {
+			t.Fatalf("unexpected manager uptime in event: %s", ev.Uptime)
+		}
+	}
+
+	snap := manager.Snapshot()
+	status := snap.Units[0]
+	if status.StopLatency < 20*time.Millisecond {
+		t.Fatalf("unexpected stop latency: %s", status.StopLatency)
+	}
+	if status.Uptime < status.StopLatency || status.Uptime > snap.Uptime {
+		t.Fatalf("unexpected unit uptime %s, manager uptime %s", status.Uptime, snap.Uptime)
+	}
+}
+
+func TestUnitsStatus(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&flakyWorker{failures: 1}, "", WithName("flaky"),
+		WithRestart(RestartOnFailure), WithRestartBackoff(time.Millisecond, time.Millisecond))
+	manager.AddUnit(&readyWorker{}, "", WithName("ready"))
+
+	go manager.Run()
+	defer func() {
+		manager.Stop()
+		<-manager.Quit
+	}()
+	waitRestarted(t, manager, 0, 1)
+
+	units := manager.Units()
+	if len(units) != 2 || units[0].Name != "flaky" || units[1].Name != "ready" {
+		t.Fatalf("expected the latest instance of each unit in registration order, got %+v", units)
+	}
+
+	u, ok := manager.Status("flaky")
+	if !ok || u.State != Running || u.Restarts != 1 || u.StartedAt.IsZero() {
+		t.Fatalf("unexpected status of the restarted unit %+v", u)
+	}
+	if _, ok := manager.Status("unknown"); ok {
+		t.Fatal("expected no status for an unknown unit")
+	}
+}
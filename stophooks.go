@@ -0,0 +1,79 @@
+package gum
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// StopHook is run around the stop of a unit by the shutdown, see WithPreStop
+// and WithPostStop. Its context is done at the end of the stop phase budget.
+type StopHook func(ctx context.Context) error
+
+// WithPreStop adds a hook run by the shutdown before the unit is asked to
+// stop, e.g. to deregister from a load balancer or stop accepting
+// connections while the requests in flight are served. The stop event is
+// sent once the pre-stop hooks returned. Hooks run in the order they were
+// added, their errors are joined to the shutdown cause.
+func WithPreStop(hook StopHook) UnitOption {
+	return func(w *WorkUnitManager) {
+		if hook == nil {
+			w.invalid(fmt.Errorf("nil pre-stop hook"))
+			return
+		}
+		w.preStop = append(w.preStop, hook)
+	}
+}
+
+// WithPostStop adds a cleanup hook run by the shutdown once the unit is
+// done, before the finalize phase. Hooks run in the order they were added,
+// their errors are joined to the shutdown cause.
+//
+// Stop hooks are only run by a graceful shutdown, not when the unit is
+// restarted, removed or abandoned.
+func WithPostStop(hook StopHook) UnitOption {
+	return func(w *WorkUnitManager) {
+		if hook == nil {
+			w.invalid(fmt.Errorf("nil post-stop hook"))
+			return
+		}
+		w.postStop = append(w.postStop, hook)
+	}
+}
+
+// runStopHooks runs the stop hooks of a unit within the hook timeout,
+// protected against panics. The remaining hooks are skipped once ctx is
+// done.
+func (m *Manager) runStopHooks(ctx context.Context, w *WorkUnitManager, what string, hooks []StopHook) {
+	for i, hook := range hooks {
+		if ctx.Err() != nil {
+			m.warnf(w.name, "stop phase timeout exceeded, skipping %d %s hooks of <%s>\n",
+				len(hooks)-i, what, w)
+			return
+		}
+
+		var err error
+		if !m.callHook(what+" hook", w.name, func() { err = hook(ctx) }) {
+			continue
+		}
+		if err != nil {
+			m.warnf(w.name, "<%s> %s: %s\n", w, what, err)
+			m.addErr(fmt.Errorf("<%s> %s: %w", w, what, err))
+		}
+	}
+}
+
+// waitStopHooks waits for the running stop hooks, at most until ctx is done.
+func (m *Manager) waitStopHooks(ctx context.Context, hooks *sync.WaitGroup) {
+	done := make(chan struct{})
+	go func() {
+		hooks.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		m.warnf("", "stop phase timeout exceeded, not waiting for the post-stop hooks\n")
+	}
+}
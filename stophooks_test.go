@@ -0,0 +1,60 @@
+package gum
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestStopHooks(t *testing.T) {
+	var mu sync.Mutex
+	var steps []string
+	step := func(s string) {
+		mu.Lock()
+		defer mu.Unlock()
+		steps = append(steps, s)
+	}
+	hook := func(s string, err error) StopHook {
+		return func(context.Context) error {
+			step(s)
+			return err
+		}
+	}
+
+	manager := NewManager(WithFinalizer(func(context.Context) error {
+		step("finalize")
+		return nil
+	}))
+	manager.AddUnit(funcWorker(func(um UnitManager) {
+		um.Ready()
+		<-um.ShouldStop()
+		step("stop")
+		um.Done()
+	}), "", WithName("server"),
+		WithPreStop(hook("deregister", nil)),
+		WithPreStop(hook("close listener", errors.New("already closed"))),
+		WithPostStop(hook("cleanup", nil)),
+	)
+
+	sub := manager.Subscribe()
+	quit := runAsync(manager)
+	waitEvent(t, sub, EventStartupComplete)
+	manager.Stop()
+	<-quit
+
+	want := []string{"deregister", "close listener", "stop", "cleanup", "finalize"}
+	if !slices.Equal(steps, want) {
+		t.Fatalf("expected the steps %v, got %v", want, steps)
+	}
+	if err := manager.Err(); err == nil || err.Error() != "<server> pre-stop: already closed" {
+		t.Fatalf("expected the hook error to be the shutdown cause, got %v", err)
+	}
+
+	manager = NewManager()
+	manager.AddUnit(&readyWorker{}, "", WithPreStop(nil))
+	if err := manager.Validate(); err == nil {
+		t.Fatal("expected a nil hook to be reported")
+	}
+}
@@ -0,0 +1,100 @@
+package gum
+
+import (
+	"fmt"
+	"time"
+)
+
+// Strategy defines how the manager handles the failure of a unit, see
+// WithStrategy.
+type Strategy int
+
+const (
+	// StrategyNone only applies the restart policy of the failed unit, see
+	// WithRestart: the failure of a unit which is not restarted shuts the
+	// manager down (default).
+	StrategyNone Strategy = iota
+
+	// OneForOne restarts the failed unit only.
+	OneForOne
+
+	// OneForAll restarts the failed unit and all the other units.
+	OneForAll
+
+	// RestForOne restarts the failed unit and the units registered after
+	// it, e.g. the units depending on it.
+	RestForOne
+)
+
+var strategyNames = [...]string{
+	StrategyNone: "none",
+	OneForOne:    "one-for-one",
+	OneForAll:    "one-for-all",
+	RestForOne:   "rest-for-one",
+}
+
+func (s Strategy) String() string {
+	if s >= 0 && int(s) < len(strategyNames) {
+		return strategyNames[s]
+	}
+	return fmt.Sprintf("Strategy(%d)", int(s))
+}
+
+// WithStrategy sets the supervision strategy of the manager. With a
+// strategy, failed units are restarted even without restart policy, as
+// with RestartOnFailure, and the strategy selects the other units restarted
+// along: none, all of them or the units registered after the failed one.
+// The restart intensity of the failed unit still applies, past it the
+// failure shuts the manager down. Subsystems get their own strategy with a
+// Subtree.
+func WithStrategy(s Strategy) Option {
+	return func(m *Manager) {
+		if s < StrategyNone || s > RestForOne {
+			m.invalid(fmt.Errorf("invalid supervision strategy %d", int(s)))
+			return
+		}
+		m.strategy = s
+	}
+}
+
+// restartPolicy returns the restart policy applied to the unit.
+func (m *Manager) restartPolicy(w *WorkUnitManager) RestartPolicy {
+	if w.restartPolicy == RestartNever && m.currentStrategy() != StrategyNone {
+		return RestartOnFailure
+	}
+	return w.restartPolicy
+}
+
+// restartSiblings restarts the units selected by the strategy along with the
+// failed unit, once the restart delay of the failed unit elapsed.
+func (m *Manager) restartSiblings(failed *WorkUnitManager, delay time.Duration) {
+	strategy := m.currentStrategy()
+	if strategy != OneForAll && strategy != RestForOne {
+		return
+	}
+
+	m.regMu.RLock()
+	rank := make(map[string]int, len(m.order))
+	for i, w := range m.order {
+		if _, ok := rank[w.lineage]; !ok {
+			rank[w.lineage] = i
+		}
+	}
+	var siblings []*WorkUnitManager
+	for _, w := range m.order {
+		switch {
+		case w == failed || !w.started.Load() || w.done.Load() || w.Stopping() || w.removed.Load():
+		case strategy == RestForOne && rank[w.lineage] <= rank[failed.lineage]:
+		default:
+			siblings = append(siblings, w)
+		}
+	}
+	m.regMu.RUnlock()
+
+	reason := fmt.Sprintf("%s after <%s> failed", strategy, failed.name)
+	for _, w := range siblings {
+		if w.restartChecked.CompareAndSwap(false, true) {
+			m.restartWith(w, m.unitTrace(failed), reason, delay)
+		}
+	}
+}
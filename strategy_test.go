@@ -0,0 +1,145 @@
+package gum
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// failingWorker fails each time it is sent a value
+type failingWorker struct {
+	fail chan struct{}
+}
+
+func (w *failingWorker) Run(um UnitManager) {
+	um.Ready()
+	select {
+	case <-um.ShouldStop():
+		um.Done()
+	case <-w.fail:
+		um.Panic(errFlaky)
+	}
+}
+
+func TestStrategies(t *testing.T) {
+	for _, tt := range []struct {
+		strategy  Strategy
+		restarted []string
+	}{
+		{OneForOne, []string{"b"}},
+		{OneForAll, []string{"a", "b", "c"}},
+		{RestForOne, []string{"b", "c"}},
+	} {
+		t.Run(tt.strategy.String(), func(t *testing.T) {
+			failing := &failingWorker{fail: make(chan struct{})}
+			manager := NewManager(WithStrategy(tt.strategy))
+			manager.AddUnit(&readyWorker{}, "", WithName("a"))
+			manager.AddUnit(failing, "", WithName("b"), WithRestartBackoff(time.Millisecond, time.Millisecond))
+			manager.AddUnit(&readyWorker{}, "", WithName("c"))
+
+			sub := manager.Subscribe()
+			quit := runAsync(manager)
+			waitEvent(t, sub, EventStartupComplete)
+			failing.fail <- struct{}{}
+
+			restarts := make(map[string]bool)
+			for range tt.restarted {
+				restarts[waitEvent(t, sub, EventUnitRestart).Unit] = true
+			}
+			for range tt.restarted {
+				waitEvent(t, sub, EventUnitReady)
+			}
+			manager.Stop()
+			<-quit
+
+			for _, name := range tt.restarted {
+				if !restarts[name] {
+					t.Errorf("expected <%s> to be restarted, got %v", name, restarts)
+				}
+			}
+			for _, u := range manager.Units() {
+				want := 0
+				if restarts[u.Name] {
+					want = 1
+				}
+				if u.Restarts != want {
+					t.Errorf("expected %d restarts of <%s>, got %d", want, u.Name, u.Restarts)
+				}
+			}
+			if manager.Err() != nil {
+				t.Fatalf("unexpected shutdown cause: %v", manager.Err())
+			}
+		})
+	}
+}
+
+func TestStrategyNone(t *testing.T) {
+	failing := &failingWorker{fail: make(chan struct{}, 1)}
+	failing.fail <- struct{}{}
+	manager := NewManager()
+	manager.AddUnit(failing, "", WithName("b"))
+	manager.Run()
+
+	if !errors.Is(manager.Err(), errFlaky) {
+		t.Fatalf("expected the failure to shut the manager down, got %v", manager.Err())
+	}
+	if NewManager(WithStrategy(Strategy(7))).Validate() == nil {
+		t.Fatal("expected an unknown strategy to be invalid")
+	}
+}
+
+// slowStartHandler discards the logs, slowly for the start of the units
+// named with the prefix so the window between their registration and their
+// start is wide.
+type slowStartHandler string
+
+func (slowStartHandler) Enabled(context.Context, slog.Level) bool { return true }
+func (h slowStartHandler) WithAttrs([]slog.Attr) slog.Handler     { return h }
+func (h slowStartHandler) WithGroup(string) slog.Handler          { return h }
+
+func (h slowStartHandler) Handle(_ context.Context, r slog.Record) error {
+	if strings.HasPrefix(r.Message, "Starting <"+string(h)) {
+		time.Sleep(time.Millisecond)
+	}
+	return nil
+}
+
+func TestStrategyWhileAdding(t *testing.T) {
+	failing := &failingWorker{fail: make(chan struct{})}
+	manager := NewManager(WithStrategy(OneForAll), WithSlog(slog.New(slowStartHandler("sibling"))))
+	manager.AddUnit(failing, "", WithName("failing"),
+		WithRestartBackoff(time.Millisecond, time.Millisecond), WithRestartIntensity(100, time.Minute))
+
+	sub := manager.Subscribe()
+	quit := runAsync(manager)
+	waitEvent(t, sub, EventStartupComplete)
+	sub.Close()
+
+	// Units started at runtime race with the restarts of the siblings, see
+	// go test -race
+	added := make(chan struct{})
+	go func() {
+		defer close(added)
+		for i := 0; i < 30; i++ {
+			manager.AddUnit(&readyWorker{}, "sibling")
+		}
+	}()
+	for failures := true; failures; {
+		select {
+		case <-added:
+			failures = false
+		case failing.fail <- struct{}{}:
+		case <-time.After(time.Second):
+			t.Fatal("expected the failing unit to be restarted")
+		}
+	}
+
+	manager.Stop()
+	<-quit
+	if manager.Err() != nil {
+		t.Fatalf("unexpected shutdown cause: %v", manager.Err())
+	}
+}
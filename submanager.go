@@ -0,0 +1,134 @@
+package gum
+
+import (
+	"slices"
+	"sync"
+)
+
+// SubManager creates a child manager scoping a subsystem, run by m as a unit
+// named name, see Subtree. The child inherits the logging, baggage, timeouts
+// and policies of m, which opts override, and its baggage is tagged with
+// scope=name. Units are added through the returned Subsystem, before or
+// while m runs:
+//
+//	ingest := manager.SubManager("ingest", gum.WithShutdownTimeout(time.Minute))
+//	ingest.AddUnit(fetcher, "fetcher")
+//	ingest.AddUnit(parser, "parser", gum.After("fetcher"))
+//
+// The child does not handle OS signals and its panic policy is PanicShutdown
+// unless overridden: a failure shuts the subsystem down and fails its unit in
+// m, whose policies apply. As a manager only runs once, a restart of the
+// unit runs a new child with the same options and units, see
+// Subsystem.Manager for the current one. The child appears as a single unit
+// of m, expanded in the Subtrees of its Snapshot and in DumpStatus.
+func (m *Manager) SubManager(name string, opts ...Option) *Subsystem {
+	s := &Subsystem{opts: append([]Option{m.inherit(name)}, opts...)}
+	s.current = NewManager(s.opts...)
+	s.fresh = true
+
+	m.AddUnit(Subtree(s.next), "", WithName(name))
+	return s
+}
+
+// Subsystem is the handle of a child manager created by SubManager. It
+// resolves the current child across restarts of the subsystem.
+type Subsystem struct {
+	mu      sync.Mutex
+	opts    []Option
+	current *Manager
+	fresh   bool             // current was not run yet
+	pending []func(*Manager) // Units added once current shut down
+}
+
+// Manager returns the current child manager, replaced on each restart of
+// the subsystem.
+func (s *Subsystem) Manager() *Manager {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.current
+}
+
+// AddUnit adds a unit to the current child manager, see Manager.AddUnit, or
+// to the next one if the current child is shut down. The unit is part of
+// the later children run by the restarts of the subsystem.
+func (s *Subsystem) AddUnit(unit WorkUnit, name string, opts ...UnitOption) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	select {
+	case <-s.current.startStop:
+		s.pending = append(s.pending, func(m *Manager) { m.AddUnit(unit, name, opts...) })
+	default:
+		s.current.AddUnit(unit, name, opts...)
+	}
+}
+
+// next returns the child manager to run, a new one after the first run.
+func (s *Subsystem) next() *Manager {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.fresh {
+		s.current = s.current.respawn(s.opts)
+		for _, add := range s.pending {
+			add(s.current)
+		}
+		s.pending = nil
+	}
+	s.fresh = false
+	return s.current
+}
+
+// inherit returns the option applying the settings of m to a child manager
+// scoped under name.
+func (m *Manager) inherit(scope string) Option {
+	return func(c *Manager) {
+		m.settingsMu.RLock()
+		c.logger = m.logger
+		c.slog = m.slogBase
+		c.silent = m.silent
+		c.verbosity = m.verbosity
+		c.logSeverity = m.logSeverity
+		c.strategy = m.strategy
+		c.shutdownTimeout = m.shutdownTimeout
+		m.settingsMu.RUnlock()
+
+		c.phaseTimeouts = m.phaseTimeouts
+		c.hookTimeout = m.hookTimeout
+		c.reloadTimeout = m.reloadTimeout
+		c.depTimeout = m.depTimeout
+		c.sampleInterval = m.sampleInterval
+		c.emptyPolicy = m.emptyPolicy
+		c.flags = m.flags
+		c.stableNames = m.stableNames
+		c.build = m.build
+		c.clock = m.clock
+		c.runtimeMetrics = m.runtimeMetrics
+
+		c.baggage = copyBaggage(m.baggage)
+		if c.baggage == nil {
+			c.baggage = make(map[string]string, 1)
+		}
+		c.baggage["scope"] = scope
+	}
+}
+
+// respawn returns a new manager created with opts, with the units currently
+// registered with m under the same names.
+func (m *Manager) respawn(opts []Option) *Manager {
+	c := NewManager(opts...)
+
+	m.regMu.RLock()
+	var units []*WorkUnitManager
+	for _, w := range m.order {
+		if m.workers[w.name] == w && !w.removed.Load() {
+			units = append(units, w)
+		}
+	}
+	m.regMu.RUnlock()
+
+	for _, w := range units {
+		c.AddUnit(w.unit, w.base, append(slices.Clip(w.opts), WithName(w.name))...)
+	}
+	return c
+}
@@ -0,0 +1,76 @@
+package gum
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSubManager(t *testing.T) {
+	var out syncBuffer
+	manager := NewManager(
+		WithLogger(log.New(&out, "", 0)),
+		WithBaggage(map[string]string{"run": "42"}),
+		WithHookTimeout(time.Second),
+	)
+	ingest := manager.SubManager("ingest", WithShutdownTimeout(time.Minute))
+	ingest.AddUnit(&readyWorker{}, "", WithName("fetcher"))
+	first := ingest.Manager()
+
+	if b := first.Baggage(); b["run"] != "42" || b["scope"] != "ingest" {
+		t.Fatalf("unexpected child baggage %v", b)
+	}
+	if first.hookTimeout != time.Second || first.shutdownTimeout != time.Minute || first.logger != manager.logger {
+		t.Fatal("expected the child to inherit the settings of the parent, and override them")
+	}
+
+	sub := manager.Subscribe()
+	quit := runAsync(manager)
+	waitEvent(t, sub, EventStartupComplete)
+
+	if !strings.Contains(out.String(), "[run=42 scope=ingest] Starting <fetcher>") {
+		t.Fatalf("expected the child logs to be scoped, got:\n%s", out.String())
+	}
+	var status bytes.Buffer
+	if err := manager.DumpStatus(&status); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(status.String(), "ingest/fetcher") {
+		t.Fatalf("expected the child units under their subtree, got:\n%s", status.String())
+	}
+
+	// A restart runs a new child with the same units
+	if err := manager.Restart("ingest"); err != nil {
+		t.Fatalf("unexpected restart error: %v", err)
+	}
+	waitChild := func(unit string) {
+		t.Helper()
+
+		deadline := time.Now().Add(time.Second)
+		for {
+			child := manager.subtree("ingest")
+			if child != nil && child != first && child == ingest.Manager() {
+				if u, _ := child.Status(unit); u.State == Running {
+					return
+				}
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("expected a new child running <%s>", unit)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	waitChild("fetcher")
+
+	// Units are added to the current child
+	ingest.AddUnit(&readyWorker{}, "", WithName("parser"))
+	waitChild("parser")
+
+	manager.Stop()
+	<-quit
+	if manager.Err() != nil {
+		t.Fatalf("unexpected shutdown cause: %v", manager.Err())
+	}
+}
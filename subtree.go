@@ -0,0 +1,77 @@
+package gum
+
+import (
+	"fmt"
+	"sync"
+)
+
+// SubtreeUnit is a unit running a child manager, forming a supervision tree:
+// a subsystem made of several units is supervised by its own manager,
+// composed under a root manager which keeps running while the subsystem is
+// stopped, restarted (see WithRestart and RestartUnit) or removed.
+//
+//	root.AddUnit(gum.Subtree(func() *gum.Manager {
+//		m := gum.NewManager(gum.WithBaggage(map[string]string{"subsystem": "ingest"}))
+//		m.AddUnit(fetcher, "fetcher")
+//		m.AddUnit(parser, "parser", gum.After("fetcher"))
+//		return m
+//	}), "ingest")
+//
+// A manager only runs once, so each run of the unit creates its child
+// manager with newManager. The child must not handle OS signals, the root
+// does. The unit is ready once the units of the child are ready, and
+// stopping it shuts the child down within the shutdown budget of the unit.
+// If the child quits on its own, the unit is done, or panics with the
+// shutdown cause of the child. The status of the child is mirrored in the
+// Subtrees of the parent Snapshot.
+type SubtreeUnit struct {
+	newManager func() *Manager
+
+	mu    sync.Mutex
+	child *Manager
+}
+
+// Subtree returns a unit running the child managers created by newManager.
+func Subtree(newManager func() *Manager) *SubtreeUnit {
+	return &SubtreeUnit{newManager: newManager}
+}
+
+// Manager returns the current child manager, nil before the first run.
+func (s *SubtreeUnit) Manager() *Manager {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.child
+}
+
+// Run implements WorkUnit.
+func (s *SubtreeUnit) Run(um UnitManager) {
+	child := s.newManager()
+	if child == nil {
+		um.Panic(fmt.Errorf("subtree: nil manager"))
+		return
+	}
+	s.mu.Lock()
+	s.child = child
+	s.mu.Unlock()
+
+	go child.Run()
+	go func() {
+		if child.WaitReady(um.Context()) == nil {
+			um.Ready()
+		}
+	}()
+
+	select {
+	case <-um.ShouldStop():
+		if err := child.Shutdown(um.ShutdownContext()); err != nil {
+			um.Panic(fmt.Errorf("subtree: %w", err))
+			return
+		}
+	case <-child.quitC:
+		if err := child.Err(); err != nil {
+			um.Panic(fmt.Errorf("subtree: %w", err))
+			return
+		}
+	}
+	um.Done()
+}
@@ -0,0 +1,108 @@
+package gum
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+func TestSubtree(t *testing.T) {
+	var children []*Manager
+	ingest := Subtree(func() *Manager {
+		m := NewManager()
+		m.AddUnit(&readyWorker{}, "", WithName("fetcher"))
+		m.AddUnit(&readyWorker{}, "", WithName("parser"), After("fetcher"))
+		children = append(children, m)
+		return m
+	})
+
+	root := NewManager()
+	root.AddUnit(ingest, "", WithName("ingest"))
+	root.AddUnit(&readyWorker{}, "", WithName("api"))
+
+	sub := root.Subscribe()
+	quit := runAsync(root)
+	waitEvent(t, sub, EventStartupComplete)
+
+	child, ok := root.Snapshot().Subtrees["ingest"]
+	if !ok || len(child.Units) != 2 || !child.Units[1].Ready {
+		t.Fatalf("expected the child units to be ready, got %+v", child)
+	}
+
+	if err := root.RestartUnit("ingest"); err != nil {
+		t.Fatal(err)
+	}
+	waitEvent(t, sub, EventUnitReady)
+	if len(children) != 2 || ingest.Manager() != children[1] {
+		t.Fatalf("expected a new child manager, got %d", len(children))
+	}
+	if u, _ := root.Status("api"); u.State != Running {
+		t.Fatalf("expected the root to keep running, got %s", u.State)
+	}
+
+	root.Stop()
+	<-quit
+
+	if root.Err() != nil {
+		t.Fatalf("unexpected shutdown cause: %v", root.Err())
+	}
+	for i, m := range children {
+		select {
+		case <-m.quitC:
+		default:
+			t.Fatalf("expected child manager %d to quit", i)
+		}
+	}
+}
+
+func TestSubtreeFailure(t *testing.T) {
+	root := NewManager()
+	root.AddUnit(Subtree(func() *Manager {
+		m := NewManager()
+		m.AddUnit(&crashWorker{}, "", WithName("crash"))
+		return m
+	}), "", WithName("processing"))
+	root.Run()
+
+	var perr *PanicError
+	if !errors.Is(root.Err(), ErrUnitPanic) || !errors.As(root.Err(), &perr) {
+		t.Fatalf("expected the child failure to fail the subtree, got %v", root.Err())
+	}
+}
+
+func TestSnapshotWhileAdding(t *testing.T) {
+	root := NewManager(WithSlog(slog.New(slowStartHandler("ingest"))))
+	root.AddUnit(&readyWorker{}, "", WithName("api"))
+
+	sub := root.Subscribe()
+	quit := runAsync(root)
+	waitEvent(t, sub, EventStartupComplete)
+	sub.Close()
+
+	// Subtrees started at runtime race with the snapshots, see go test -race
+	added := make(chan struct{})
+	go func() {
+		defer close(added)
+		for i := 0; i < 30; i++ {
+			root.AddUnit(Subtree(func() *Manager {
+				return NewManager(WithSilent())
+			}), "ingest")
+		}
+	}()
+	for snapshots := true; snapshots; {
+		select {
+		case <-added:
+			snapshots = false
+		default:
+		}
+		root.Snapshot()
+		root.DumpStatus(io.Discard)
+	}
+
+	root.Stop()
+	<-quit
+	if root.Err() != nil {
+		t.Fatalf("unexpected shutdown cause: %v", root.Err())
+	}
+}
@@ -0,0 +1,114 @@
+package gum
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Summary is the final account of a manager run, logged and available from
+// Manager.Summary once the shutdown is over, e.g. to attach to deploy logs.
+type Summary struct {
+	Uptime   time.Duration
+	Err      error // Shutdown cause, joining every error
+	ExitCode int
+
+	// Units are the units which ran, in registration order. The instances
+	// of a restarted, swapped or recycled unit are counted as one unit.
+	Units []UnitSummary
+
+	// Phases are the reports of the shutdown phases, in order.
+	Phases []PhaseReport
+
+	// Abandoned are the units still running at the end of the shutdown.
+	Abandoned []string
+
+	// Chain is the chain of dependencies which governed the end of the
+	// shutdown, in stop order: each unit was held back until the previous
+	// one was done, see After. It is empty if the last unit done was not
+	// held back.
+	Chain []string
+}
+
+// UnitSummary is the account of a unit in a Summary.
+type UnitSummary struct {
+	Name     string // Of the latest instance
+	State    UnitState
+	Restarts int
+	Panics   int // Of all instances
+	Err      error
+}
+
+// String formats the summary for logs, one line per unit and phase. Errors
+// are quoted so their text stays on the line.
+func (s *Summary) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Summary: uptime %s, exit code %d", s.Uptime.Round(time.Millisecond), s.ExitCode)
+	for _, u := range s.Units {
+		fmt.Fprintf(&b, "\n  <%s> %s, %d restarts, %d panics", u.Name, u.State, u.Restarts, u.Panics)
+		if u.Err != nil {
+			fmt.Fprintf(&b, ": %q", u.Err.Error())
+		}
+	}
+	for _, p := range s.Phases {
+		fmt.Fprintf(&b, "\n  %s phase took %s", p.Phase, p.Duration.Round(time.Microsecond))
+		if p.TimedOut {
+			b.WriteString(" (timed out)")
+		}
+	}
+	if len(s.Chain) > 0 {
+		fmt.Fprintf(&b, "\n  shutdown chain <%s>", strings.Join(s.Chain, "> -> <"))
+	}
+	if len(s.Abandoned) > 0 {
+		fmt.Fprintf(&b, "\n  abandoned <%s>", strings.Join(s.Abandoned, ">, <"))
+	}
+	if s.Err != nil {
+		fmt.Fprintf(&b, "\n  error: %q", s.Err.Error())
+	}
+	return b.String()
+}
+
+// Summary returns the summary of the run, nil until the shutdown is over.
+func (m *Manager) Summary() *Summary {
+	m.regMu.RLock()
+	defer m.regMu.RUnlock()
+	return m.summary
+}
+
+// summarize builds, publishes and logs the summary at the end of the
+// shutdown.
+func (m *Manager) summarize() {
+	now := m.clock.Now()
+	err := m.Err()
+	summary := &Summary{Err: err, ExitCode: m.ExitCode()}
+
+	m.regMu.Lock()
+	summary.Uptime = m.uptime(now)
+	summary.Phases = append([]PhaseReport(nil), m.phases...)
+	summary.Chain = m.shutdownChain
+
+	lineages := make(map[string]int)
+	for _, w := range m.order {
+		if !w.started.Load() {
+			continue
+		}
+		if w.awaited && !w.done.Load() {
+			summary.Abandoned = append(summary.Abandoned, w.name)
+		}
+
+		u := UnitSummary{Name: w.name, State: w.state, Restarts: w.restarts, Panics: w.panics.total, Err: w.err}
+		i, ok := lineages[w.lineage]
+		if !ok {
+			lineages[w.lineage] = len(summary.Units)
+			summary.Units = append(summary.Units, u)
+			continue
+		}
+		summary.Units[i] = u
+	}
+	m.summary = summary
+	m.regMu.Unlock()
+
+	for _, line := range strings.Split(summary.String(), "\n") {
+		m.logf("%s\n", line)
+	}
+}
@@ -0,0 +1,41 @@
+package gum
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSummary(t *testing.T) {
+	manager := NewManager(WithShutdownTimeout(20 * time.Millisecond))
+	manager.AddUnit(&flakyWorker{failures: 1}, "", WithName("flaky"),
+		WithRestart(RestartOnFailure), WithRestartBackoff(time.Millisecond, time.Millisecond))
+	manager.AddUnit(&stuckWorker{}, "", WithName("stuck"))
+
+	if manager.Summary() != nil {
+		t.Fatal("expected no summary before the run")
+	}
+
+	go manager.Run()
+	waitRestarted(t, manager, 0, 1)
+	manager.Stop()
+	<-manager.Quit
+
+	s := manager.Summary()
+	if s == nil || s.Uptime <= 0 || !errors.Is(s.Err, ErrForcedShutdown) || s.ExitCode != ExitForced {
+		t.Fatalf("unexpected summary %+v", s)
+	}
+	if len(s.Units) != 2 || s.Units[0].Name != "flaky" || s.Units[0].Restarts != 1 || s.Units[0].Panics != 1 {
+		t.Fatalf("expected the instances of the restarted unit to be counted as one, got %+v", s.Units)
+	}
+	if len(s.Abandoned) != 1 || s.Abandoned[0] != "stuck" {
+		t.Fatalf("expected the stuck unit to be abandoned, got %v", s.Abandoned)
+	}
+	if len(s.Phases) != 3 || s.Phases[1].Phase != PhaseStop || !s.Phases[1].TimedOut {
+		t.Fatalf("unexpected phases %+v", s.Phases)
+	}
+	if !strings.Contains(s.String(), "abandoned <stuck>") {
+		t.Fatalf("expected the abandoned units in\n%s", s)
+	}
+}
@@ -0,0 +1,41 @@
+package gum
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+)
+
+// ErrInternal is joined to the shutdown cause when the manager recovered from
+// an internal error, see EventInternalError.
+var ErrInternal = errors.New("internal manager error")
+
+// protect runs f, recovering from a panic so a bug in the manager or in a
+// hook can't kill the supervision of the whole process. The panic is logged
+// with its stack trace, joined to the shutdown cause and published as an
+// EventInternalError event. It returns false if f panicked.
+func (m *Manager) protect(what string, f func()) (ok bool) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		ok = false
+
+		err := fmt.Errorf("%w in %s: %v", ErrInternal, what, r)
+		m.warnf("", "%s\n%s", err, debug.Stack())
+
+		m.addErr(err)
+
+		// The event bus may be the culprit
+		defer func() {
+			if r := recover(); r != nil {
+				m.warnf("", "could not publish internal error: %v\n", r)
+			}
+		}()
+		m.emit(EventInternalError, "", err)
+	}()
+
+	f()
+	return true
+}
@@ -0,0 +1,56 @@
+package gum
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestInternalErrorRecovered(t *testing.T) {
+	manager := NewManager(
+		WithSampleInterval(time.Millisecond),
+		WithPressureHook(func(Pressure) { panic("buggy hook") }),
+	)
+	manager.AddUnit(&stopWorker{}, "")
+	sub := manager.Subscribe()
+
+	go manager.Run()
+
+	for {
+		select {
+		case ev := <-sub.Events():
+			if ev.Kind != EventInternalError {
+				continue
+			}
+		case <-time.After(time.Second):
+			t.Fatal("internal error not published")
+		}
+		break
+	}
+
+	// Supervision goes on
+	manager.Stop()
+	select {
+	case <-manager.Quit:
+	case <-time.After(time.Second):
+		t.Fatal("manager did not quit")
+	}
+
+	if !errors.Is(manager.Err(), ErrInternal) {
+		t.Fatalf("expected internal error in shutdown cause, got %v", manager.Err())
+	}
+}
+
+func TestProtect(t *testing.T) {
+	manager := NewManager()
+
+	if !manager.protect("test", func() {}) {
+		t.Fatal("expected protect to succeed")
+	}
+	if manager.protect("test", func() { panic("boom") }) {
+		t.Fatal("expected protect to report the panic")
+	}
+	if !errors.Is(manager.Err(), ErrInternal) {
+		t.Fatalf("expected internal error, got %v", manager.Err())
+	}
+}
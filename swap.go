@@ -0,0 +1,153 @@
+package gum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// SwapUnit replaces a running unit by a new version without downtime. It
+// waits for the manager to have started its units first. The new unit is
+// started alongside the old one and once it reports Ready the old unit is
+// asked to stop, and unregistered once done. SwapUnit returns without
+// waiting for the old unit to be done.
+//
+// The new unit takes over the name and identity of the old one, and its
+// options, which opts override. If the new unit is done before being ready
+// or ctx is canceled first, the new unit is stopped, the old one keeps
+// running and an error is returned. While the topology is frozen, the swap
+// is rejected or waits for Unfreeze, see Freeze.
+func (m *Manager) SwapUnit(ctx context.Context, name string, unit WorkUnit, opts ...UnitOption) error {
+	if err := m.waitUnfrozen(ctx, "swap", name); err != nil {
+		return err
+	}
+	_, err := m.swapUnit(ctx, name, "", unit, opts...)
+	return err
+}
+
+// swapUnit implements SwapUnit and returns the new unit. The events of both
+// units are correlated with the given trace ID, a new one if empty.
+func (m *Manager) swapUnit(ctx context.Context, name, trace string, unit WorkUnit, opts ...UnitOption) (*WorkUnitManager, error) {
+	if unit == nil {
+		return nil, fmt.Errorf("nil unit to swap <%s> with", name)
+	}
+
+	// Wait for the initial units to be started
+	select {
+	case <-m.startDone:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("can't swap <%s>: %w", name, ctx.Err())
+	}
+
+	m.startMu.Lock()
+
+	select {
+	case <-m.startStop:
+		m.startMu.Unlock()
+		return nil, fmt.Errorf("can't swap <%s>: manager is shutting down", name)
+	default:
+	}
+
+	m.regMu.RLock()
+	old, ok := m.workers[name]
+	state := Starting
+	if ok {
+		state = old.state
+	}
+	m.regMu.RUnlock()
+
+	if !ok {
+		m.startMu.Unlock()
+		return nil, fmt.Errorf("can't swap <%s>: unknown unit", name)
+	}
+	if state != Running && state != Parked {
+		m.startMu.Unlock()
+		return nil, fmt.Errorf("can't swap <%s>: unit is %s", name, state)
+	}
+
+	w := m.newUnitID(unit, old.base, old.info.ID, append(slices.Clip(old.opts), opts...)...)
+	if len(w.configErrs) > 0 {
+		m.startMu.Unlock()
+		return nil, fmt.Errorf("can't swap <%s>: %w", name, errors.Join(w.configErrs...))
+	}
+	w.name, w.info.Name, w.lineage = old.name, old.name, old.lineage
+	w.readyC = make(chan struct{})
+	w.doneCh = make(chan struct{})
+	w.settled.Store(true) // The startup completion only waits for the initial units
+
+	// Correlate the old and new instances
+	if trace == "" {
+		trace = m.newTraceID()
+	}
+	m.setTrace(old, trace)
+	w.traceID = trace
+
+	m.addInstance(old, w)
+	m.startUnit(w)
+	m.startMu.Unlock()
+
+	m.logf("Swapping <%s> with a new instance (trace %s)\n", old, trace)
+
+	select {
+	case <-w.readyC:
+	case <-w.doneCh:
+		m.retire(w)
+		return nil, fmt.Errorf("can't swap <%s>: new instance done before being ready", name)
+	case <-m.startStop:
+		return nil, fmt.Errorf("can't swap <%s>: manager is shutting down", name)
+	case <-ctx.Done():
+		m.retire(w)
+		return nil, fmt.Errorf("can't swap <%s>: %w", name, ctx.Err())
+	}
+
+	m.regMu.Lock()
+	m.workers[name] = w
+	m.touch()
+	m.regMu.Unlock()
+
+	m.retire(old)
+	return w, nil
+}
+
+// addInstance registers the unit as a new instance of old, next to it, while
+// old is still registered under the name they share.
+func (m *Manager) addInstance(old, w *WorkUnitManager) {
+	m.regMu.Lock()
+	defer m.regMu.Unlock()
+
+	m.inheritLineage(w)
+	order := make([]*WorkUnitManager, 0, len(m.order)+1)
+	for _, u := range m.order {
+		order = append(order, u)
+		if u == old {
+			order = append(order, w)
+		}
+	}
+	m.order = order
+	m.touch()
+}
+
+// retire stops an instance replaced under its name and unregisters it once
+// it is done, as RemoveUnit does.
+func (m *Manager) retire(w *WorkUnitManager) {
+	m.startMu.Lock()
+	w.removed.Store(true)
+	done := w.done.Load()
+	if done {
+		m.unregister(w)
+	}
+	m.startMu.Unlock()
+
+	if !done {
+		m.stopUnit(w)
+	}
+}
+
+// stopUnit asks a running unit to stop.
+func (m *Manager) stopUnit(w *WorkUnitManager) {
+	m.setState(w, Stopping, nil)
+	if w.requestStop() {
+		m.emitUnit(EventUnitStopping, w, nil)
+	}
+}
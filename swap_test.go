@@ -0,0 +1,101 @@
+package gum
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// blockedWorker is never ready
+type blockedWorker struct{}
+
+func (w *blockedWorker) Run(um UnitManager) {
+	<-um.ShouldStop()
+	um.Done()
+}
+
+func TestSwapUnit(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&readyWorker{}, "", WithName("api"), WithLabels(map[string]string{"tier": "web"}))
+	old := manager.order[0]
+
+	go manager.Run()
+	waitState(t, manager, 0, Running)
+
+	if err := manager.SwapUnit(context.Background(), "api", &readyWorker{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The old unit is unregistered once done
+	waitUnits(t, manager, 1)
+	u, _ := manager.Status("api")
+	if u.State != Running || !u.Ready || u.Labels["tier"] != "web" {
+		t.Fatalf("expected the new unit to be running with the old options, got %+v", u)
+	}
+	if w := manager.order[0]; w == old || w.Info().ID != old.info.ID || w.Info().Type != "readyWorker" {
+		t.Fatalf("unexpected identity for the new unit: %+v", w.Info())
+	}
+
+	// The new unit can be swapped in turn, opts override the old options
+	if err := manager.SwapUnit(context.Background(), "api", &readyWorker{},
+		WithLabels(map[string]string{"version": "3"})); err != nil {
+		t.Fatal(err)
+	}
+	waitUnits(t, manager, 1)
+	if u, _ := manager.Status("api"); u.State != Running || u.Labels["tier"] != "web" || u.Labels["version"] != "3" {
+		t.Fatalf("unexpected status of the second swap %+v", u)
+	}
+
+	manager.Stop()
+	<-manager.Quit
+}
+
+// waitUnits waits for n units to be registered.
+func waitUnits(t *testing.T, manager *Manager, n int) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for len(manager.Snapshot().Units) != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d units, got %+v", n, manager.Snapshot().Units)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSwapUnitNotReady(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&readyWorker{}, "api")
+	old := manager.order[0]
+
+	go manager.Run()
+	waitState(t, manager, 0, Running)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := manager.SwapUnit(ctx, old.name, &blockedWorker{}); err == nil {
+		t.Fatal("expected swap to fail")
+	}
+
+	waitUnits(t, manager, 1)
+	if u, _ := manager.Status(old.name); u.State != Running || manager.order[0] != old {
+		t.Fatalf("expected the old unit to keep running, got %+v", u)
+	}
+
+	manager.Stop()
+	<-manager.Quit
+}
+
+func TestSwapUnitUnknown(t *testing.T) {
+	manager := NewManager()
+	go manager.Run()
+	defer func() {
+		manager.Stop()
+		<-manager.Quit
+	}()
+
+	if err := manager.SwapUnit(context.Background(), "none", &readyWorker{}); err == nil {
+		t.Fatal("expected swapping an unknown unit to fail")
+	}
+}
@@ -0,0 +1,105 @@
+package gum
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// AsTask marks the unit as a task running a finite job. A task is done once
+// its Run returned, and is completed when done without being asked to stop:
+// it is then unregistered, as with RemoveUnit, and never restarted, rather
+// than being left stopped in the registry. See AddTask.
+func AsTask() UnitOption {
+	return func(w *WorkUnitManager) {
+		w.task = true
+	}
+}
+
+// completeTask unregisters the task once done, unless it was asked to stop
+// or the manager is shutting down. It reports whether the task completed.
+func (m *Manager) completeTask(w *WorkUnitManager) bool {
+	if w.Stopping() {
+		return false
+	}
+	select {
+	case <-m.startStop:
+		return false
+	default:
+	}
+	return w.removed.CompareAndSwap(false, true)
+}
+
+// taskUnit runs the function of a task and delivers its result.
+type taskUnit struct {
+	fn     func(ctx context.Context) error
+	result chan error
+	done   chan struct{} // Closed with the result channel
+	once   sync.Once
+}
+
+func (t *taskUnit) Run(um UnitManager) {
+	um.Ready()
+	defer func() {
+		if r := recover(); r != nil {
+			t.finish(&PanicError{Value: r, Stack: debug.Stack()})
+		}
+	}()
+	t.finish(t.fn(um.Context()))
+}
+
+// finish delivers the first result and closes the result channel.
+func (t *taskUnit) finish(err error) {
+	t.once.Do(func() {
+		t.result <- err
+		close(t.result)
+		close(t.done)
+	})
+}
+
+// AddTask registers a function as a task named name, see AsTask, and returns
+// a channel receiving its result once it returned: its error, a *PanicError
+// if it panicked, or ErrTaskNotRun if the manager rejected it or quit before
+// it returned, e.g. before running it. The context given to the function is
+// cancelled if the manager shuts down first. A failed task doesn't fail the
+// manager, the caller handles the result:
+//
+//	result := manager.AddTask("migrate", func(ctx context.Context) error {
+//		return db.Migrate(ctx)
+//	})
+//	...
+//	if err := <-result; err != nil {
+//		log.Printf("migration failed: %s", err)
+//	}
+//
+// The name is used as is, as with WithName.
+func (m *Manager) AddTask(name string, fn func(ctx context.Context) error, opts ...UnitOption) <-chan error {
+	t := &taskUnit{fn: fn, result: make(chan error, 1), done: make(chan struct{})}
+	if fn == nil {
+		m.invalid(fmt.Errorf("nil func %q", name))
+		t.finish(fmt.Errorf("%w: nil func", ErrTaskNotRun))
+		return t.result
+	}
+
+	m.regMu.RLock()
+	_, exists := m.workers[name]
+	m.regMu.RUnlock()
+	if exists {
+		t.finish(fmt.Errorf("%w: duplicate unit name <%s>", ErrTaskNotRun, name))
+		return t.result
+	}
+
+	if err := m.tryAddUnit(t, "", append([]UnitOption{WithName(name), AsTask()}, opts...)...); err != nil {
+		t.finish(fmt.Errorf("%w: %w", ErrTaskNotRun, err))
+		return t.result
+	}
+	go func() {
+		select {
+		case <-t.done:
+		case <-m.quitC:
+			t.finish(ErrTaskNotRun)
+		}
+	}()
+	return t.result
+}
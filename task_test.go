@@ -0,0 +1,106 @@
+package gum
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAddTask(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&readyWorker{}, "", WithName("server"))
+	before := manager.AddTask("warmup", func(context.Context) error { return nil })
+
+	sub := manager.Subscribe()
+	quit := runAsync(manager)
+	waitEvent(t, sub, EventStartupComplete)
+
+	if err := <-before; err != nil {
+		t.Fatalf("unexpected task error: %v", err)
+	}
+	failed := manager.AddTask("migrate", func(context.Context) error { return errors.New("bad schema") })
+	if err := <-failed; err == nil || err.Error() != "bad schema" {
+		t.Fatalf("unexpected task result: %v", err)
+	}
+	panicked := manager.AddTask("crash", func(context.Context) error { panic("boom") })
+	var perr *PanicError
+	if err := <-panicked; !errors.As(err, &perr) {
+		t.Fatalf("expected a panic error, got %v", err)
+	}
+
+	// Completed tasks are unregistered, a failed task doesn't stop the manager
+	deadline := time.Now().Add(time.Second)
+	for len(manager.Units()) != 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the tasks to be unregistered, got %+v", manager.Units())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if u, _ := manager.Status("server"); u.State != Running {
+		t.Fatalf("expected the manager to keep running, got <server> %s", u.State)
+	}
+
+	// A task is never restarted
+	again := manager.AddTask("warmup", func(context.Context) error { return nil }, WithRestart(RestartAlways))
+	if err := <-again; err != nil {
+		t.Fatalf("unexpected task error: %v", err)
+	}
+	if err := <-manager.AddTask("server", func(context.Context) error { return nil }); !errors.Is(err, ErrTaskNotRun) {
+		t.Fatalf("expected a duplicate task not to run, got %v", err)
+	}
+	select {
+	case err := <-manager.AddTask("orphan", func(context.Context) error { return nil }, After("missing")):
+		if !errors.Is(err, ErrTaskNotRun) {
+			t.Fatalf("expected a rejected task not to run, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a result for a rejected task")
+	}
+
+	manager.Stop()
+	<-quit
+	if manager.Err() != nil {
+		t.Fatalf("unexpected shutdown cause: %v", manager.Err())
+	}
+}
+
+func TestTaskNotRun(t *testing.T) {
+	manager := NewManager(WithStartupTimeout(-1))
+	result := manager.AddTask("never", func(context.Context) error { return nil })
+	manager.Run()
+
+	select {
+	case err := <-result:
+		if !errors.Is(err, ErrTaskNotRun) {
+			t.Fatalf("expected the task not to run, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a result once the manager quit")
+	}
+}
+
+func TestAsTask(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&readyWorker{}, "", WithName("server"))
+
+	sub := manager.Subscribe()
+	quit := runAsync(manager)
+	waitEvent(t, sub, EventStartupComplete)
+
+	// Returns without calling Done
+	manager.AddUnit(funcWorker(func(UnitManager) {}), "", WithName("job"), AsTask())
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, ok := manager.Status("job"); !ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the task to be unregistered once it returned")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	manager.Stop()
+	<-quit
+}
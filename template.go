@@ -0,0 +1,134 @@
+package gum
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// UnitTemplate describes a family of units, e.g. a consumer per partition or
+// a worker per tenant, instantiated with InstantiateTemplate.
+type UnitTemplate struct {
+	// Name is the name of the instances, where ${param} or $param is
+	// expanded with the parameters of the instance, e.g.
+	// "consumer-${partition}". It defaults to "<family>@${name}".
+	Name string
+
+	// New creates an instance from its parameters.
+	New func(params map[string]string) (WorkUnit, error)
+
+	// Options are applied to every instance.
+	Options []UnitOption
+}
+
+// WithUnitTemplate registers the template of the family, see
+// InstantiateTemplate.
+func WithUnitTemplate(family string, t UnitTemplate) Option {
+	return func(m *Manager) {
+		switch {
+		case family == "":
+			m.invalid(fmt.Errorf("empty unit template family"))
+			return
+		case t.New == nil:
+			m.invalid(fmt.Errorf("unit template %q without constructor", family))
+			return
+		}
+		if t.Name == "" {
+			t.Name = family + "@${name}"
+		}
+		if m.templates == nil {
+			m.templates = make(map[string]UnitTemplate)
+		}
+		m.templates[family] = t
+	}
+}
+
+// withFamily tags the unit as an instance of the template family.
+func withFamily(family string) UnitOption {
+	return func(w *WorkUnitManager) {
+		w.family = family
+	}
+}
+
+// InstantiateTemplate creates an instance of the template of the family with
+// the parameters and registers it, labeled with the parameters, under the
+// expanded template name which it returns. The instance is started right
+// away if the manager runs, which returns the error of an instance it
+// rejects. Instances are listed with Family and operated as a set with
+// RestartFamily and RemoveFamily.
+func (m *Manager) InstantiateTemplate(family string, params map[string]string) (string, error) {
+	t, ok := m.templates[family]
+	if !ok {
+		return "", fmt.Errorf("no unit template %q", family)
+	}
+
+	var missing []string
+	name := os.Expand(t.Name, func(key string) string {
+		v, ok := params[key]
+		if !ok {
+			missing = append(missing, key)
+		}
+		return v
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("<%s>: missing template parameters %v", name, missing)
+	}
+
+	m.regMu.RLock()
+	_, exists := m.workers[name]
+	m.regMu.RUnlock()
+	if exists {
+		return "", fmt.Errorf("<%s>: duplicate unit name", name)
+	}
+
+	unit, err := t.New(params)
+	if err != nil {
+		return "", fmt.Errorf("<%s>: %w", name, err)
+	}
+	if unit == nil {
+		return "", fmt.Errorf("<%s>: nil unit", name)
+	}
+
+	opts := append(slices.Clip(t.Options), WithName(name), withFamily(family))
+	if len(params) > 0 {
+		opts = append(opts, WithLabels(params))
+	}
+	if err := m.tryAddUnit(unit, family, opts...); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// Family returns the status of the instances of the template family, in
+// registration order.
+func (m *Manager) Family(family string) []UnitStatus {
+	var units []UnitStatus
+	for _, u := range m.Units() {
+		if u.Family == family {
+			units = append(units, u)
+		}
+	}
+	return units
+}
+
+// RestartFamily restarts the running instances of the template family, see
+// RestartUnit.
+func (m *Manager) RestartFamily(family string) error {
+	var errs []error
+	for _, u := range m.Family(family) {
+		if u.State == Running {
+			errs = append(errs, m.RestartUnit(u.Name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RemoveFamily removes the instances of the template family, see RemoveUnit.
+func (m *Manager) RemoveFamily(family string) error {
+	var errs []error
+	for _, u := range m.Family(family) {
+		errs = append(errs, m.RemoveUnit(u.Name))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,118 @@
+package gum
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type partitionWorker struct {
+	readyWorker
+	partition string
+}
+
+func TestInstantiateTemplate(t *testing.T) {
+	manager := NewManager(WithUnitTemplate("consumer", UnitTemplate{
+		Name: "consumer-${partition}",
+		New: func(params map[string]string) (WorkUnit, error) {
+			if params["partition"] == "bad" {
+				return nil, errors.New("bad partition")
+			}
+			return &partitionWorker{partition: params["partition"]}, nil
+		},
+		Options: []UnitOption{WithRestart(RestartOnFailure)},
+	}), WithUnitTemplate("replica", UnitTemplate{
+		New:     func(map[string]string) (WorkUnit, error) { return &readyWorker{}, nil },
+		Options: []UnitOption{After("missing")},
+	}))
+
+	for _, p := range []string{"0", "1"} {
+		if _, err := manager.InstantiateTemplate("consumer", map[string]string{"partition": p}); err != nil {
+			t.Fatalf("unexpected instantiation error: %v", err)
+		}
+	}
+	for _, params := range []map[string]string{
+		{"partition": "0"},   // Duplicate
+		{"partition": "bad"}, // Constructor error
+		{"topic": "orders"},  // Missing parameter
+	} {
+		if _, err := manager.InstantiateTemplate("consumer", params); err == nil {
+			t.Errorf("expected an error instantiating %v", params)
+		}
+	}
+	if _, err := manager.InstantiateTemplate("producer", nil); err == nil {
+		t.Error("expected an error instantiating an unknown template")
+	}
+
+	sub := manager.Subscribe()
+	quit := runAsync(manager)
+	waitEvent(t, sub, EventStartupComplete)
+
+	// Instances added at runtime are started right away
+	name, err := manager.InstantiateTemplate("consumer", map[string]string{"partition": "2"})
+	if err != nil || name != "consumer-2" {
+		t.Fatalf("unexpected instance %q: %v", name, err)
+	}
+	waitState(t, manager, 2, Running)
+	if _, err := manager.InstantiateTemplate("replica", map[string]string{"name": "0"}); err == nil {
+		t.Fatal("expected the rejected instance to be reported")
+	}
+
+	family := manager.Family("consumer")
+	if len(family) != 3 || family[1].Name != "consumer-1" || family[1].Labels["partition"] != "1" {
+		t.Fatalf("unexpected family %+v", family)
+	}
+
+	if err := manager.RestartFamily("consumer"); err != nil {
+		t.Fatalf("unexpected restart error: %v", err)
+	}
+	waitFamily(t, manager, func(family []UnitStatus) bool {
+		for _, u := range family {
+			if u.Restarts != 1 || u.State != Running {
+				return false
+			}
+		}
+		return len(family) == 3
+	})
+
+	if err := manager.RemoveFamily("consumer"); err != nil {
+		t.Fatalf("unexpected removal error: %v", err)
+	}
+	waitFamily(t, manager, func(family []UnitStatus) bool {
+		for _, u := range family {
+			if u.State != Stopped {
+				return false
+			}
+		}
+		return true
+	})
+	if _, ok := manager.Status("consumer-0"); ok {
+		t.Fatal("expected the removed instance to be unregistered")
+	}
+
+	manager.Stop()
+	<-quit
+	if manager.Err() != nil {
+		t.Fatalf("unexpected shutdown cause: %v", manager.Err())
+	}
+}
+
+func TestUnitTemplateValidate(t *testing.T) {
+	manager := NewManager(WithUnitTemplate("consumer", UnitTemplate{}))
+	if err := manager.Validate(); err == nil {
+		t.Fatal("expected a template without constructor to be reported")
+	}
+}
+
+func waitFamily(t *testing.T, manager *Manager, cond func([]UnitStatus) bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if cond(manager.Family("consumer")) {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("unexpected family %+v", manager.Family("consumer"))
+}
@@ -0,0 +1,252 @@
+package gum
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// timerWheel is a hierarchical timing wheel used to schedule a large number
+// of timers with a single runtime timer. Each level has the same number of
+// slots, a slot of level n spans size^n ticks. Timers are inserted in the
+// lowest level covering their expiration and cascaded down to lower levels
+// as the wheel turns. Insertion and removal are O(1).
+type timerWheel struct {
+	mu      sync.Mutex
+	tick    time.Duration
+	size    uint64
+	levels  [][]*list.List
+	cur     uint64 // Current tick
+	start   time.Time
+	driven  bool // Started, cur lags behind the time since start
+	pending int  // Timers neither fired nor stopped
+
+	wake chan struct{} // Restarts the idle driving goroutine
+	stop chan struct{}
+	once sync.Once
+}
+
+// wheelTimer is a timer scheduled on a timerWheel.
+type wheelTimer struct {
+	w      *timerWheel
+	exp    uint64 // Expiration tick
+	f      func()
+	bucket *list.List
+	elem   *list.Element
+}
+
+// newTimerWheel creates a wheel of the given resolution with the given
+// number of levels and slots per level. The wheel covers tick*size^levels
+// before timers have to be re-cascaded from the top level.
+func newTimerWheel(tick time.Duration, size, levels int) *timerWheel {
+	w := &timerWheel{
+		tick:   tick,
+		size:   uint64(size),
+		levels: make([][]*list.List, levels),
+		start:  time.Now(),
+		wake:   make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+	}
+
+	for i := range w.levels {
+		w.levels[i] = make([]*list.List, size)
+		for j := range w.levels[i] {
+			w.levels[i][j] = list.New()
+		}
+	}
+
+	return w
+}
+
+// Start drives the wheel from a goroutine until Stop is called. The
+// goroutine only ticks while timers are pending.
+func (w *timerWheel) Start() {
+	w.mu.Lock()
+	w.driven = true
+	w.mu.Unlock()
+
+	go w.run()
+}
+
+// Stop stops driving the wheel. Pending timers never fire.
+func (w *timerWheel) Stop() {
+	w.once.Do(func() { close(w.stop) })
+}
+
+func (w *timerWheel) run() {
+	ticker := time.NewTicker(w.tick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if w.advanceTo(uint64(time.Since(w.start) / w.tick)) {
+				continue
+			}
+
+			// Idle until a timer is added
+			ticker.Stop()
+			select {
+			case <-w.wake:
+				ticker.Reset(w.tick)
+			case <-w.stop:
+				return
+			}
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+// AfterFunc calls f in its own goroutine once d has elapsed. The duration is
+// rounded up to the wheel resolution.
+func (w *timerWheel) AfterFunc(d time.Duration, f func()) *wheelTimer {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ticks := uint64((d + w.tick - 1) / w.tick)
+	if ticks == 0 {
+		ticks = 1
+	}
+
+	exp := w.cur + ticks
+	if w.driven {
+		if w.pending == 0 {
+			// The idle wheel didn't turn, catch up with the time
+			w.cur = max(w.cur, uint64(time.Since(w.start)/w.tick))
+		}
+
+		// Count from now rather than from the current tick, which may be
+		// nearly over: the timer would fire up to a tick early. A timer
+		// never expires before the next tick.
+		exp = max(uint64((time.Since(w.start)+d+w.tick-1)/w.tick), w.cur+1)
+	}
+
+	t := &wheelTimer{w: w, exp: exp, f: f}
+	w.add(t)
+
+	w.pending++
+	if w.pending == 1 && w.driven {
+		select {
+		case w.wake <- struct{}{}:
+		default:
+		}
+	}
+
+	return t
+}
+
+// Stop prevents the timer from firing. It returns false if the timer already
+// fired or was stopped.
+func (t *wheelTimer) Stop() bool {
+	t.w.mu.Lock()
+	defer t.w.mu.Unlock()
+
+	if t.bucket == nil {
+		return false
+	}
+
+	t.bucket.Remove(t.elem)
+	t.bucket, t.elem = nil, nil
+	t.w.pending--
+
+	return true
+}
+
+// span returns the number of ticks covered by a slot of the given level.
+func (w *timerWheel) span(level int) uint64 {
+	s := uint64(1)
+	for i := 0; i < level; i++ {
+		s *= w.size
+	}
+	return s
+}
+
+// add inserts the timer in the lowest level covering its expiration. It must
+// be called with the lock held.
+func (w *timerWheel) add(t *wheelTimer) {
+	delta := t.exp - w.cur
+
+	for level := range w.levels {
+		if delta < w.span(level+1) {
+			slot := (t.exp / w.span(level)) % w.size
+			w.insert(t, w.levels[level][slot])
+			return
+		}
+	}
+
+	// Beyond the wheel range, park the timer in the last top level slot to
+	// be cascaded, it is re-inserted from there.
+	top := len(w.levels) - 1
+	slot := (w.cur/w.span(top) + w.size - 1) % w.size
+	w.insert(t, w.levels[top][slot])
+}
+
+func (w *timerWheel) insert(t *wheelTimer, bucket *list.List) {
+	t.bucket = bucket
+	t.elem = bucket.PushBack(t)
+}
+
+// advanceTo turns the wheel up to the given tick, firing expired timers. It
+// returns whether timers are still pending.
+func (w *timerWheel) advanceTo(tick uint64) bool {
+	w.mu.Lock()
+	var expired []func()
+
+	if w.pending == 0 {
+		// No bucket to visit
+		w.cur = max(w.cur, tick)
+	}
+
+	for w.cur < tick {
+		w.cur++
+
+		// Cascade the higher level slots reached by the current tick
+		for level := len(w.levels) - 1; level > 0; level-- {
+			span := w.span(level)
+			if w.cur%span != 0 {
+				continue
+			}
+			w.cascade(w.levels[level][(w.cur/span)%w.size])
+		}
+
+		bucket := w.levels[0][w.cur%w.size]
+		for e := bucket.Front(); e != nil; {
+			next := e.Next()
+			t := e.Value.(*wheelTimer)
+			bucket.Remove(e)
+			t.bucket, t.elem = nil, nil
+
+			if t.exp <= w.cur {
+				expired = append(expired, t.f)
+				w.pending--
+			} else {
+				w.add(t)
+			}
+			e = next
+		}
+	}
+	pending := w.pending > 0
+	w.mu.Unlock()
+
+	for _, f := range expired {
+		go f()
+	}
+	return pending
+}
+
+// cascade re-inserts the timers of a bucket in lower levels.
+func (w *timerWheel) cascade(bucket *list.List) {
+	for e := bucket.Front(); e != nil; {
+		next := e.Next()
+		t := e.Value.(*wheelTimer)
+		bucket.Remove(e)
+		if t.exp <= w.cur {
+			// Expired timers are fired with the level 0 bucket
+			w.insert(t, w.levels[0][w.cur%w.size])
+		} else {
+			w.add(t)
+		}
+		e = next
+	}
+}
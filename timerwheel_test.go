@@ -0,0 +1,153 @@
+package gum
+
+import (
+	"fmt"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTimerWheelFires(t *testing.T) {
+	w := newTimerWheel(time.Millisecond, 8, 3)
+
+	// Delays within the first level, cascaded from higher levels and
+	// beyond the wheel range (8^3 ticks).
+	delays := []int{1, 7, 8, 9, 63, 64, 65, 511, 512, 1500}
+	fired := make(chan int, len(delays))
+
+	for _, d := range delays {
+		d := d
+		w.AfterFunc(time.Duration(d)*time.Millisecond, func() { fired <- d })
+	}
+
+	for tick := uint64(1); tick <= 1500; tick++ {
+		w.advanceTo(tick)
+
+		for _, d := range delays {
+			if uint64(d) != tick {
+				continue
+			}
+			select {
+			case got := <-fired:
+				if got != d {
+					t.Fatalf("tick %d: timer %d fired instead of %d", tick, got, d)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("tick %d: timer %d did not fire", tick, d)
+			}
+		}
+	}
+
+	select {
+	case got := <-fired:
+		t.Fatalf("unexpected timer %d fired", got)
+	default:
+	}
+}
+
+func TestTimerWheelStop(t *testing.T) {
+	w := newTimerWheel(time.Millisecond, 8, 3)
+
+	var fired atomic.Bool
+	timer := w.AfterFunc(100*time.Millisecond, func() { fired.Store(true) })
+
+	if !timer.Stop() {
+		t.Fatal("expected pending timer to be stopped")
+	}
+	if timer.Stop() {
+		t.Fatal("expected second Stop to return false")
+	}
+
+	w.advanceTo(200)
+	time.Sleep(10 * time.Millisecond)
+
+	if fired.Load() {
+		t.Fatal("stopped timer fired")
+	}
+}
+
+func TestTimerWheelRun(t *testing.T) {
+	w := newTimerWheel(time.Millisecond, 64, 4)
+	w.Start()
+	defer w.Stop()
+
+	done := make(chan bool)
+	start := time.Now()
+	w.AfterFunc(20*time.Millisecond, func() { close(done) })
+
+	select {
+	case <-done:
+		if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+			t.Fatalf("timer fired early after %s", elapsed)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timer did not fire")
+	}
+
+	// The idle wheel is woken by the next timer
+	time.Sleep(5 * time.Millisecond)
+	again := make(chan bool)
+	w.AfterFunc(time.Millisecond, func() { close(again) })
+	select {
+	case <-again:
+	case <-time.After(time.Second):
+		t.Fatal("timer added to the idle wheel did not fire")
+	}
+}
+
+func TestTimerWheelDrivenExpiration(t *testing.T) {
+	w := newTimerWheel(time.Hour, 8, 3)
+	w.driven = true
+	w.cur = 5 // Ahead of the time since start
+
+	fired := make(chan bool, 1)
+	w.AfterFunc(0, func() { fired <- true })
+
+	w.advanceTo(6)
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Fatal("expected the timer to fire on the next tick")
+	}
+}
+
+// Schedule and cancel n timers spread over an hour, the common pattern of
+// scheduled units being rescheduled or stopped.
+func BenchmarkTimers(b *testing.B) {
+	for _, n := range []int{1000, 10000, 100000} {
+		delays := make([]time.Duration, n)
+		for i := range delays {
+			delays[i] = time.Duration(rand.Int63n(int64(time.Hour)))
+		}
+
+		b.Run(fmt.Sprintf("wheel/n=%d", n), func(b *testing.B) {
+			w := newTimerWheel(10*time.Millisecond, 256, 4)
+			timers := make([]*wheelTimer, n)
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				for j, d := range delays {
+					timers[j] = w.AfterFunc(d, func() {})
+				}
+				for _, t := range timers {
+					t.Stop()
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("runtime/n=%d", n), func(b *testing.B) {
+			timers := make([]*time.Timer, n)
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				for j, d := range delays {
+					timers[j] = time.AfterFunc(d, func() {})
+				}
+				for _, t := range timers {
+					t.Stop()
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package gum
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// UnitSpec is the serializable description of a unit created by a
+// UnitFactory, e.g. from configuration pushed by a control plane.
+type UnitSpec struct {
+	Name   string            `json:"name"` // Full unit name, see WithName
+	Type   string            `json:"type"` // Selects the factory
+	Labels map[string]string `json:"labels,omitempty"`
+	Config json.RawMessage   `json:"config,omitempty"`
+}
+
+// UnitFactory creates a unit from its spec.
+type UnitFactory func(spec UnitSpec) (WorkUnit, error)
+
+// TopologyStore persists the unit specs of a manager, see
+// WithTopologyStore.
+type TopologyStore interface {
+	LoadTopology() ([]UnitSpec, error)
+	SaveTopology(specs []UnitSpec) error
+}
+
+// WithUnitFactory registers the factory creating the units of the given
+// spec type, see AddSpec.
+func WithUnitFactory(typ string, f UnitFactory) Option {
+	return func(m *Manager) {
+		switch {
+		case typ == "":
+			m.invalid(fmt.Errorf("empty unit factory type"))
+			return
+		case f == nil:
+			m.invalid(fmt.Errorf("nil unit factory %q", typ))
+			return
+		}
+		if m.factories == nil {
+			m.factories = make(map[string]UnitFactory)
+		}
+		m.factories[typ] = f
+	}
+}
+
+// WithTopologyStore persists the unit specs added with AddSpec, so a
+// supervisor managed by a control plane can recreate its units after a
+// restart without the configuration being pushed again. When Run is called
+// without any spec added, the topology is restored from the store.
+// Otherwise the pushed topology replaces the stored one.
+func WithTopologyStore(store TopologyStore) Option {
+	return func(m *Manager) {
+		if store == nil {
+			m.invalid(fmt.Errorf("nil topology store"))
+			return
+		}
+		m.topologyStore = store
+	}
+}
+
+// AddSpec creates a unit with the factory of the spec type and registers it
+// with the manager under the spec name. The unit is started by Run.
+func (m *Manager) AddSpec(spec UnitSpec) error {
+	if spec.Name == "" {
+		return fmt.Errorf("unit spec without name")
+	}
+	blocked, err := m.blockChange("add", spec.Name, func() {
+		if err := m.AddSpec(spec); err != nil {
+			m.warnf(spec.Name, "%s\n", err)
+		}
+	})
+	if blocked {
+		return err
+	}
+	f, ok := m.factories[spec.Type]
+	if !ok {
+		return fmt.Errorf("<%s>: no unit factory for type %q", spec.Name, spec.Type)
+	}
+	for _, s := range m.specs {
+		if s.Name == spec.Name {
+			return fmt.Errorf("<%s>: duplicate unit spec", spec.Name)
+		}
+	}
+
+	unit, err := f(spec)
+	if err != nil {
+		return fmt.Errorf("<%s>: %w", spec.Name, err)
+	}
+
+	opts := []UnitOption{WithName(spec.Name)}
+	if len(spec.Labels) > 0 {
+		opts = append(opts, WithLabels(spec.Labels))
+	}
+	m.AddUnit(unit, spec.Type, opts...)
+	m.specs = append(m.specs, spec)
+
+	return nil
+}
+
+// Topology returns the specs of the units added with AddSpec.
+func (m *Manager) Topology() []UnitSpec {
+	return append([]UnitSpec(nil), m.specs...)
+}
+
+// syncTopology restores the topology from the store if no spec was added,
+// or saves the added specs otherwise.
+func (m *Manager) syncTopology() {
+	if len(m.specs) > 0 {
+		if err := m.topologyStore.SaveTopology(m.Topology()); err != nil {
+			m.warnf("", "Could not save topology: %s\n", err)
+		}
+		return
+	}
+
+	specs, err := m.topologyStore.LoadTopology()
+	if err != nil {
+		m.invalid(fmt.Errorf("load topology: %w", err))
+		return
+	}
+	for _, spec := range specs {
+		if err := m.AddSpec(spec); err != nil {
+			m.invalid(fmt.Errorf("restore topology: %w", err))
+		}
+	}
+	if len(specs) > 0 {
+		m.logf("Restored %d units from the topology store\n", len(specs))
+	}
+}
+
+// fileTopology is a TopologyStore backed by a JSON file.
+type fileTopology struct {
+	path string
+}
+
+// FileTopologyStore returns a TopologyStore saving the topology to the given
+// JSON file. The file is replaced atomically. A missing file is an empty
+// topology.
+func FileTopologyStore(path string) TopologyStore {
+	return fileTopology{path}
+}
+
+type topologyFile struct {
+	Units []UnitSpec `json:"units"`
+}
+
+func (s fileTopology) LoadTopology() ([]UnitSpec, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var f topologyFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("%s: %w", s.path, err)
+	}
+	return f.Units, nil
+}
+
+func (s fileTopology) SaveTopology(specs []UnitSpec) error {
+	data, err := json.MarshalIndent(topologyFile{specs}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.path, data)
+}
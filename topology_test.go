@@ -0,0 +1,96 @@
+package gum
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// tenantWorker is created from a spec by tenantFactory
+type tenantWorker struct {
+	stopWorker
+	tenant string
+}
+
+func tenantFactory(spec UnitSpec) (WorkUnit, error) {
+	var config struct{ Tenant string }
+	if err := json.Unmarshal(spec.Config, &config); err != nil {
+		return nil, err
+	}
+	return &tenantWorker{tenant: config.Tenant}, nil
+}
+
+func TestTopologyRestored(t *testing.T) {
+	store := FileTopologyStore(filepath.Join(t.TempDir(), "topology.json"))
+	spec := UnitSpec{
+		Name:   "tenant-a",
+		Type:   "tenant",
+		Labels: map[string]string{"tier": "gold"},
+		Config: json.RawMessage(`{"Tenant":"a"}`),
+	}
+
+	manager := NewManager(WithUnitFactory("tenant", tenantFactory), WithTopologyStore(store))
+	if err := manager.AddSpec(spec); err != nil {
+		t.Fatal(err)
+	}
+	go manager.Run()
+	waitState(t, manager, 0, Running)
+	manager.Stop()
+	<-manager.Quit
+
+	// Restart without pushing the configuration again
+	manager = NewManager(WithUnitFactory("tenant", tenantFactory), WithTopologyStore(store))
+	go manager.Run()
+	waitState(t, manager, 0, Running)
+	manager.Stop()
+	<-manager.Quit
+
+	w := manager.order[0]
+	if w.name != "tenant-a" || w.Info().Labels["tier"] != "gold" {
+		t.Fatalf("unexpected restored unit %+v", w.Info())
+	}
+	if tenant := w.unit.(*tenantWorker).tenant; tenant != "a" {
+		t.Fatalf("unexpected restored config %q", tenant)
+	}
+	if specs := manager.Topology(); len(specs) != 1 || specs[0].Name != spec.Name {
+		t.Fatalf("unexpected topology %+v", specs)
+	}
+}
+
+func TestAddSpecErrors(t *testing.T) {
+	manager := NewManager(WithUnitFactory("tenant", tenantFactory))
+
+	if err := manager.AddSpec(UnitSpec{Type: "tenant"}); err == nil {
+		t.Error("expected an error for a spec without name")
+	}
+	if err := manager.AddSpec(UnitSpec{Name: "x", Type: "unknown"}); err == nil {
+		t.Error("expected an error for an unknown type")
+	}
+	if err := manager.AddSpec(UnitSpec{Name: "x", Type: "tenant", Config: json.RawMessage(`{`)}); err == nil {
+		t.Error("expected the factory error")
+	}
+
+	spec := UnitSpec{Name: "x", Type: "tenant", Config: json.RawMessage(`{}`)}
+	if err := manager.AddSpec(spec); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.AddSpec(spec); err == nil {
+		t.Error("expected an error for a duplicate spec")
+	}
+}
+
+// brokenStore fails to load the topology
+type brokenStore struct{}
+
+func (brokenStore) LoadTopology() ([]UnitSpec, error) { return nil, errors.New("unavailable") }
+func (brokenStore) SaveTopology([]UnitSpec) error     { return nil }
+
+func TestTopologyLoadError(t *testing.T) {
+	manager := NewManager(WithTopologyStore(brokenStore{}))
+	manager.Run()
+
+	if !errors.Is(manager.Err(), ErrStartup) {
+		t.Fatalf("expected a startup error, got %v", manager.Err())
+	}
+}
@@ -0,0 +1,34 @@
+package gum
+
+import "fmt"
+
+// newTraceID returns a random correlation ID, drawn from the manager random
+// source so it is reproducible with WithSeed.
+func (m *Manager) newTraceID() string {
+	m.randMu.Lock()
+	defer m.randMu.Unlock()
+
+	return fmt.Sprintf("%016x", m.rand.Uint64())
+}
+
+// setTrace sets the correlation ID of the latest restart chain or failure of
+// the unit.
+func (m *Manager) setTrace(w *WorkUnitManager, id string) {
+	m.regMu.Lock()
+	w.traceID = id
+	m.regMu.Unlock()
+}
+
+// unitTrace returns the correlation ID of the unit, empty if none.
+func (m *Manager) unitTrace(w *WorkUnitManager) string {
+	m.regMu.RLock()
+	defer m.regMu.RUnlock()
+
+	return w.traceID
+}
+
+// emitUnit publishes a lifecycle event of the unit, correlated with its
+// latest restart chain or failure.
+func (m *Manager) emitUnit(kind EventKind, w *WorkUnitManager, err error) {
+	m.emitEvent(Event{Kind: kind, Unit: w.name, Err: err, TraceID: m.unitTrace(w)})
+}
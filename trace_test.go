@@ -0,0 +1,78 @@
+package gum
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecycleTrace(t *testing.T) {
+	delay := func(UnitInfo) RestartDecision {
+		return RestartDecision{Delay: time.Millisecond, Reason: "drain"}
+	}
+
+	manager := NewManager(WithRestartHook(delay))
+	newUnit := func() WorkUnit { return &readyWorker{} }
+	manager.AddUnit(newUnit(), "", WithRecycle(5*time.Millisecond, 0, newUnit))
+	sub := manager.Subscribe(WithBufferSize(256))
+
+	go manager.Run()
+	waitState(t, manager, 1, Running)
+	manager.Stop()
+	<-manager.Quit
+	sub.Close()
+
+	traces := map[EventKind]string{}
+	for ev := range sub.Events() {
+		switch {
+		case ev.Kind == EventRestartDecision && traces[ev.Kind] == "":
+			traces[ev.Kind] = ev.TraceID
+		case ev.Kind == EventUnitStarted && ev.TraceID != "" && traces[ev.Kind] == "":
+			traces[ev.Kind] = ev.TraceID
+		case ev.Kind == EventUnitStopping && traces[ev.Kind] == "":
+			traces[ev.Kind] = ev.TraceID
+		}
+	}
+
+	trace := traces[EventRestartDecision]
+	if trace == "" {
+		t.Fatal("restart decision without trace ID")
+	}
+	for _, kind := range []EventKind{EventUnitStarted, EventUnitStopping} {
+		if traces[kind] != trace {
+			t.Errorf("expected %s event with trace %q, got %q", kind, trace, traces[kind])
+		}
+	}
+}
+
+func TestPanicTrace(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&panicWorker{}, "")
+	sub := manager.Subscribe()
+
+	manager.Run()
+	sub.Close()
+
+	traces := map[EventKind]string{}
+	for ev := range sub.Events() {
+		traces[ev.Kind] = ev.TraceID
+	}
+
+	trace := traces[EventUnitPanic]
+	if trace == "" {
+		t.Fatal("panic without trace ID")
+	}
+	if traces[EventUnitDone] != trace {
+		t.Errorf("expected the done event with trace %q, got %q", trace, traces[EventUnitDone])
+	}
+	if traces[EventUnitStarted] != "" {
+		t.Errorf("unexpected trace on the initial start: %q", traces[EventUnitStarted])
+	}
+}
+
+func TestTraceIDSeeded(t *testing.T) {
+	a := NewManager(WithSeed(42)).newTraceID()
+	b := NewManager(WithSeed(42)).newTraceID()
+	if a != b || len(a) != 16 {
+		t.Fatalf("expected reproducible trace IDs, got %q and %q", a, b)
+	}
+}
@@ -0,0 +1,47 @@
+package gum
+
+import (
+	"errors"
+	"fmt"
+)
+
+// invalid records a configuration error of the manager.
+func (m *Manager) invalid(err error) {
+	m.regMu.Lock()
+	m.configErrs = append(m.configErrs, err)
+	m.regMu.Unlock()
+}
+
+// invalid records a configuration error of the unit.
+func (w *WorkUnitManager) invalid(err error) {
+	w.configErrs = append(w.configErrs, err)
+}
+
+// Validate checks the manager options and the registered units. It returns
+// all the problems found at once, joined with errors.Join, or nil. Run
+// refuses to start an invalid configuration.
+func (m *Manager) Validate() error {
+	m.regMu.RLock()
+	defer m.regMu.RUnlock()
+
+	errs := append([]error(nil), m.configErrs...)
+	if m.emptyPolicy == EmptyError && len(m.order) == 0 {
+		errs = append(errs, ErrNoUnits)
+	}
+	seen := make(map[string]bool, len(m.order))
+
+	for _, w := range m.order {
+		if seen[w.name] {
+			errs = append(errs, fmt.Errorf("duplicate unit name <%s>", w.name))
+		}
+		seen[w.name] = true
+
+		for _, err := range w.configErrs {
+			errs = append(errs, fmt.Errorf("unit <%s>: %w", w.name, err))
+		}
+	}
+
+	errs = append(errs, m.validateDeps()...)
+
+	return errors.Join(errs...)
+}
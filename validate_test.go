@@ -0,0 +1,48 @@
+package gum
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateAggregatesErrors(t *testing.T) {
+	manager := NewManager(
+		WithShutdownTimeout(-time.Second),
+		WithStartupConcurrency(-1),
+	)
+	manager.AddUnit(&stopWorker{}, "", WithPanicBudget(1, 0))
+
+	err := manager.Validate()
+	if err == nil {
+		t.Fatal("expected invalid configuration")
+	}
+
+	for _, msg := range []string{
+		"negative shutdown timeout",
+		"negative startup concurrency",
+		"invalid panic budget",
+	} {
+		if !strings.Contains(err.Error(), msg) {
+			t.Errorf("expected error %q to be reported, got: %v", msg, err)
+		}
+	}
+}
+
+func TestRunInvalidConfiguration(t *testing.T) {
+	manager := NewManager(WithShutdownTimeout(-time.Second))
+	manager.AddUnit(&stopWorker{}, "")
+
+	manager.Run()
+
+	if !errors.Is(manager.Err(), ErrStartup) {
+		t.Fatalf("expected startup failure, got %v", manager.Err())
+	}
+	if code := manager.ExitCode(); code != ExitStartup {
+		t.Fatalf("expected startup exit code, got %d", code)
+	}
+	if state := manager.Snapshot().Units[0].State; state != Starting {
+		t.Fatalf("expected unit not to be started, got %s", state)
+	}
+}
@@ -0,0 +1,167 @@
+package gum
+
+import (
+	"context"
+	"fmt"
+)
+
+// UpdateOp is the kind of change of a UnitUpdate.
+type UpdateOp int
+
+const (
+	// UnitAdded units are new to the watch.
+	UnitAdded UpdateOp = iota
+
+	// UnitUpdated units changed state, readiness, error, panics or parking.
+	UnitUpdated
+
+	// UnitDeleted units no longer match the selector of the watch.
+	UnitDeleted
+)
+
+var updateOpNames = [...]string{
+	UnitAdded:   "added",
+	UnitUpdated: "updated",
+	UnitDeleted: "deleted",
+}
+
+func (op UpdateOp) String() string {
+	if op >= 0 && int(op) < len(updateOpNames) {
+		return updateOpNames[op]
+	}
+	return fmt.Sprintf("UpdateOp(%d)", int(op))
+}
+
+// UnitUpdate is a change of a unit delivered by Watch. Unit is the status of
+// the unit after the change, or its last status for deletions.
+type UnitUpdate struct {
+	Op      UpdateOp
+	Version uint64 // Snapshot version of the change
+	Unit    UnitStatus
+}
+
+// Selector selects the units of a watch. A nil Selector selects all units.
+type Selector func(u UnitStatus) bool
+
+// MatchLabels selects the units having all the given labels.
+func MatchLabels(labels map[string]string) Selector {
+	return func(u UnitStatus) bool {
+		for k, v := range labels {
+			if l, ok := u.Labels[k]; !ok || l != v {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Watch delivers the selected units as batches of updates, modeled after
+// Kubernetes informers: the first batch lists the units selected at the
+// time of the call, possibly none, each following batch the changes since
+// the previous one. Changes are coalesced while the receiver is busy, so a
+// slow receiver never misses the latest status of a unit nor slows the
+// manager down. The channel is closed once ctx is done.
+//
+// Units are never removed from the registry: a unit is deleted from the
+// watch once it no longer matches the selector, e.g. a selector of running
+// units. A unit replaced by an instance of the same name, see WithName, is
+// updated.
+func (m *Manager) Watch(ctx context.Context, sel Selector) <-chan []UnitUpdate {
+	c := make(chan []UnitUpdate)
+	go m.protect("watch", func() { m.watch(ctx, sel, c) })
+	return c
+}
+
+func (m *Manager) watch(ctx context.Context, sel Selector, c chan<- []UnitUpdate) {
+	defer close(c)
+
+	var last []UnitStatus
+	for synced := false; ; synced = true {
+		m.regMu.Lock()
+		changed := m.changedC()
+		m.regMu.Unlock()
+
+		snap := m.Snapshot()
+		units := make([]UnitStatus, 0, len(snap.Units))
+		for _, u := range latestUnits(snap.Units) {
+			if sel == nil || sel(u) {
+				units = append(units, u)
+			}
+		}
+
+		updates := diffUnits(last, units, snap.Version)
+		if !synced || len(updates) > 0 {
+			select {
+			case c <- updates:
+			case <-ctx.Done():
+				return
+			}
+		}
+		last = units
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// diffUnits returns the updates turning the units from into the units to,
+// in the order of to followed by the deletions.
+func diffUnits(from, to []UnitStatus, version uint64) []UnitUpdate {
+	prev := make(map[string]UnitStatus, len(from))
+	for _, u := range from {
+		prev[u.Name] = u
+	}
+
+	updates := []UnitUpdate{}
+	for _, u := range to {
+		p, ok := prev[u.Name]
+		delete(prev, u.Name)
+
+		switch {
+		case !ok:
+			updates = append(updates, UnitUpdate{UnitAdded, version, u})
+		case unitChanged(p, u):
+			updates = append(updates, UnitUpdate{UnitUpdated, version, u})
+		}
+	}
+	for _, u := range from {
+		if _, ok := prev[u.Name]; ok {
+			updates = append(updates, UnitUpdate{UnitDeleted, version, u})
+		}
+	}
+	return updates
+}
+
+// unitChanged reports whether the status of a unit changed, ignoring the
+// durations growing with time.
+func unitChanged(a, b UnitStatus) bool {
+	return a.State != b.State ||
+		a.Ready != b.Ready ||
+		a.Err != b.Err ||
+		a.Panics != b.Panics ||
+		a.Restarts != b.Restarts ||
+		!a.StartedAt.Equal(b.StartedAt) ||
+		!a.StoppedAt.Equal(b.StoppedAt) ||
+		!a.ParkedUntil.Equal(b.ParkedUntil)
+}
+
+// touch records a change of the registry or of a unit status: it increments
+// the version and wakes up the watchers. regMu must be held.
+func (m *Manager) touch() {
+	m.version++
+	if m.changed != nil {
+		close(m.changed)
+		m.changed = nil
+	}
+}
+
+// changedC returns a channel closed on the next change. regMu must be held.
+func (m *Manager) changedC() chan struct{} {
+	if m.changed == nil {
+		m.changed = make(chan struct{})
+	}
+	return m.changed
+}
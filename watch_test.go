@@ -0,0 +1,81 @@
+package gum
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// waitUpdate returns the first update of the watch matching ok.
+func waitUpdate(t *testing.T, c <-chan []UnitUpdate, ok func(UnitUpdate) bool) UnitUpdate {
+	t.Helper()
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case updates := <-c:
+			for _, u := range updates {
+				if ok(u) {
+					return u
+				}
+			}
+		case <-timeout:
+			t.Fatal("expected update not delivered")
+		}
+	}
+}
+
+func TestWatch(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&readyWorker{}, "", WithName("api"), WithLabels(map[string]string{"tier": "api"}))
+	manager.AddUnit(&readyWorker{}, "", WithName("db"), WithLabels(map[string]string{"tier": "db"}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := manager.Watch(ctx, MatchLabels(map[string]string{"tier": "api"}))
+
+	initial := <-c
+	if len(initial) != 1 || initial[0].Op != UnitAdded || initial[0].Unit.Name != "api" {
+		t.Fatalf("expected the current state first, got %+v", initial)
+	}
+
+	go manager.Run()
+	ready := waitUpdate(t, c, func(u UnitUpdate) bool { return u.Unit.Ready })
+	if ready.Op != UnitUpdated || ready.Unit.Name != "api" {
+		t.Fatalf("unexpected update %+v", ready)
+	}
+
+	manager.Stop()
+	<-manager.Quit
+	waitUpdate(t, c, func(u UnitUpdate) bool { return u.Unit.State == Stopped })
+
+	cancel()
+	for range c {
+	}
+}
+
+func TestWatchDeleted(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&stopWorker{}, "")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	running := func(u UnitStatus) bool { return u.State == Running }
+	c := manager.Watch(ctx, running)
+
+	if initial := <-c; len(initial) != 0 {
+		t.Fatalf("expected no running unit before Run, got %+v", initial)
+	}
+
+	go manager.Run()
+	added := waitUpdate(t, c, func(u UnitUpdate) bool { return true })
+	if added.Op != UnitAdded {
+		t.Fatalf("expected the unit to be added once running, got %+v", added)
+	}
+
+	manager.Stop()
+	<-manager.Quit
+	deleted := waitUpdate(t, c, func(u UnitUpdate) bool { return true })
+	if deleted.Op != UnitDeleted || deleted.Unit.Name != added.Unit.Name {
+		t.Fatalf("expected the unit to be deleted once stopping, got %+v", deleted)
+	}
+}
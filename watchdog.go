@@ -0,0 +1,94 @@
+package gum
+
+import (
+	"fmt"
+	"runtime/metrics"
+	"time"
+)
+
+// DefaultSampleInterval is the default interval at which the manager samples
+// the runtime metrics, see WithSampleInterval.
+const DefaultSampleInterval = 10 * time.Second
+
+const heapMetric = "/memory/classes/heap/objects:bytes"
+
+// WithMemoryBudget associates a memory budget to the unit. The Go runtime
+// can't attribute memory to goroutines so the budget is compared to the live
+// heap of the whole process: it is a crude guard against leaky workers, best
+// suited to the units dominating the memory usage. Crossing the budget
+// publishes an EventMemoryExceeded event.
+func WithMemoryBudget(bytes uint64) UnitOption {
+	return func(w *WorkUnitManager) {
+		if bytes == 0 {
+			w.invalid(fmt.Errorf("zero memory budget"))
+			return
+		}
+		w.memoryBudget = bytes
+	}
+}
+
+// WithSampleInterval sets the interval at which the runtime metrics are
+// sampled.
+func WithSampleInterval(d time.Duration) Option {
+	return func(m *Manager) {
+		if d <= 0 {
+			m.invalid(fmt.Errorf("invalid sample interval: %s", d))
+			return
+		}
+		m.sampleInterval = d
+	}
+}
+
+// sampler periodically reads the runtime metrics until stop is closed.
+func (m *Manager) sampler(stop <-chan struct{}) {
+	ticker := time.NewTicker(m.sampleInterval)
+	defer ticker.Stop()
+
+	heap := []metrics.Sample{{Name: heapMetric}}
+	pressure := newPressureSampler()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.protect("sampler", func() {
+				metrics.Read(heap)
+				m.checkMemory(heap[0].Value.Uint64())
+
+				p := pressure.sample()
+				m.pressure.Store(&p)
+				for _, hook := range m.pressureHooks {
+					hook := hook // Left running on timeout
+					m.callHook("pressure hook", "", func() { hook(p) })
+				}
+			})
+
+		case <-stop:
+			return
+		}
+	}
+}
+
+// checkMemory compares the live heap to the unit memory budgets.
+func (m *Manager) checkMemory(heap uint64) {
+	var exceeded []*WorkUnitManager
+
+	m.regMu.Lock()
+	for _, w := range m.order {
+		if w.memoryBudget == 0 {
+			continue
+		}
+
+		over := heap > w.memoryBudget
+		if over && !w.overMemory {
+			exceeded = append(exceeded, w)
+		}
+		w.overMemory = over
+	}
+	m.regMu.Unlock()
+
+	for _, w := range exceeded {
+		err := fmt.Errorf("heap of %d bytes exceeds the memory budget of %d bytes", heap, w.memoryBudget)
+		m.warnf(w.name, "<%s> %s\n", w, err)
+		m.emitUnit(EventMemoryExceeded, w, err)
+	}
+}
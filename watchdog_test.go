@@ -0,0 +1,53 @@
+package gum
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestMemoryBudget(t *testing.T) {
+	manager := NewManager(WithSampleInterval(10 * time.Millisecond))
+	manager.ShutdownOn(os.Interrupt)
+	manager.AddUnit(&stopWorker{}, "", WithMemoryBudget(1))
+	sub := manager.Subscribe()
+
+	go manager.Run()
+	defer func() {
+		manager.signalIn <- os.Interrupt
+		<-manager.Quit
+	}()
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case ev := <-sub.Events():
+			if ev.Kind == EventMemoryExceeded {
+				return
+			}
+		case <-timeout:
+			t.Fatal("memory budget was not enforced")
+		}
+	}
+}
+
+func TestMemoryBudgetCrossing(t *testing.T) {
+	manager := NewManager()
+	manager.AddUnit(&stopWorker{}, "", WithMemoryBudget(100))
+	sub := manager.Subscribe()
+
+	for _, heap := range []uint64{50, 150, 200, 80, 120} {
+		manager.checkMemory(heap)
+	}
+	sub.Close()
+
+	exceeded := 0
+	for ev := range sub.Events() {
+		if ev.Kind == EventMemoryExceeded {
+			exceeded++
+		}
+	}
+	if exceeded != 2 {
+		t.Fatalf("expected the budget to be crossed twice, got %d", exceeded)
+	}
+}